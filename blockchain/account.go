@@ -1,6 +1,7 @@
 package blockchain
 
 import (
+	"fmt"
 	"sort"
 
 	"github.com/ellcrys/elld/blockchain/common"
@@ -37,6 +38,16 @@ func (b *Blockchain) GetAccount(address util.String,
 	b.chainLock.RLock()
 	defer b.chainLock.RUnlock()
 	opt := common.GetChainerOp(opts...)
+
+	// Historical queries on the main chain cannot
+	// be answered if the state has been pruned
+	if blockRange := common.GetBlockQueryRangeOp(opts...); opt.Chain == nil &&
+		b.bestChain != nil && blockRange.Max > 0 {
+		if err := b.checkPruneHorizon(blockRange.Max, opts...); err != nil {
+			return nil, err
+		}
+	}
+
 	account, err := b.NewWorldReader().GetAccount(opt.Chain, address, opts...)
 	if err != nil {
 		return nil, err
@@ -44,6 +55,34 @@ func (b *Blockchain) GetAccount(address util.String,
 	return account, nil
 }
 
+// checkPruneHorizon returns ErrStatePruned if the state of
+// the main chain as at the given block has been pruned.
+//
+// NOTE: This method must be called with chain lock held by the caller.
+func (b *Blockchain) checkPruneHorizon(blockNumber uint64, opts ...types.CallOp) error {
+	horizon, err := b.bestChain.store.GetPruneHorizon(opts...)
+	if err != nil {
+		return err
+	}
+	if blockNumber < horizon {
+		return core.ErrStatePruned
+	}
+	return nil
+}
+
+// GetName gets a name record by its name
+func (b *Blockchain) GetName(name util.String,
+	opts ...types.CallOp) (*types.NameRecord, error) {
+	b.chainLock.RLock()
+	defer b.chainLock.RUnlock()
+	opt := common.GetChainerOp(opts...)
+	record, err := b.NewWorldReader().GetName(opt.Chain, name, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return record, nil
+}
+
 // ListAccounts list all accounts
 func (b *Blockchain) ListAccounts(opts ...types.CallOp) ([]types.Account, error) {
 
@@ -75,3 +114,126 @@ func (b *Blockchain) ListTopAccounts(n int, opts ...types.CallOp) ([]types.Accou
 
 	return accounts[:n], nil
 }
+
+// GetAccountProof gets an account as at a given block
+// of the main chain and a proof of its inclusion in the
+// state tree committed to by the block's state root.
+// The tip block is used if blockNumber is zero.
+func (b *Blockchain) GetAccountProof(address util.String, blockNumber uint64,
+	opts ...types.CallOp) (types.Account, types.Block, *common.StateProof, error) {
+	b.chainLock.RLock()
+	defer b.chainLock.RUnlock()
+
+	if b.bestChain == nil {
+		return nil, nil, nil, core.ErrBestChainUnknown
+	}
+
+	block, err := b.bestChain.GetBlock(blockNumber, opts...)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	if err := b.checkPruneHorizon(block.GetNumber(), opts...); err != nil {
+		return nil, nil, nil, err
+	}
+
+	// Restrict the account and the state tree
+	// to objects known as at the block
+	opts = append(opts, &common.OpBlockQueryRange{Max: block.GetNumber()})
+
+	account, err := b.bestChain.GetAccount(address, opts...)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	tree, err := b.stateTreeAt(block, opts...)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	proof, ok := tree.Prove(address.Bytes())
+	if !ok {
+		return nil, nil, nil, core.ErrAccountNotFound
+	}
+
+	return account, block, proof, nil
+}
+
+// stateTreeAt returns the state tree of the main chain
+// as at the given block. The cached tree of the main chain
+// is used if it is as at the block. The trees of older
+// blocks are cached by the hash of the block.
+//
+// NOTE: This method must be called with chain lock held by the caller.
+func (b *Blockchain) stateTreeAt(block types.Block,
+	opts ...types.CallOp) (*common.StateTree, error) {
+
+	if tree := b.bestChain.cachedStateTree(block.GetHash()); tree != nil {
+		return tree, nil
+	}
+
+	if tree, ok := b.stateTrees.Get(block.GetHash()).(*common.StateTree); ok {
+		return tree, nil
+	}
+
+	tree, err := b.bestChain.stateTree(opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	b.stateTrees.Add(block.GetHash(), tree)
+
+	return tree, nil
+}
+
+// GetBalanceHistory gets the balance changes of an account
+// between two blocks (inclusive) of the main chain. The first
+// item is the version of the account known as at the start
+// block, even if it was stored at an earlier block. The tip
+// block is used as the end block if to is zero.
+func (b *Blockchain) GetBalanceHistory(address util.String, from, to uint64,
+	opts ...types.CallOp) ([]*types.AccountVersion, error) {
+	b.chainLock.RLock()
+	defer b.chainLock.RUnlock()
+
+	if b.bestChain == nil {
+		return nil, core.ErrBestChainUnknown
+	}
+
+	tip, err := b.bestChain.GetBlock(to, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	to = tip.GetNumber()
+	if from > to {
+		return nil, fmt.Errorf("start block cannot be greater than end block")
+	}
+
+	if err := b.checkPruneHorizon(from, opts...); err != nil {
+		return nil, err
+	}
+
+	var history = []*types.AccountVersion{}
+
+	// Find the latest version stored at
+	// or before the start block
+	if from > 0 {
+		versions, err := b.bestChain.GetAccountVersions(address,
+			append(opts, &common.OpBlockQueryRange{Max: from})...)
+		if err != nil {
+			return nil, err
+		}
+		if len(versions) > 0 {
+			history = append(history, versions[len(versions)-1])
+		}
+	}
+
+	versions, err := b.bestChain.GetAccountVersions(address,
+		append(opts, &common.OpBlockQueryRange{Min: from + 1, Max: to})...)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(history, versions...), nil
+}
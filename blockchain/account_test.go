@@ -7,6 +7,8 @@ import (
 
 	. "github.com/onsi/ginkgo"
 
+	"github.com/ellcrys/elld/blockchain/common"
+	. "github.com/ellcrys/elld/blockchain/testutil"
 	"github.com/ellcrys/elld/blockchain/txpool"
 	"github.com/ellcrys/elld/config"
 	"github.com/ellcrys/elld/elldb"
@@ -35,7 +37,7 @@ var _ = Describe("Account", func() {
 		err = db.Open(util.RandString(5))
 		Expect(err).To(BeNil())
 
-		bc = New(txpool.New(100), cfg, log)
+		bc = New(txpool.New(100, nil), nil, newEngine(nil), cfg, log)
 		bc.SetDB(db)
 		bc.SetCoinbase(crypto.NewKeyFromIntSeed(1234))
 	})
@@ -148,4 +150,103 @@ var _ = Describe("Account", func() {
 			Expect(result[2].GetBalance().String()).To(Equal("10"))
 		})
 	})
+
+	Describe(".GetAccountProof", func() {
+
+		var sender *crypto.Key
+		var block2 types.Block
+
+		BeforeEach(func() {
+			sender = crypto.NewKeyFromIntSeed(1)
+			block2 = MakeBlock(bc, genesisChain, sender, sender)
+			_, err = bc.ProcessBlock(block2)
+			Expect(err).To(BeNil())
+		})
+
+		It("should return a proof that is valid under the tip block's state root", func() {
+			account, block, proof, err := bc.GetAccountProof(sender.Addr(), 0)
+			Expect(err).To(BeNil())
+			Expect(block.GetHash()).To(Equal(block2.GetHash()))
+			Expect(account.GetNonce()).To(Equal(uint64(1)))
+			Expect(common.VerifyAccountProof(block.GetHeader().GetStateRoot(), account, proof)).To(BeTrue())
+		})
+
+		It("should return the account and proof as at an older block", func() {
+			account, block, proof, err := bc.GetAccountProof(sender.Addr(), 1)
+			Expect(err).To(BeNil())
+			Expect(block.GetHash()).To(Equal(genesisBlock.GetHash()))
+			Expect(account.GetNonce()).To(Equal(uint64(0)))
+			Expect(common.VerifyAccountProof(block.GetHeader().GetStateRoot(), account, proof)).To(BeTrue())
+			Expect(common.VerifyAccountProof(block2.GetHeader().GetStateRoot(), account, proof)).To(BeFalse())
+		})
+
+		It("should use the cached state tree of the main chain for the tip block", func() {
+			_, _, _, err := bc.GetAccountProof(sender.Addr(), 0)
+			Expect(err).To(BeNil())
+			Expect(bc.stateTrees.Len()).To(Equal(0))
+		})
+
+		It("should cache the state tree of an older block", func() {
+			_, _, _, err := bc.GetAccountProof(sender.Addr(), 1)
+			Expect(err).To(BeNil())
+			Expect(bc.stateTrees.Has(genesisBlock.GetHash())).To(BeTrue())
+		})
+
+		It("should return ErrAccountNotFound if account does not exist", func() {
+			_, _, _, err := bc.GetAccountProof("xyz", 0)
+			Expect(err).To(Equal(core.ErrAccountNotFound))
+		})
+
+		It("should return ErrBlockNotFound if block does not exist", func() {
+			_, _, _, err := bc.GetAccountProof(sender.Addr(), 10)
+			Expect(err).To(Equal(core.ErrBlockNotFound))
+		})
+	})
+
+	Describe(".GetBalanceHistory", func() {
+
+		var sender *crypto.Key
+
+		BeforeEach(func() {
+			sender = crypto.NewKeyFromIntSeed(1)
+			block2 := MakeBlock(bc, genesisChain, sender, sender)
+			_, err = bc.ProcessBlock(block2)
+			Expect(err).To(BeNil())
+		})
+
+		It("should return all versions of the account up to the tip", func() {
+			history, err := bc.GetBalanceHistory(sender.Addr(), 1, 0)
+			Expect(err).To(BeNil())
+			Expect(history).To(HaveLen(2))
+			Expect(history[0].BlockNumber).To(Equal(uint64(1)))
+			Expect(history[0].Account.GetNonce()).To(Equal(uint64(0)))
+			Expect(history[1].BlockNumber).To(Equal(uint64(2)))
+			Expect(history[1].Account.GetNonce()).To(Equal(uint64(1)))
+		})
+
+		It("should include the version known as at the start block", func() {
+			history, err := bc.GetBalanceHistory(sender.Addr(), 2, 2)
+			Expect(err).To(BeNil())
+			Expect(history).To(HaveLen(1))
+			Expect(history[0].BlockNumber).To(Equal(uint64(2)))
+		})
+
+		It("should not include versions stored after the end block", func() {
+			history, err := bc.GetBalanceHistory(sender.Addr(), 0, 1)
+			Expect(err).To(BeNil())
+			Expect(history).To(HaveLen(1))
+			Expect(history[0].BlockNumber).To(Equal(uint64(1)))
+		})
+
+		It("should return error if start block is greater than end block", func() {
+			_, err := bc.GetBalanceHistory(sender.Addr(), 2, 1)
+			Expect(err).ToNot(BeNil())
+			Expect(err.Error()).To(Equal("start block cannot be greater than end block"))
+		})
+
+		It("should return ErrBlockNotFound if end block does not exist", func() {
+			_, err := bc.GetBalanceHistory(sender.Addr(), 1, 10)
+			Expect(err).To(Equal(core.ErrBlockNotFound))
+		})
+	})
 })
@@ -2,11 +2,15 @@ package blockchain
 
 import (
 	"fmt"
+	"strconv"
+	"strings"
 
 	"github.com/thoas/go-funk"
 
 	"github.com/mitchellh/mapstructure"
 
+	"github.com/ellcrys/elld/blockchain/common"
+	"github.com/ellcrys/elld/params"
 	"github.com/ellcrys/elld/rpc"
 	"github.com/ellcrys/elld/rpc/jsonrpc"
 	"github.com/ellcrys/elld/types"
@@ -196,20 +200,105 @@ func (b *Blockchain) apiGetBestchain(arg interface{}) *jsonrpc.Response {
 	}))
 }
 
+// apiGetRejectedReOrgs fetches the records of
+// re-organizations rejected for being too deep
+func (b *Blockchain) apiGetRejectedReOrgs(arg interface{}) *jsonrpc.Response {
+	return jsonrpc.Success(b.getRejectedReOrgs())
+}
+
 // apiGetReOrgs fetches the re-organization records
 func (b *Blockchain) apiGetReOrgs(arg interface{}) *jsonrpc.Response {
 	return jsonrpc.Success(b.getReOrgs())
 }
 
+// getBlockByNumberOrHash fetches a block of the main
+// chain using a block number or a hex encoded block hash
+func (b *Blockchain) getBlockByNumberOrHash(val interface{}) (types.Block, *jsonrpc.Response) {
+	b.chainLock.RLock()
+	defer b.chainLock.RUnlock()
+
+	if b.bestChain == nil {
+		return nil, jsonrpc.Error(types.ErrCodeQueryFailed, "best chain not set", nil)
+	}
+
+	var block types.Block
+	var err error
+
+	switch v := val.(type) {
+	case float64:
+		block, err = b.bestChain.GetBlock(uint64(v))
+	case string:
+		blockHash, hErr := util.HexToHash(v)
+		if hErr != nil {
+			return nil, jsonrpc.Error(types.ErrCodeQueryParamError,
+				"invalid block hash", nil)
+		}
+		block, err = b.bestChain.getBlockByHash(blockHash)
+	default:
+		return nil, jsonrpc.Error(types.ErrCodeQueryParamError,
+			"block must be a number or a hash", nil)
+	}
+
+	if err != nil {
+		if err != core.ErrBlockNotFound {
+			return nil, jsonrpc.Error(types.ErrCodeQueryFailed,
+				err.Error(), nil)
+		}
+		return nil, jsonrpc.Error(types.ErrCodeBlockNotFound,
+			err.Error(), nil)
+	}
+
+	return block, nil
+}
+
+// parseAccountQueryArg parses the argument of an account
+// query. The argument is either the address of the account
+// or a map with the address and an optional block number
+// or block hash. If a block is provided, the returned call
+// options restrict the query to the state as at the block.
+func (b *Blockchain) parseAccountQueryArg(arg interface{}) (util.String,
+	[]types.CallOp, *jsonrpc.Response) {
+
+	if address, ok := arg.(string); ok {
+		return util.String(address), nil, nil
+	}
+
+	mArgs, ok := arg.(map[string]interface{})
+	if !ok {
+		return "", nil, jsonrpc.Error(types.ErrCodeUnexpectedArgType,
+			rpc.ErrMethodArgType("String or JSON").Error(), nil)
+	}
+
+	address, ok := mArgs["address"].(string)
+	if !ok {
+		return "", nil, jsonrpc.Error(types.ErrCodeQueryParamError,
+			"address is required", nil)
+	}
+
+	val, ok := mArgs["block"]
+	if !ok {
+		return util.String(address), nil, nil
+	}
+
+	block, errResp := b.getBlockByNumberOrHash(val)
+	if errResp != nil {
+		return "", nil, errResp
+	}
+
+	return util.String(address), []types.CallOp{
+		&common.OpBlockQueryRange{Max: block.GetNumber()},
+	}, nil
+}
+
 // apiGetAccount gets an account
 func (b *Blockchain) apiGetAccount(arg interface{}) *jsonrpc.Response {
-	address, ok := arg.(string)
-	if !ok {
-		return jsonrpc.Error(types.ErrCodeUnexpectedArgType,
-			rpc.ErrMethodArgType("String").Error(), nil)
+
+	address, opts, errResp := b.parseAccountQueryArg(arg)
+	if errResp != nil {
+		return errResp
 	}
 
-	account, err := b.GetAccount(util.String(address))
+	account, err := b.GetAccount(address, opts...)
 	if err != nil {
 		return jsonrpc.Error(types.ErrCodeAccountNotFound,
 			err.Error(), nil)
@@ -218,16 +307,92 @@ func (b *Blockchain) apiGetAccount(arg interface{}) *jsonrpc.Response {
 	return jsonrpc.Success(account)
 }
 
-// apiGetAccount gets the nonce of an account
-func (b *Blockchain) apiGetNonce(arg interface{}) *jsonrpc.Response {
+// apiGetAccountProof gets an account as at a given block
+// and a proof of its inclusion under the block's state root
+func (b *Blockchain) apiGetAccountProof(arg interface{}) *jsonrpc.Response {
 
-	address, ok := arg.(string)
+	mArgs, ok := arg.(map[string]interface{})
+	if !ok {
+		return jsonrpc.Error(types.ErrCodeUnexpectedArgType,
+			rpc.ErrMethodArgType("JSON").Error(), nil)
+	}
+
+	address, ok := mArgs["address"].(string)
+	if !ok {
+		return jsonrpc.Error(types.ErrCodeQueryParamError,
+			"address is required", nil)
+	}
+
+	var blockNum uint64
+	if val, ok := mArgs["blockNumber"].(float64); ok {
+		blockNum = uint64(val)
+	}
+
+	account, block, proof, err := b.GetAccountProof(util.String(address), blockNum)
+	if err != nil {
+		switch err {
+		case core.ErrAccountNotFound:
+			return jsonrpc.Error(types.ErrCodeAccountNotFound, err.Error(), nil)
+		case core.ErrBlockNotFound:
+			return jsonrpc.Error(types.ErrCodeBlockNotFound, err.Error(), nil)
+		default:
+			return jsonrpc.Error(types.ErrCodeQueryFailed, err.Error(), nil)
+		}
+	}
+
+	var siblings = []string{}
+	for _, sibling := range proof.Siblings {
+		siblings = append(siblings, sibling.HexStr())
+	}
+
+	return jsonrpc.Success(util.EncodeForJS(map[string]interface{}{
+		"account":     account,
+		"blockNumber": block.GetNumber(),
+		"blockHash":   block.GetHash(),
+		"stateRoot":   block.GetHeader().GetStateRoot(),
+		"siblings":    siblings,
+	}))
+}
+
+// apiResolveName gets the record of a
+// registered name that has not expired
+func (b *Blockchain) apiResolveName(arg interface{}) *jsonrpc.Response {
+
+	name, ok := arg.(string)
 	if !ok {
 		return jsonrpc.Error(types.ErrCodeUnexpectedArgType,
 			rpc.ErrMethodArgType("String").Error(), nil)
 	}
 
-	account, err := b.GetAccount(util.String(address))
+	record, err := b.GetName(util.String(name))
+	if err != nil {
+		if err != core.ErrNameNotFound {
+			return jsonrpc.Error(types.ErrCodeQueryFailed, err.Error(), nil)
+		}
+		return jsonrpc.Error(types.ErrCodeNameNotFound, err.Error(), nil)
+	}
+
+	tip, err := b.ChainReader().Current()
+	if err != nil {
+		return jsonrpc.Error(types.ErrCodeQueryFailed, err.Error(), nil)
+	}
+
+	if record.IsExpired(tip.GetNumber()) {
+		return jsonrpc.Error(types.ErrCodeNameNotFound, "name has expired", nil)
+	}
+
+	return jsonrpc.Success(record)
+}
+
+// apiGetAccount gets the nonce of an account
+func (b *Blockchain) apiGetNonce(arg interface{}) *jsonrpc.Response {
+
+	address, opts, errResp := b.parseAccountQueryArg(arg)
+	if errResp != nil {
+		return errResp
+	}
+
+	account, err := b.GetAccount(address, opts...)
 	if err != nil {
 		return jsonrpc.Error(types.ErrCodeAccountNotFound,
 			err.Error(), nil)
@@ -239,13 +404,12 @@ func (b *Blockchain) apiGetNonce(arg interface{}) *jsonrpc.Response {
 // apiGetBalance gets the balance of an account
 func (b *Blockchain) apiGetBalance(arg interface{}) *jsonrpc.Response {
 
-	address, ok := arg.(string)
-	if !ok {
-		return jsonrpc.Error(types.ErrCodeUnexpectedArgType,
-			rpc.ErrMethodArgType("String").Error(), nil)
+	address, opts, errResp := b.parseAccountQueryArg(arg)
+	if errResp != nil {
+		return errResp
 	}
 
-	account, err := b.GetAccount(util.String(address))
+	account, err := b.GetAccount(address, opts...)
 	if err != nil {
 		return jsonrpc.Error(types.ErrCodeAccountNotFound,
 			err.Error(), nil)
@@ -254,6 +418,147 @@ func (b *Blockchain) apiGetBalance(arg interface{}) *jsonrpc.Response {
 	return jsonrpc.Success(account.GetBalance())
 }
 
+// apiGetBalanceHistory gets the balance changes
+// of an account between two blocks
+func (b *Blockchain) apiGetBalanceHistory(arg interface{}) *jsonrpc.Response {
+
+	mArgs, ok := arg.(map[string]interface{})
+	if !ok {
+		return jsonrpc.Error(types.ErrCodeUnexpectedArgType,
+			rpc.ErrMethodArgType("JSON").Error(), nil)
+	}
+
+	address, ok := mArgs["address"].(string)
+	if !ok {
+		return jsonrpc.Error(types.ErrCodeQueryParamError,
+			"address is required", nil)
+	}
+
+	var from, to uint64
+	if val, ok := mArgs["from"].(float64); ok {
+		from = uint64(val)
+	}
+	if val, ok := mArgs["to"].(float64); ok {
+		to = uint64(val)
+	}
+
+	history, err := b.GetBalanceHistory(util.String(address), from, to)
+	if err != nil {
+		if err == core.ErrBlockNotFound {
+			return jsonrpc.Error(types.ErrCodeBlockNotFound, err.Error(), nil)
+		}
+		return jsonrpc.Error(types.ErrCodeQueryFailed, err.Error(), nil)
+	}
+
+	var result = []interface{}{}
+	for _, v := range history {
+		result = append(result, util.EncodeForJS(map[string]interface{}{
+			"blockNumber": v.BlockNumber,
+			"balance":     v.Account.GetBalance(),
+		}))
+	}
+
+	return jsonrpc.Success(result)
+}
+
+// parseTxCursor parses a cursor in the
+// format <block number>:<transaction index>
+func parseTxCursor(cursor string) (*types.TxPointer, error) {
+	parts := strings.Split(cursor, ":")
+	if len(parts) != 2 {
+		return nil, fmt.Errorf("invalid cursor")
+	}
+	blockNumber, err := strconv.ParseUint(parts[0], 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid cursor")
+	}
+	index, err := strconv.ParseUint(parts[1], 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid cursor")
+	}
+	return &types.TxPointer{BlockNumber: blockNumber, Index: index}, nil
+}
+
+// apiGetTransactionsByAddress gets a page of transactions
+// of the main chain sent or received by an address
+func (b *Blockchain) apiGetTransactionsByAddress(arg interface{}) *jsonrpc.Response {
+
+	mArgs, ok := arg.(map[string]interface{})
+	if !ok {
+		return jsonrpc.Error(types.ErrCodeUnexpectedArgType,
+			rpc.ErrMethodArgType("Map").Error(), nil)
+	}
+
+	address, ok := mArgs["address"].(string)
+	if !ok {
+		return jsonrpc.Error(types.ErrCodeQueryParamError,
+			"address is required", nil)
+	}
+
+	var cursor *types.TxPointer
+	if val, ok := mArgs["cursor"].(string); ok && val != "" {
+		var err error
+		if cursor, err = parseTxCursor(val); err != nil {
+			return jsonrpc.Error(types.ErrCodeQueryParamError, err.Error(), nil)
+		}
+	}
+
+	var limit = params.DefaultTxsByAddressLimit
+	if val, ok := mArgs["limit"].(float64); ok && val > 0 {
+		limit = int(val)
+	}
+	if limit > params.MaxTxsByAddressLimit {
+		limit = params.MaxTxsByAddressLimit
+	}
+
+	// Transactions are returned newest first
+	// unless the direction is "asc"
+	var ascending bool
+	if val, ok := mArgs["direction"].(string); ok {
+		switch val {
+		case "asc":
+			ascending = true
+		case "desc", "":
+		default:
+			return jsonrpc.Error(types.ErrCodeQueryParamError,
+				"direction must be 'asc' or 'desc'", nil)
+		}
+	}
+
+	// Fetch one more pointer than required
+	// to determine whether there is a next page
+	pointers, err := b.GetTransactionsByAddress(util.String(address),
+		cursor, limit+1, ascending)
+	if err != nil {
+		return jsonrpc.Error(types.ErrCodeQueryFailed, err.Error(), nil)
+	}
+
+	var nextCursor string
+	if len(pointers) > limit {
+		pointers = pointers[:limit]
+		last := pointers[len(pointers)-1]
+		nextCursor = fmt.Sprintf("%d:%d", last.BlockNumber, last.Index)
+	}
+
+	var txs = []interface{}{}
+	for _, pointer := range pointers {
+		tx, err := b.GetTransaction(pointer.Hash)
+		if err != nil {
+			return jsonrpc.Error(types.ErrCodeQueryFailed, err.Error(), nil)
+		}
+		txs = append(txs, map[string]interface{}{
+			"blockNumber": pointer.BlockNumber,
+			"index":       pointer.Index,
+			"tx":          tx,
+		})
+	}
+
+	return jsonrpc.Success(util.EncodeForJS(map[string]interface{}{
+		"transactions": txs,
+		"nextCursor":   nextCursor,
+	}))
+}
+
 // apiGetTransaction gets a transaction by hash
 func (b *Blockchain) apiGetTransaction(arg interface{}) *jsonrpc.Response {
 
@@ -281,6 +586,46 @@ func (b *Blockchain) apiGetTransaction(arg interface{}) *jsonrpc.Response {
 	return jsonrpc.Success(util.EncodeForJS(tx))
 }
 
+// apiGetTransactionProof gets a proof of the inclusion
+// of a transaction in a block of the main chain
+func (b *Blockchain) apiGetTransactionProof(arg interface{}) *jsonrpc.Response {
+
+	txHash, ok := arg.(string)
+	if !ok {
+		return jsonrpc.Error(types.ErrCodeUnexpectedArgType,
+			rpc.ErrMethodArgType("String").Error(), nil)
+	}
+
+	hash, err := util.HexToHash(txHash)
+	if err != nil {
+		return jsonrpc.Error(
+			types.ErrCodeQueryParamError,
+			fmt.Sprintf("invalid transaction id: %s", err.Error()),
+			nil,
+		)
+	}
+
+	proof, err := b.GetTransactionProof(hash)
+	if err != nil {
+		if err == core.ErrTxNotFound {
+			return jsonrpc.Error(types.ErrCodeTransactionNotFound,
+				err.Error(), nil)
+		}
+		return jsonrpc.Error(types.ErrCodeQueryFailed, err.Error(), nil)
+	}
+
+	var branch = []string{}
+	for _, node := range proof.Branch {
+		branch = append(branch, node.HexStr())
+	}
+
+	return jsonrpc.Success(util.EncodeForJS(map[string]interface{}{
+		"blockHash": proof.BlockHash,
+		"index":     proof.Index,
+		"branch":    branch,
+	}))
+}
+
 // apiGetTransactionStatus gets the status of
 // a transaction matching a given hash.
 // Status: 'unknown' - not found, 'pooled' - in
@@ -510,11 +855,31 @@ func (b *Blockchain) APIs() jsonrpc.APISet {
 			Description: "Get a list of re-organization events",
 			Func:        b.apiGetReOrgs,
 		},
+		"getTransactionsByAddress": {
+			Namespace:   types.NamespaceState,
+			Description: "Get a page of transactions sent or received by an address",
+			Func:        b.apiGetTransactionsByAddress,
+		},
+		"getRejectedReOrgs": {
+			Namespace:   types.NamespaceState,
+			Description: "Get a list of re-organizations rejected for exceeding the maximum reorg depth",
+			Func:        b.apiGetRejectedReOrgs,
+		},
 		"getAccount": {
 			Namespace:   types.NamespaceState,
 			Description: "Get an account",
 			Func:        b.apiGetAccount,
 		},
+		"getAccountProof": {
+			Namespace:   types.NamespaceState,
+			Description: "Get an account and a proof of its inclusion in a block's state root",
+			Func:        b.apiGetAccountProof,
+		},
+		"getBalanceHistory": {
+			Namespace:   types.NamespaceState,
+			Description: "Get the balance changes of an account between two blocks",
+			Func:        b.apiGetBalanceHistory,
+		},
 		"listAccounts": {
 			Namespace:   types.NamespaceState,
 			Description: "List all accounts",
@@ -525,6 +890,11 @@ func (b *Blockchain) APIs() jsonrpc.APISet {
 			Description: "List top accounts",
 			Func:        b.apiListTopNAccounts,
 		},
+		"resolveName": {
+			Namespace:   types.NamespaceState,
+			Description: "Get the record of a registered name",
+			Func:        b.apiResolveName,
+		},
 		"getAccountNonce": {
 			Namespace:   types.NamespaceState,
 			Description: "Get the nonce of an account",
@@ -535,6 +905,11 @@ func (b *Blockchain) APIs() jsonrpc.APISet {
 			Description: "Get a transaction by hash",
 			Func:        b.apiGetTransaction,
 		},
+		"getTransactionProof": {
+			Namespace:   types.NamespaceState,
+			Description: "Get a proof of the inclusion of a transaction in a block",
+			Func:        b.apiGetTransactionProof,
+		},
 		"getDifficulty": {
 			Namespace:   types.NamespaceState,
 			Description: "Get difficulty information",
@@ -197,7 +197,8 @@ func (b *Blockchain) Generate(params *types.GenerateBlockParams, opts ...types.C
 
 	// select transactions and compute transaction root
 	if len(params.Transactions) == 0 {
-		selectedTxs, err := b.SelectTransactions(p.MaxBlockTxsSize)
+		maxTxsSize := b.spec.At(block.GetNumber()).MaxBlockTxsSize
+		selectedTxs, err := b.SelectTransactions(maxTxsSize)
 		if err != nil {
 			return nil, err
 		}
@@ -43,7 +43,7 @@ var _ = Describe("Block", func() {
 		sender = crypto.NewKeyFromIntSeed(1)
 		receiver = crypto.NewKeyFromIntSeed(2)
 
-		bc = New(txpool.New(100), cfg, log)
+		bc = New(txpool.New(100, nil), nil, newEngine(nil), cfg, log)
 		bc.SetDB(db)
 		bc.SetCoinbase(crypto.NewKeyFromIntSeed(1234))
 	})
@@ -14,7 +14,6 @@ import (
 	"github.com/ellcrys/elld/blockchain/common"
 	"github.com/ellcrys/elld/config"
 	"github.com/ellcrys/elld/crypto"
-	"github.com/ellcrys/elld/miner/blakimoto"
 	"github.com/ellcrys/elld/types/core"
 	"github.com/ellcrys/elld/util"
 	"github.com/ellcrys/elld/util/logger"
@@ -78,19 +77,44 @@ type BlockValidator struct {
 	// to query transactions and blocks
 	bchain types.Blockchain
 
-	// blakimoto is an instance of PoW implementation
-	blakimoto *blakimoto.Blakimoto
+	// engine is the consensus engine of the network
+	engine types.ConsensusEngine
+
+	// spec contains the consensus parameters of the network
+	spec *params.ChainSpec
+}
+
+// getChainSpec returns the chain specification of a
+// blockchain or the main network's specification
+// if the blockchain is not set
+func getChainSpec(bchain types.Blockchain) *params.ChainSpec {
+	if bchain == nil {
+		return params.DefaultChainSpec()
+	}
+	return bchain.GetChainSpec()
+}
+
+// getConsensusEngine returns the consensus
+// engine of a blockchain or nil if the
+// blockchain is not set
+func getConsensusEngine(bchain types.Blockchain) types.ConsensusEngine {
+	if bchain == nil {
+		return nil
+	}
+	return bchain.GetConsensusEngine()
 }
 
 // NewBlockValidator creates and returns a BlockValidator object
 func NewBlockValidator(block types.Block, txPool types.TxPool,
 	bchain types.Blockchain, cfg *config.EngineConfig,
 	log logger.Logger) *BlockValidator {
+	spec := getChainSpec(bchain)
 	return &BlockValidator{
-		block:     block,
-		txpool:    txPool,
-		bchain:    bchain,
-		blakimoto: blakimoto.ConfiguredBlakimoto(blakimoto.ModeNormal, log),
+		block:  block,
+		txpool: txPool,
+		bchain: bchain,
+		engine: getConsensusEngine(bchain),
+		spec:   spec,
 	}
 }
 
@@ -151,11 +175,17 @@ func (v *BlockValidator) validateHeader(h types.Header) (errs []error) {
 	}
 
 	// Timestamp is required and must not be more than
-	// 15 seconds in the future
+	// 15 seconds in the future. Once ForkFutureBlockTime
+	// is active, the allowed future block time of the
+	// chain specification is used instead.
+	allowedFutureTime := 15 * time.Second
+	if v.spec.IsActive(params.ForkFutureBlockTime, h.GetNumber()) {
+		allowedFutureTime = v.spec.At(h.GetNumber()).GetAllowedFutureBlockTime()
+	}
 	if h.GetTimestamp() == 0 {
 		errs = append(errs, fieldError("timestamp", "timestamp is required"))
 	} else if time.Unix(h.GetTimestamp(), 0).After(time.Now().
-		Add(15 * time.Second).UTC()) {
+		Add(allowedFutureTime).UTC()) {
 		errs = append(errs, fieldError("timestamp",
 			"timestamp is too far in the future"))
 	}
@@ -176,7 +206,7 @@ func (v *BlockValidator) CheckPoW(opts ...types.CallOp) (errs []error) {
 		return errs
 	}
 
-	if err := v.blakimoto.VerifyHeader(v.block.GetHeader(),
+	if err := v.engine.VerifyHeader(v.block.GetHeader(),
 		parentHeader.GetHeader(), true); err != nil {
 		errs = append(errs, fieldError("header", err.Error()))
 	}
@@ -184,10 +214,22 @@ func (v *BlockValidator) CheckPoW(opts ...types.CallOp) (errs []error) {
 	return
 }
 
+// CheckStateRoot checks whether the state root in the
+// header matches the given root. The given root must be
+// the root of the state tree derived from executing the
+// block's transactions on the state of its parent.
+func (v *BlockValidator) CheckStateRoot(root util.Hash) (errs []error) {
+	if !v.block.GetHeader().GetStateRoot().Equal(root) {
+		errs = append(errs, core.ErrBlockStateRootInvalid)
+	}
+	return
+}
+
 // CheckSize checks the size of the blocks
 func (v *BlockValidator) CheckSize() (errs []error) {
 
-	maxBlockSize := params.MaxBlockTxsSize + params.MaxBlockNonTxsSize
+	spec := v.spec.At(v.block.GetNumber())
+	maxBlockSize := spec.MaxBlockTxsSize + spec.MaxBlockNonTxsSize
 	if v.block.GetSize() > maxBlockSize {
 		errs = append(errs, fmt.Errorf("block size exceeded"))
 	}
@@ -325,6 +367,7 @@ func (v *BlockValidator) checkSignature() (errs []error) {
 func (v *BlockValidator) CheckTransactions(opts ...types.CallOp) (errs []error) {
 	txValidator := NewTxsValidator(v.block.GetTransactions(), v.txpool, v.bchain)
 	txValidator.addContext(v.contexts...)
+	txValidator.block = v.block
 	for _, err := range txValidator.Validate(opts...) {
 		errs = append(errs, fmt.Errorf(strings.Replace(err.Error(), "index:", "tx:", -1)))
 	}
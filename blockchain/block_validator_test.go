@@ -23,6 +23,16 @@ import (
 	. "github.com/onsi/gomega"
 )
 
+// rejectingEngine is a consensus
+// engine that rejects all headers
+type rejectingEngine struct {
+	*blakimoto.Blakimoto
+}
+
+func (e *rejectingEngine) VerifyHeader(header, parent types.Header, seal bool) error {
+	return fmt.Errorf("header rejected")
+}
+
 var _ = Describe("BlockValidator", func() {
 
 	var err error
@@ -45,11 +55,11 @@ var _ = Describe("BlockValidator", func() {
 		sender = crypto.NewKeyFromIntSeed(1)
 		receiver = crypto.NewKeyFromIntSeed(2)
 
-		bc = New(txpool.New(100), cfg, log)
+		bc = New(txpool.New(100, nil), nil, newEngine(nil), cfg, log)
 		bc.SetDB(db)
 		bc.SetCoinbase(crypto.NewKeyFromIntSeed(1234))
 
-		bkm = blakimoto.ConfiguredBlakimoto(blakimoto.ModeNormal, log)
+		bkm = blakimoto.ConfiguredBlakimoto(blakimoto.ModeNormal, nil, log)
 	})
 
 	BeforeEach(func() {
@@ -88,6 +98,15 @@ var _ = Describe("BlockValidator", func() {
 			errs := NewBlockValidator(block, nil, nil, cfg, log).CheckSize()
 			Expect(errs).To(ContainElement(fmt.Errorf("block size exceeded")))
 		})
+
+		It("should use the block size limits of the active forks", func() {
+			bc.spec.Forks = []params.Fork{{Name: "fork1", ActivationHeight: 2,
+				Params: []byte(`{"maxBlockNonTxsSize":1,"maxBlockTxsSize":1}`)}}
+			block := MakeBlock(bc, genesisChain, sender, receiver)
+			Expect(block.GetNumber()).To(Equal(uint64(2)))
+			errs := NewBlockValidator(block, nil, bc, cfg, log).CheckSize()
+			Expect(errs).To(ContainElement(fmt.Errorf("block size exceeded")))
+		})
 	})
 
 	Describe(".CheckFields", func() {
@@ -177,6 +196,20 @@ var _ = Describe("BlockValidator", func() {
 				errs := NewBlockValidator(block, nil, nil, cfg, log).CheckFields()
 				Expect(errs).To(ContainElement(fmt.Errorf("field:header.timestamp, error:timestamp is too far in the future")))
 			})
+
+			It("should use the allowed future block time of the chain spec once ForkFutureBlockTime is active", func() {
+				bc.spec.AllowedFutureBlockTime = 60
+				block.GetHeader().SetTimestamp(time.Now().Add(30 * time.Second).Unix())
+				timestampErr := fmt.Errorf("field:header.timestamp, error:timestamp is too far in the future")
+
+				bc.spec.Forks = []params.Fork{{Name: params.ForkFutureBlockTime, ActivationHeight: 3}}
+				errs := NewBlockValidator(block, nil, bc, cfg, log).CheckFields()
+				Expect(errs).To(ContainElement(timestampErr))
+
+				bc.spec.Forks = []params.Fork{{Name: params.ForkFutureBlockTime, ActivationHeight: 2}}
+				errs = NewBlockValidator(block, nil, bc, cfg, log).CheckFields()
+				Expect(errs).ToNot(ContainElement(timestampErr))
+			})
 		})
 
 		Context("Header: when it is valid", func() {
@@ -240,7 +273,7 @@ var _ = Describe("BlockValidator", func() {
 				})
 
 				It("should return no error", func() {
-					tp := txpool.New(1)
+					tp := txpool.New(1, nil)
 					validator := NewBlockValidator(block, tp, bc, cfg, log)
 					validator.setContext(types.ContextBlock)
 					errs := validator.CheckTransactions()
@@ -284,6 +317,62 @@ var _ = Describe("BlockValidator", func() {
 		})
 	})
 
+	Describe("Blakimoto.CalcDifficulty", func() {
+
+		var spec *params.ChainSpec
+		var parent, header *core.Header
+
+		// The block is created twice the
+		// duration limit after its parent
+		BeforeEach(func() {
+			spec = params.DefaultChainSpec()
+			parent = &core.Header{Number: 1, Timestamp: 1000,
+				Difficulty: new(big.Int).Mul(spec.MinimumDifficulty, big.NewInt(100))}
+			header = &core.Header{Number: 2,
+				Timestamp: parent.Timestamp + 2*spec.DurationLimit.Int64()}
+		})
+
+		It("should reduce the difficulty by the adjustment", func() {
+			diff := blakimoto.ConfiguredBlakimoto(blakimoto.ModeNormal, spec, log).CalcDifficulty(header, parent)
+			adjust := new(big.Int).Div(parent.Difficulty, spec.DifficultyBoundDivisor)
+			Expect(diff.Cmp(new(big.Int).Sub(parent.Difficulty, adjust))).To(Equal(0))
+		})
+
+		It("should reduce the difficulty by twice the adjustment once ForkFastDifficultyDrop is active", func() {
+			spec.Forks = []params.Fork{{Name: params.ForkFastDifficultyDrop, ActivationHeight: 2}}
+			diff := blakimoto.ConfiguredBlakimoto(blakimoto.ModeNormal, spec, log).CalcDifficulty(header, parent)
+			adjust := new(big.Int).Div(parent.Difficulty, spec.DifficultyBoundDivisor)
+			Expect(diff.Cmp(new(big.Int).Sub(parent.Difficulty, new(big.Int).Mul(adjust, big.NewInt(2))))).To(Equal(0))
+		})
+	})
+
+	Describe("Blakimoto.Seal", func() {
+
+		var block types.Block
+
+		BeforeEach(func() {
+			block = MakeBlock(bc, genesisChain, sender, receiver)
+			header := block.GetHeader().Copy()
+			header.SetDifficulty(new(big.Int).SetInt64(1000))
+			block = block.ReplaceHeader(header)
+		})
+
+		It("should return the block with a nonce that seals it", func() {
+			sealed, err := bkm.Seal(block, nil)
+			Expect(err).To(BeNil())
+			Expect(sealed.GetHeader().GetHashNoNonce()).To(Equal(block.GetHeader().GetHashNoNonce()))
+			Expect(bkm.VerifySeal(sealed.GetHeader())).To(BeNil())
+		})
+
+		It("should return nil if stopped before the block is sealed", func() {
+			stop := make(chan struct{})
+			close(stop)
+			sealed, err := bkm.Seal(block, stop)
+			Expect(err).To(BeNil())
+			Expect(sealed).To(BeNil())
+		})
+	})
+
 	Describe(".checkPow", func() {
 		var block types.Block
 
@@ -348,6 +437,20 @@ var _ = Describe("BlockValidator", func() {
 				Expect(errs).To(ContainElement(fmt.Errorf("field:header, error:invalid proof-of-work")))
 			})
 		})
+
+		Context("when the blockchain has a custom consensus engine", func() {
+
+			BeforeEach(func() {
+				block = MakeBlock(bc, genesisChain, sender, receiver)
+				bc.SetConsensusEngine(&rejectingEngine{bkm})
+			})
+
+			It("should verify the header using the engine", func() {
+				errs := NewBlockValidator(block, nil, bc, cfg, log).CheckPoW()
+				Expect(errs).To(HaveLen(1))
+				Expect(errs).To(ContainElement(fmt.Errorf("field:header, error:header rejected")))
+			})
+		})
 	})
 
 	Describe(".checkSignature", func() {
@@ -370,6 +473,21 @@ var _ = Describe("BlockValidator", func() {
 		})
 	})
 
+	Describe(".CheckStateRoot", func() {
+		It("should return error when the state root does not match", func() {
+			errs := NewBlockValidator(genesisBlock, nil, bc, cfg, log).CheckStateRoot(util.StrToHash("incorrect"))
+			Expect(errs).To(HaveLen(1))
+			Expect(errs[0]).To(Equal(core.ErrBlockStateRootInvalid))
+		})
+
+		It("should return no error when the state root matches", func() {
+			tree, err := genesisChain.NewStateTree()
+			Expect(err).To(BeNil())
+			errs := NewBlockValidator(genesisBlock, nil, bc, cfg, log).CheckStateRoot(tree.Root())
+			Expect(errs).To(BeEmpty())
+		})
+	})
+
 	Describe(".checkAllocs", func() {
 
 		When("block is a genesis block", func() {
@@ -5,6 +5,7 @@ package blockchain
 import (
 	"encoding/json"
 	"fmt"
+	"sort"
 	"sync"
 	"time"
 
@@ -18,6 +19,7 @@ import (
 	"github.com/ellcrys/elld/blockchain/common"
 	"github.com/ellcrys/elld/config"
 	"github.com/ellcrys/elld/elldb"
+	"github.com/ellcrys/elld/params"
 	"github.com/ellcrys/elld/types"
 	"github.com/ellcrys/elld/types/core"
 	"github.com/ellcrys/elld/util"
@@ -29,8 +31,20 @@ const (
 	// MaxOrphanBlocksCacheSize is the number of blocks we can keep in the orphan block cache
 	MaxOrphanBlocksCacheSize = 500
 
+	// MaxOrphanBlocksPerPeer is the number of blocks from
+	// a single peer we can keep in the orphan block cache
+	MaxOrphanBlocksPerPeer = 50
+
+	// OrphanBlockTTL is the duration an orphan block
+	// is kept in the orphan block cache
+	OrphanBlockTTL = time.Hour
+
 	// MaxRejectedBlocksCacheSize is the number of blocks we can keep in the rejected block cache
 	MaxRejectedBlocksCacheSize = 100
+
+	// MaxStateTreesCacheSize is the number of state trees
+	// of historic blocks to keep in the cache
+	MaxStateTreesCacheSize = 16
 )
 
 // Blockchain represents the Ellcrys blockchain. It provides
@@ -55,6 +69,12 @@ type Blockchain struct {
 	// cfg is the client configuration
 	cfg *config.EngineConfig
 
+	// spec contains the consensus parameters of the network
+	spec *params.ChainSpec
+
+	// engine is the consensus engine of the network
+	engine types.ConsensusEngine
+
 	// log is used for logging output
 	log logger.Logger
 
@@ -69,12 +89,16 @@ type Blockchain struct {
 	chains map[util.String]*Chain
 
 	// orphanBlocks stores blocks whose parents are unknown
-	orphanBlocks *cache.Cache
+	orphanBlocks *OrphanPool
 
 	// rejectedBlocks stores collection of blocks that have been deemed invalid.
 	// This allows us to quickly learn and discard blocks that are found here.
 	rejectedBlocks *cache.Cache
 
+	// stateTrees stores the state trees of historic
+	// blocks of the main chain by their block hash
+	stateTrees *cache.Cache
+
 	// txPool contains all transactions awaiting inclusion in a block
 	txPool types.TxPool
 
@@ -84,19 +108,38 @@ type Blockchain struct {
 
 	// reOrgActive indicates an ongoing reorganization
 	reOrgActive bool
+
+	// checkpoints maps block numbers to the hash of
+	// the blocks the main chain must include
+	checkpoints map[uint64]util.Hash
+
+	// rejectedBranches stores the IDs of branches whose
+	// reorganization was rejected for being too deep
+	rejectedBranches map[util.String]struct{}
 }
 
-// New creates a Blockchain instance.
-func New(txPool types.TxPool, cfg *config.EngineConfig, log logger.Logger) *Blockchain {
+// New creates a Blockchain instance that validates
+// blocks using the given consensus engine. The main
+// network's chain specification is used if spec is nil.
+func New(txPool types.TxPool, spec *params.ChainSpec, engine types.ConsensusEngine,
+	cfg *config.EngineConfig, log logger.Logger) *Blockchain {
 	bc := new(Blockchain)
 	bc.txPool = txPool
 	bc.log = log
 	bc.cfg = cfg
+	bc.spec = spec
+	if bc.spec == nil {
+		bc.spec = params.DefaultChainSpec()
+	}
+	bc.engine = engine
 	bc.chainLock = &sync.RWMutex{}
 	bc.processLock = &sync.Mutex{}
 	bc.chains = make(map[util.String]*Chain)
-	bc.orphanBlocks = cache.NewCache(MaxOrphanBlocksCacheSize)
+	bc.rejectedBranches = make(map[util.String]struct{})
+	bc.orphanBlocks = NewOrphanPool(MaxOrphanBlocksCacheSize,
+		MaxOrphanBlocksPerPeer, OrphanBlockTTL)
 	bc.rejectedBlocks = cache.NewCache(MaxRejectedBlocksCacheSize)
+	bc.stateTrees = cache.NewCache(MaxStateTreesCacheSize)
 	bc.eventEmitter = &emitter.Emitter{}
 	return bc
 }
@@ -152,6 +195,11 @@ func (b *Blockchain) Up() error {
 		return fmt.Errorf("db has not been initialized")
 	}
 
+	// Load the checkpoints of the network
+	if err := b.loadCheckpoints(); err != nil {
+		return err
+	}
+
 	// Get known chains
 	chains, err := b.getChains()
 	if err != nil {
@@ -186,6 +234,10 @@ func (b *Blockchain) Up() error {
 			return fmt.Errorf("failed to save genesis chain: %s", err)
 		}
 
+		if err := b.updateAddressIndex(gChain); err != nil {
+			return err
+		}
+
 		// Process the genesis block.
 		if _, err := b.maybeAcceptBlock(gBlock, gChain); err != nil {
 			return fmt.Errorf("genesis block error: %s", err)
@@ -204,13 +256,24 @@ func (b *Blockchain) Up() error {
 		}
 	}
 
+	// Index the transactions of the chains
+	// by address, if required
+	for _, chain := range b.chains {
+		if err := b.updateAddressIndex(chain); err != nil {
+			return err
+		}
+	}
+
 	if numChains := len(chains); numChains > 0 {
 		b.log.Info("Known branches have been loaded", "NumBranches", numChains)
 	}
 
+	// Load the branches whose reorganization was rejected
+	b.loadRejectedBranches()
+
 	// Using the best chain rule, we mush select the best chain
 	// and set it as the current bestChain.
-	err = b.decideBestChain()
+	_, err = b.decideBestChain()
 	if err != nil {
 		return fmt.Errorf("failed to determine best chain: %s", err)
 	}
@@ -234,6 +297,21 @@ func (b *Blockchain) GetTxPool() types.TxPool {
 	return b.txPool
 }
 
+// GetChainSpec gets the chain specification
+func (b *Blockchain) GetChainSpec() *params.ChainSpec {
+	return b.spec
+}
+
+// SetConsensusEngine sets the consensus engine
+func (b *Blockchain) SetConsensusEngine(engine types.ConsensusEngine) {
+	b.engine = engine
+}
+
+// GetConsensusEngine gets the consensus engine
+func (b *Blockchain) GetConsensusEngine() types.ConsensusEngine {
+	return b.engine
+}
+
 // OrphanBlocks returns a cache reader for orphan blocks
 func (b *Blockchain) OrphanBlocks() types.CacheReader {
 	return b.orphanBlocks
@@ -383,9 +461,7 @@ func (b *Blockchain) isRejected(block types.Block) bool {
 func (b *Blockchain) addOrphanBlock(block types.Block) {
 	b.chainLock.Lock()
 	defer b.chainLock.Unlock()
-	// Insert the block to the cache with a 1 hour expiration
-	b.orphanBlocks.AddWithExp(block.GetHash().HexStr(),
-		block, time.Now().Add(time.Hour))
+	b.orphanBlocks.Add(block)
 	b.log.Debug("Added block to orphan cache",
 		"BlockNo", block.GetNumber(),
 		"CacheSize", b.orphanBlocks.Len())
@@ -563,6 +639,70 @@ func (b *Blockchain) GetTransaction(hash util.Hash,
 	return tx, nil
 }
 
+// GetTransactionsByAddress gets pointers to transactions
+// of the main chain sent or received by an address.
+// It returns an error if address indexing is disabled.
+func (b *Blockchain) GetTransactionsByAddress(address util.String,
+	cursor *types.TxPointer, limit int, ascending bool,
+	opts ...types.CallOp) ([]*types.TxPointer, error) {
+	b.chainLock.RLock()
+	defer b.chainLock.RUnlock()
+
+	if b.cfg.Chain == nil || !b.cfg.Chain.IndexAddressTxs {
+		return nil, fmt.Errorf("address index is disabled")
+	}
+
+	if b.bestChain == nil {
+		return nil, core.ErrBestChainUnknown
+	}
+
+	return b.bestChain.GetAddressTxPointers(address, cursor, limit, ascending, opts...)
+}
+
+// updateAddressIndex builds the address index of a chain
+// if address indexing is enabled, so that the blocks stored
+// while it was disabled are indexed. Otherwise, the index is
+// deleted since the blocks stored from now are not indexed.
+func (b *Blockchain) updateAddressIndex(chain *Chain) error {
+	if b.cfg.Chain == nil || !b.cfg.Chain.IndexAddressTxs {
+		if err := chain.store.ResetAddressIndex(); err != nil {
+			return fmt.Errorf("failed to reset address index: %s", err)
+		}
+		return nil
+	}
+
+	if err := chain.store.BuildAddressIndex(); err != nil {
+		return fmt.Errorf("failed to build address index: %s", err)
+	}
+
+	return nil
+}
+
+// GetTransactionProof creates a proof of the inclusion
+// of a transaction in a block of the main chain
+func (b *Blockchain) GetTransactionProof(hash util.Hash,
+	opts ...types.CallOp) (*core.TxProof, error) {
+	b.chainLock.RLock()
+	defer b.chainLock.RUnlock()
+
+	if b.bestChain == nil {
+		return nil, core.ErrBestChainUnknown
+	}
+
+	block, err := b.bestChain.GetTransactionBlock(hash, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, tx := range block.GetTransactions() {
+		if tx.GetHash().Equal(hash) {
+			return core.NewTxProof(block, i)
+		}
+	}
+
+	return nil, core.ErrTxNotFound
+}
+
 // ChainReader creates a chain reader for best/main chain
 func (b *Blockchain) ChainReader() types.ChainReaderFactory {
 	return NewChainReader(b.bestChain)
@@ -643,16 +783,31 @@ func (b *Blockchain) GetLocators() ([]util.Hash, error) {
 }
 
 // SelectTransactions collects transactions from the head
-// of the pool up to the specified maxSize.
+// of the pool up to the specified maxSize. Time-locked
+// transactions that cannot be included in the next block
+// of the main chain are left in the pool.
 func (b *Blockchain) SelectTransactions(maxSize int64) (selectedTxs []types.Transaction,
 	err error) {
 	b.chainLock.RLock()
 	b.chainLock.RUnlock()
 
+	// Get the tip of the main chain. The
+	// selected transactions will be included
+	// in the block after it.
+	var tip types.Block
+	if b.bestChain != nil {
+		if tip, err = b.bestChain.GetBlock(0); err != nil {
+			if err != core.ErrBlockNotFound {
+				return nil, err
+			}
+			err = nil
+		}
+	}
+
 	totalSelectedTxsSize := int64(0)
 	cache := []types.Transaction{}
 	nonces := make(map[util.String]uint64)
-	for b.txPool.Size() > 0 {
+	for b.txPool.Container().Size() > 0 {
 		// Get a transaction from the top of
 		// the pool
 		tx := b.txPool.Container().First()
@@ -672,6 +827,15 @@ func (b *Blockchain) SelectTransactions(maxSize int64) (selectedTxs []types.Tran
 			continue
 		}
 
+		// Leave the transaction in the pool
+		// if it has not reached its lock time
+		if tx.GetLockTime() > 0 && tip != nil {
+			if checkLockTime(b, tx, tip.GetNumber()+1, tip.GetHash()) != nil {
+				cache = append(cache, tx)
+				continue
+			}
+		}
+
 		// Check the current nonce value from
 		// the cache and ensure the transaction's
 		// nonce matches the expected/next nonce value.
@@ -708,7 +872,13 @@ func (b *Blockchain) SelectTransactions(maxSize int64) (selectedTxs []types.Tran
 		cache = append(cache, tx)
 	}
 
-	// put the cached transactions back to the pool
+	// put the cached transactions back to the pool.
+	// They are added in nonce order so that none is
+	// queued behind a nonce gap that is about to be
+	// filled by another cached transaction.
+	sort.SliceStable(cache, func(i, j int) bool {
+		return cache[i].GetNonce() < cache[j].GetNonce()
+	})
 	for _, tx := range cache {
 		_ = b.txPool.Put(tx)
 	}
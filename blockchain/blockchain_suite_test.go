@@ -8,7 +8,9 @@ import (
 
 	. "github.com/onsi/ginkgo"
 
+	"github.com/ellcrys/elld/miner/blakimoto"
 	"github.com/ellcrys/elld/params"
+	"github.com/ellcrys/elld/types"
 	"github.com/ellcrys/elld/util/logger"
 	"github.com/shopspring/decimal"
 )
@@ -21,6 +23,12 @@ func init() {
 	params.MinimumDifficulty = new(big.Int).SetInt64(100000)
 }
 
+// newEngine creates the consensus engine of a
+// blockchain using the given chain specification
+func newEngine(spec *params.ChainSpec) types.ConsensusEngine {
+	return blakimoto.ConfiguredBlakimoto(blakimoto.ModeNormal, spec, log)
+}
+
 func TestBlockchainSuite(t *testing.T) {
 	RegisterFailHandler(Fail)
 	RunSpecs(t, "Blockchain Suite")
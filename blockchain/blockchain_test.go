@@ -9,6 +9,7 @@ import (
 	"github.com/ellcrys/elld/config"
 	"github.com/ellcrys/elld/crypto"
 	"github.com/ellcrys/elld/elldb"
+	"github.com/ellcrys/elld/params"
 	"github.com/ellcrys/elld/testutil"
 	"github.com/ellcrys/elld/types"
 	"github.com/ellcrys/elld/types/core"
@@ -39,7 +40,7 @@ var _ = Describe("IntegrationBlockchain", func() {
 		sender = crypto.NewKeyFromIntSeed(1)
 		receiver = crypto.NewKeyFromIntSeed(2)
 
-		bc = New(txpool.New(100), cfg, log)
+		bc = New(txpool.New(100, nil), nil, newEngine(nil), cfg, log)
 		bc.SetDB(db)
 		bc.SetCoinbase(crypto.NewKeyFromIntSeed(1234))
 	})
@@ -355,6 +356,42 @@ var _ = Describe("IntegrationBlockchain", func() {
 			})
 		})
 
+		Describe(".GetTransactionProof", func() {
+			var block types.Block
+			var chain *Chain
+
+			BeforeEach(func() {
+				chain = NewChain("chain_a", db, cfg, log)
+				block = MakeBlock(bc, genesisChain, sender, receiver)
+				err := chain.append(block)
+				Expect(err).To(BeNil())
+				err = chain.PutTransactions(block.GetTransactions(), block.GetNumber())
+				Expect(err).To(BeNil())
+			})
+
+			It("should return err = 'best chain unknown' if the best chain has not been decided", func() {
+				bc.bestChain = nil
+				_, err := bc.GetTransactionProof(block.GetTransactions()[0].GetHash())
+				Expect(err).To(Equal(core.ErrBestChainUnknown))
+			})
+
+			It("should return ErrTxNotFound if transaction does not exist", func() {
+				bc.bestChain = chain
+				_, err := bc.GetTransactionProof(util.Hash{1, 2, 3})
+				Expect(err).To(Equal(core.ErrTxNotFound))
+			})
+
+			It("should return a proof that verifies against the block header", func() {
+				bc.bestChain = chain
+				tx := block.GetTransactions()[0]
+				proof, err := bc.GetTransactionProof(tx.GetHash())
+				Expect(err).To(BeNil())
+				Expect(proof.BlockHash).To(Equal(block.GetHash()))
+				Expect(proof.Index).To(Equal(0))
+				Expect(core.VerifyTxProof(block.GetHeader(), tx, proof)).To(BeTrue())
+			})
+		})
+
 		Describe(".GetChainReaderByHash", func() {
 			It("should get chain reader of the genesis block", func() {
 				reader := bc.GetChainReaderByHash(genesisBlock.GetHash())
@@ -461,6 +498,38 @@ var _ = Describe("IntegrationBlockchain", func() {
 			var tx, tx2, tx3 *core.Transaction
 			var txs []types.Transaction
 
+			Context("pool has time-locked transactions and the main chain tip is block 1", func() {
+				BeforeEach(func() {
+					tp = bc.txPool
+					tx = core.NewTx(core.TxTypeBalance, 1, util.String(sender.Addr()), sender, "0.1", "0.001", time.Now().Unix())
+					tx.LockTime = 2
+					tx.Hash = tx.ComputeHash()
+					Expect(tp.Put(tx)).To(BeNil())
+
+					tx2 = core.NewTx(core.TxTypeBalance, 1, util.String(receiver.Addr()), receiver, "0.1", "0.001", time.Now().Unix())
+					tx2.LockTime = 3
+					tx2.Hash = tx2.ComputeHash()
+					Expect(tp.Put(tx2)).To(BeNil())
+
+					tx3 = core.NewTx(core.TxTypeBalance, 2, util.String(sender.Addr()), sender, "0.1", "0.001", time.Now().Unix())
+					tx3.LockTime = uint64(time.Now().Add(time.Hour).Unix())
+					tx3.Hash = tx3.ComputeHash()
+					Expect(tp.Put(tx3)).To(BeNil())
+
+					txs, err = bc.SelectTransactions(tx.GetSizeNoFee() + tx2.GetSizeNoFee() + tx3.GetSizeNoFee())
+					Expect(err).To(BeNil())
+				})
+
+				It("should only return the transaction that can be included in block 2", func() {
+					Expect(txs).To(HaveLen(1))
+					Expect(txs[0]).To(Equal(tx))
+				})
+
+				Specify("container should still contain the locked transactions", func() {
+					Expect(tp.Size()).To(Equal(int64(3)))
+				})
+			})
+
 			Context("pool has 1 transaction and account nonce = 0", func() {
 				Context("transaction nonce is 2", func() {
 					BeforeEach(func() {
@@ -612,24 +681,24 @@ var _ = Describe("IntegrationBlockchain", func() {
 						err := tp.Put(tx)
 						Expect(err).To(BeNil())
 
-						tx2 = core.NewTx(core.TxTypeBalance, 1, util.String(sender.Addr()), sender, "0.2", "0.001", time.Now().Unix())
+						tx2 = core.NewTx(core.TxTypeBalance, 1, util.String(sender.Addr()), sender, "0.2", "0.01", time.Now().Unix())
 						tx2.Hash = tx2.ComputeHash()
 						err = tp.Put(tx2)
-						Expect(tp.Container().Size()).To(Equal(int64(2)))
 						Expect(err).To(BeNil())
 
-						Expect(tp.Size()).To(Equal(int64(2)))
+						Expect(tp.Size()).To(Equal(int64(1)))
 						maxSize := tx.GetSizeNoFee() + tx2.GetSizeNoFee()
 						txs, err = bc.SelectTransactions(maxSize)
 						Expect(err).To(BeNil())
 					})
 
-					It("should return 1 transactions", func() {
+					It("should return the replacement transaction", func() {
 						Expect(txs).To(HaveLen(1))
+						Expect(txs[0]).To(Equal(tx2))
 					})
 
-					Specify("container should contain 2 transactions since selected txs go back in the pool", func() {
-						Expect(tp.Size()).To(Equal(int64(2)))
+					Specify("container should contain 1 transaction since selected txs go back in the pool", func() {
+						Expect(tp.Size()).To(Equal(int64(1)))
 					})
 				})
 			})
@@ -694,7 +763,7 @@ var _ = Describe("UnitBlock", func() {
 		err = db.Open(util.RandString(5))
 		Expect(err).To(BeNil())
 
-		bc = New(txpool.New(100), cfg, log)
+		bc = New(txpool.New(100, nil), nil, newEngine(nil), cfg, log)
 		bc.SetDB(db)
 		bc.SetCoinbase(crypto.NewKeyFromIntSeed(1234))
 	})
@@ -727,6 +796,28 @@ var _ = Describe("UnitBlock", func() {
 		})
 	})
 
+	Describe(".GetChainSpec", func() {
+		It("should return the main network spec when no spec is provided", func() {
+			Expect(bc.GetChainSpec()).To(Equal(params.DefaultChainSpec()))
+		})
+
+		It("should return the spec provided to the blockchain", func() {
+			spec := params.DefaultChainSpec()
+			spec.TxTTL = 2
+			bc2 := New(txpool.New(100, spec), spec, newEngine(spec), cfg, log)
+			Expect(bc2.GetChainSpec().TxTTL).To(Equal(2))
+			Expect(bc.GetChainSpec().TxTTL).To(Equal(params.TxTTL))
+		})
+	})
+
+	Describe(".GetConsensusEngine", func() {
+		It("should return the engine provided to the blockchain", func() {
+			engine := newEngine(nil)
+			bc2 := New(txpool.New(100, nil), nil, engine, cfg, log)
+			Expect(bc2.GetConsensusEngine()).To(BeIdenticalTo(engine))
+		})
+	})
+
 	Describe(".IsMainChain", func() {
 		It("should return false when the given chain is not the main chain", func() {
 			ch := NewChain("c1", db, cfg, log)
@@ -48,6 +48,14 @@ type Chain struct {
 
 	// log is used for logging
 	log logger.Logger
+
+	// tree is the cached state tree of the chain
+	// as at the block whose hash is treeTip
+	tree    *common.StateTree
+	treeTip util.Hash
+
+	// treeLock synchronizes access to the cached state tree
+	treeLock sync.Mutex
 }
 
 // NewChain creates an instance of a chain. It will create metadata object for the
@@ -57,7 +65,11 @@ func NewChain(id util.String, db elldb.DB,
 	chain := new(Chain)
 	chain.id = id
 	chain.cfg = cfg
-	chain.store = store.New(db, chain.id)
+	chainStore := store.New(db, chain.id)
+	if cfg != nil && cfg.Chain != nil {
+		chainStore.SetAddressIndex(cfg.Chain.IndexAddressTxs)
+	}
+	chain.store = chainStore
 	chain.chainLock = &sync.RWMutex{}
 	chain.log = log
 	chain.parentChain = nil
@@ -313,9 +325,24 @@ func (c *Chain) GetAccount(address util.String, opts ...types.CallOp) (types.Acc
 	return c.store.GetAccount(address, opts...)
 }
 
+// GetAccountVersions gets the versions of an account
+func (c *Chain) GetAccountVersions(address util.String, opts ...types.CallOp) ([]*types.AccountVersion, error) {
+	return c.store.GetAccountVersions(address, opts...)
+}
+
 // GetAccounts gets all accounts
 func (c *Chain) GetAccounts(opts ...types.CallOp) ([]types.Account, error) {
-	return c.store.GetAccounts()
+	return c.store.GetAccounts(opts...)
+}
+
+// GetName gets a name record
+func (c *Chain) GetName(name util.String, opts ...types.CallOp) (*types.NameRecord, error) {
+	return c.store.GetName(name, opts...)
+}
+
+// GetNames gets all name records
+func (c *Chain) GetNames(opts ...types.CallOp) ([]*types.NameRecord, error) {
+	return c.store.GetNames(opts...)
 }
 
 // append adds a block to the tail of the chain. It returns
@@ -361,40 +388,151 @@ func (c *Chain) append(candidate types.Block, opts ...types.CallOp) error {
 	return c.store.PutBlock(candidate, txOp)
 }
 
-// NewStateTree creates a new tree seeded with the
-// state root of the chain's tip block. For chains
-// with no block (new chains), the state root of
-// their parent block is used.
-func (c *Chain) NewStateTree(opts ...types.CallOp) (types.Tree, error) {
+// NewStateTree creates a state tree containing the
+// latest version of every account known to the chain.
+// For branches, the accounts of the parent chains are
+// included up to the block the branch forked from.
+//
+// The tree is only built from the stored accounts when the
+// cached tree is not as at the current tip. The caller
+// gets a copy that can be changed without affecting the cache.
+func (c *Chain) NewStateTree(opts ...types.CallOp) (types.StateTree, error) {
 
-	var prevRoot util.Hash
+	// Trees restricted to a block range are not cached
+	if common.GetBlockQueryRangeOp(opts...).Max > 0 {
+		return c.stateTree(opts...)
+	}
 
-	// Get the state root of the block at the tip.
-	// If no block was found, it means the chain is empty.
-	// In this case, if the chain has a parent block,
-	// we use the parent block stateRoot.
 	tipHeader, err := c.Current(opts...)
 	if err != nil {
 		if err != core.ErrBlockNotFound {
 			return nil, err
 		}
-		if c.parentBlock != nil {
-			prevRoot = c.parentBlock.GetHeader().GetStateRoot()
+		return c.stateTree(opts...)
+	}
+
+	tip, err := c.GetBlock(tipHeader.GetNumber(), opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	c.treeLock.Lock()
+	defer c.treeLock.Unlock()
+
+	if c.tree != nil && c.treeTip.Equal(tip.GetHash()) {
+		return c.tree.Copy(), nil
+	}
+
+	tree, err := c.stateTree(opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	c.tree, c.treeTip = tree, tip.GetHash()
+
+	return tree.Copy(), nil
+}
+
+// cachedStateTree returns a copy of the cached state
+// tree if it is as at the block with the given hash.
+// It returns nil if it is not.
+func (c *Chain) cachedStateTree(blockHash util.Hash) *common.StateTree {
+	c.treeLock.Lock()
+	defer c.treeLock.Unlock()
+
+	if c.tree == nil || !c.treeTip.Equal(blockHash) {
+		return nil
+	}
+
+	return c.tree.Copy()
+}
+
+// updateStateTree applies the state objects derived from
+// executing a block to the cached state tree. The cache is
+// dropped if it is not as at the parent of the block, so
+// that it is rebuilt when next requested.
+func (c *Chain) updateStateTree(block types.Block, stateObjs []*common.StateObject) {
+	c.treeLock.Lock()
+	defer c.treeLock.Unlock()
+
+	if c.tree == nil || !c.treeTip.Equal(block.GetHeader().GetParentHash()) {
+		c.tree = nil
+		return
+	}
+
+	for _, so := range stateObjs {
+		c.tree.Set(so.TreeKey, so.Value)
+	}
+	c.treeTip = block.GetHash()
+}
+
+// stateTree is like NewStateTree but returns the concrete
+// tree. If a block range option is provided, only accounts
+// and name records as at the maximum block number in the
+// range are included.
+func (c *Chain) stateTree(opts ...types.CallOp) (*common.StateTree, error) {
+
+	tree := common.NewStateTree()
+
+	// maxChainHeight is the maximum block number of
+	// account objects to consider in the current chain.
+	// It starts as the maximum block number requested by
+	// the caller and is reduced to the parent block number
+	// as we move to the parent chains.
+	maxChainHeight := common.GetBlockQueryRangeOp(opts...).Max
+
+	// The block range is set per chain, so we
+	// remove any block range option provided
+	var baseOpts []types.CallOp
+	for _, op := range opts {
+		if _, ok := op.(*common.OpBlockQueryRange); !ok {
+			baseOpts = append(baseOpts, op)
+		}
+	}
+
+	for chain := c; chain != nil; chain = chain.GetParent(baseOpts...) {
+
+		// make a copy of the call options
+		optsCopy := append([]types.CallOp{}, baseOpts...)
+		if maxChainHeight > 0 {
+			optsCopy = append(optsCopy, &common.OpBlockQueryRange{Max: maxChainHeight})
 		}
-	} else {
-		prevRoot = tipHeader.GetStateRoot()
+
+		accounts, err := chain.GetAccounts(optsCopy...)
 		if err != nil {
-			return nil, fmt.Errorf("failed to decode chain tip state root")
+			return nil, fmt.Errorf("failed to get accounts: %s", err)
+		}
+
+		// Accounts found in a chain take precedence over
+		// accounts of the same address in its ancestors
+		for _, account := range accounts {
+			key := account.GetAddress().Bytes()
+			if _, ok := tree.Get(key); ok {
+				continue
+			}
+			tree.Set(key, util.ObjectToBytes(account))
+		}
+
+		names, err := chain.GetNames(optsCopy...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get names: %s", err)
+		}
+
+		for _, record := range names {
+			key := common.MakeTreeKeyName(record.Name.Bytes())
+			if _, ok := tree.Get(key); ok {
+				continue
+			}
+			tree.Set(key, util.ObjectToBytes(record))
 		}
-	}
 
-	// Create the new tree and seed it by adding
-	// state root of the previous block. No need
-	// to do this if at this point we  have not
-	// determined the previous state root.
-	tree := common.NewTree()
-	if !prevRoot.IsEmpty() {
-		tree.Add(common.TreeItem(prevRoot.Bytes()))
+		if chain.info == nil {
+			break
+		}
+		parentBlockNumber := chain.info.GetParentBlockNumber()
+		if maxChainHeight == 0 || parentBlockNumber < maxChainHeight {
+			maxChainHeight = parentBlockNumber
+		}
 	}
 
 	return tree, nil
@@ -414,6 +552,18 @@ func (c *Chain) GetTransaction(hash util.Hash, opts ...types.CallOp) (types.Tran
 	return tx, nil
 }
 
+// GetAddressTxPointers gets pointers to transactions
+// sent or received by an address
+func (c *Chain) GetAddressTxPointers(address util.String, cursor *types.TxPointer,
+	limit int, ascending bool, opts ...types.CallOp) ([]*types.TxPointer, error) {
+	return c.store.GetAddressTxPointers(address, cursor, limit, ascending, opts...)
+}
+
+// GetTransactionBlock gets the block that includes a transaction
+func (c *Chain) GetTransactionBlock(hash util.Hash, opts ...types.CallOp) (types.Block, error) {
+	return c.store.GetTransactionBlock(hash, opts...)
+}
+
 func (c *Chain) String() string {
 	parent := ""
 	if p := c.GetParent(); p != nil {
@@ -577,6 +727,24 @@ func (c *Chain) removeBlock(number uint64, opts ...types.CallOp) (types.Block, e
 		return nil, fmt.Errorf("failed to delete accounts: %s", err)
 	}
 
+	// Find name records associated with the block and delete them
+	err = nil
+	namesKey := common.MakeQueryKeyNames(c.id.Bytes())
+	txOp.Tx.Iterate(namesKey, false, func(kv *elldb.KVObject) bool {
+		if util.DecodeNumber(kv.Key) == number {
+			if err = txOp.Tx.DeleteByPrefix(kv.GetKey()); err != nil {
+				return true
+			}
+		}
+		return false
+	})
+	if err != nil {
+		if len(opts) == 0 {
+			txOp.Finishable().Rollback()
+		}
+		return nil, fmt.Errorf("failed to delete names: %s", err)
+	}
+
 	// Find indexed transactions associated with this block and delete them
 	err = nil
 	txsKey := common.MakeQueryKeyTransactions(c.id.Bytes())
@@ -596,6 +764,20 @@ func (c *Chain) removeBlock(number uint64, opts ...types.CallOp) (types.Block, e
 		return nil, fmt.Errorf("failed to delete transactions: %s", err)
 	}
 
+	// Delete the address index entries of the block's transactions
+	for i, tx := range block.GetTransactions() {
+		for _, address := range store.TxAddresses(tx) {
+			key := common.MakeKeyAddressTransaction(c.id.Bytes(),
+				address.Bytes(), number, uint64(i))
+			if err = c.store.Delete(key, txOp); err != nil {
+				if len(opts) == 0 {
+					txOp.Finishable().Rollback()
+				}
+				return nil, fmt.Errorf("failed to delete address index: %s", err)
+			}
+		}
+	}
+
 	if len(opts) == 0 {
 		return block, txOp.Finishable().Commit()
 	}
@@ -6,6 +6,7 @@ import (
 	. "github.com/onsi/ginkgo"
 
 	"github.com/ellcrys/elld/blockchain/common"
+	"github.com/ellcrys/elld/blockchain/store"
 	. "github.com/ellcrys/elld/blockchain/testutil"
 	"github.com/ellcrys/elld/blockchain/txpool"
 	"github.com/ellcrys/elld/config"
@@ -41,7 +42,7 @@ var _ = Describe("Chain", func() {
 		receiver = crypto.NewKeyFromIntSeed(2)
 
 		coinbase = sender
-		bc = New(txpool.New(100), cfg, log)
+		bc = New(txpool.New(100, nil), nil, newEngine(nil), cfg, log)
 		bc.SetDB(db)
 		bc.SetCoinbase(coinbase)
 	})
@@ -153,72 +154,82 @@ var _ = Describe("Chain", func() {
 
 	Describe(".NewStateTree", func() {
 
-		var emptyTreeRoot, chainRoot util.Hash
-		var item1, item2 common.TreeItem
-
-		BeforeEach(func() {
-			item1 = common.TreeItem([]byte("age"))
-			item2 = common.TreeItem([]byte("gender"))
-
-			emptyTreeRoot = util.Hash{
-				0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
-				0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
-			}
-
-			chainRoot = util.Hash{
-				0xad, 0xfa, 0xd6, 0x2b, 0x04, 0x74, 0x82, 0x37, 0x4b, 0x59, 0x48, 0x77, 0x82, 0x50, 0x96, 0xf2,
-				0x1d, 0xdf, 0xfa, 0x06, 0x78, 0x22, 0x35, 0xb5, 0xb1, 0x87, 0x43, 0x5d, 0xd5, 0x2b, 0xf1, 0x24,
-			}
+		Context("with empty chain", func() {
+			It("should return a tree with an empty root", func() {
+				emptyChain := NewChain("my_chain", db, cfg, log)
+				tree, err := emptyChain.NewStateTree()
+				Expect(err).To(BeNil())
+				Expect(tree.Root()).To(Equal(util.EmptyHash))
+			})
 		})
 
-		Context("with empty chain", func() {
+		Context("with a non-empty chain", func() {
 
-			var tree types.Tree
+			var tree types.StateTree
 			var err error
 
 			BeforeEach(func() {
-				emptyChain := NewChain("my_chain", db, cfg, log)
-				tree, err = emptyChain.NewStateTree()
+				tree, err = genesisChain.NewStateTree()
 				Expect(err).To(BeNil())
-				Expect(tree.Root()).To(Equal(emptyTreeRoot))
 			})
 
-			It("Should add 2 items and return expected root", func() {
-				tree.Add(item1)
-				tree.Add(item2)
-				err = tree.Build()
+			Specify("tree root must equal the state root of the tip block", func() {
+				Expect(tree.Root()).To(Equal(genesisBlock.GetHeader().GetStateRoot()))
+			})
+
+			Specify("tree must include the accounts of the chain", func() {
+				recipient := genesisBlock.GetTransactions()[0].GetTo()
+				account, err := genesisChain.GetAccount(recipient)
 				Expect(err).To(BeNil())
-				Expect(tree.Root()).To(Equal(chainRoot))
-				Expect(tree.GetItems()).To(HaveLen(2))
+				value, ok := tree.Get(recipient.Bytes())
+				Expect(ok).To(BeTrue())
+				Expect(value).To(Equal(util.BytesToHash(util.Blake2b256(util.ObjectToBytes(account)))))
+			})
+
+			Specify("must derive new state root after setting a new value", func() {
+				initialRoot := tree.Root()
+				tree.Set([]byte("addr"), []byte("value"))
+				Expect(tree.Root()).NotTo(Equal(initialRoot))
+			})
+
+			Specify("changes to the tree must not affect the cached tree", func() {
+				tree.Set([]byte("addr"), []byte("value"))
+				tree2, err := genesisChain.NewStateTree()
+				Expect(err).To(BeNil())
+				Expect(tree2.Root()).To(Equal(genesisBlock.GetHeader().GetStateRoot()))
+			})
+
+			Specify("the cached tree must be updated with the state of a new block", func() {
+				block2 := MakeBlock(bc, genesisChain, sender, receiver)
+				_, err = bc.ProcessBlock(block2)
+				Expect(err).To(BeNil())
+				Expect(genesisChain.treeTip).To(Equal(block2.GetHash()))
+
+				tree2, err := genesisChain.NewStateTree()
+				Expect(err).To(BeNil())
+				Expect(tree2.Root()).To(Equal(block2.GetHeader().GetStateRoot()))
 			})
 		})
 
-		Context("with a non-empty chain", func() {
+		Context("with a branch", func() {
 
-			var tree types.Tree
-			var initialRoot util.Hash
-			var err error
+			var branch *Chain
 
 			BeforeEach(func() {
-				tree, err = genesisChain.NewStateTree()
+				block2 := MakeBlock(bc, genesisChain, sender, receiver)
+				_, err = bc.ProcessBlock(block2)
 				Expect(err).To(BeNil())
-				Expect(tree.Root()).To(Equal(emptyTreeRoot))
-				initialRoot = tree.Root()
-			})
 
-			Specify("tree must be seeded with the state root of the tip block", func() {
-				curItems := tree.GetItems()
-				Expect(curItems).To(HaveLen(1))
-				Expect(curItems[0]).To(Equal(common.TreeItem(genesisBlock.GetHeader().GetStateRoot().Bytes())))
+				branch = NewChain("branch", db, cfg, log)
+				branch.parentBlock = genesisBlock
+				branch.info.ParentChainID = genesisChain.GetID()
+				branch.info.ParentBlockNumber = genesisBlock.GetNumber()
 			})
 
-			Specify("must derive new state root after adding items to tree", func() {
-				tree.Add(item1)
-				tree.Add(item2)
-				err = tree.Build()
+			It("should only include parent chain accounts up to the parent block", func() {
+				tree, err := branch.NewStateTree()
 				Expect(err).To(BeNil())
-				newRoot := tree.Root()
-				Expect(newRoot).NotTo(Equal(initialRoot))
+				Expect(tree.Root()).To(Equal(genesisBlock.GetHeader().GetStateRoot()))
 			})
 		})
 	})
@@ -430,6 +441,29 @@ var _ = Describe("Chain", func() {
 				Expect(result).To(BeEmpty())
 			})
 		})
+
+		Context("when address indexing is enabled", func() {
+
+			BeforeEach(func() {
+				genesisChain.store.(*store.ChainStore).SetAddressIndex(true)
+				block2 = MakeBlock(bc, genesisChain, sender, receiver)
+				_, err := bc.ProcessBlock(block2)
+				Expect(err).To(BeNil())
+
+				pointers, err := genesisChain.GetAddressTxPointers(sender.Addr(), nil, 0, true)
+				Expect(err).To(BeNil())
+				Expect(pointers).ToNot(BeEmpty())
+			})
+
+			Specify("address index entries of the block's transactions must be deleted", func() {
+				_, err := genesisChain.removeBlock(block2.GetNumber())
+				Expect(err).To(BeNil())
+
+				pointers, err := genesisChain.GetAddressTxPointers(sender.Addr(), nil, 0, true)
+				Expect(err).To(BeNil())
+				Expect(pointers).To(BeEmpty())
+			})
+		})
 	})
 
 	Describe(".GetMinedBlocks", func() {
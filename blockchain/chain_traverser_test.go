@@ -30,7 +30,7 @@ var _ = Describe("ChainTraverser", func() {
 		err = db.Open(util.RandString(5))
 		Expect(err).To(BeNil())
 
-		bc = New(txpool.New(100), cfg, log)
+		bc = New(txpool.New(100, nil), nil, newEngine(nil), cfg, log)
 		bc.SetDB(db)
 		bc.SetCoinbase(crypto.NewKeyFromIntSeed(1234))
 	})
@@ -0,0 +1,117 @@
+package blockchain
+
+import (
+	"fmt"
+
+	"github.com/ellcrys/elld/config"
+	"github.com/ellcrys/elld/types"
+	"github.com/ellcrys/elld/types/core"
+	"github.com/ellcrys/elld/util"
+)
+
+// loadCheckpoints loads the built-in checkpoints of
+// the current network version and the checkpoints
+// set in the chain configuration. Checkpoints in the
+// configuration override built-in checkpoints.
+func (b *Blockchain) loadCheckpoints() error {
+	checkpoints := make(map[uint64]util.Hash)
+
+	var sources []map[uint64]string
+	if config.Versions != nil {
+		sources = append(sources, config.Checkpoints[config.Versions.Protocol])
+	}
+	if b.cfg != nil && b.cfg.Chain != nil {
+		sources = append(sources, b.cfg.Chain.Checkpoints)
+	}
+
+	for _, source := range sources {
+		for number, hexHash := range source {
+			hash, err := util.HexToHash(hexHash)
+			if err != nil {
+				return fmt.Errorf("invalid checkpoint hash at block %d: %s", number, err)
+			}
+			checkpoints[number] = hash
+		}
+	}
+
+	b.chainLock.Lock()
+	b.checkpoints = checkpoints
+	b.chainLock.Unlock()
+
+	return nil
+}
+
+// GetCheckpoint returns the checkpoint hash
+// of a block number, if one exists.
+func (b *Blockchain) GetCheckpoint(number uint64) (util.Hash, bool) {
+	b.chainLock.RLock()
+	defer b.chainLock.RUnlock()
+	hash, ok := b.checkpoints[number]
+	return hash, ok
+}
+
+// CheckCheckpoint checks whether a block with the
+// given number and hash conflicts with a checkpoint.
+// It returns core.ErrCheckpointMismatch if a checkpoint
+// exists at the block number and its hash is not the
+// same as the given hash.
+func (b *Blockchain) CheckCheckpoint(number uint64, hash util.Hash) error {
+	cpHash, ok := b.GetCheckpoint(number)
+	if ok && !cpHash.Equal(hash) {
+		return core.ErrCheckpointMismatch
+	}
+	return nil
+}
+
+// conflictsWithCheckpoint checks whether a branch
+// forks off the main chain before a checkpoint that
+// has been reached by the main chain. Since the main
+// chain always includes the checkpoint blocks, such
+// a branch can never include them.
+//
+// NOTE: This method must be called with chain lock held by the caller.
+func (b *Blockchain) conflictsWithCheckpoint(chain *Chain,
+	opts ...types.CallOp) (bool, error) {
+
+	if len(b.checkpoints) == 0 || b.bestChain == nil ||
+		chain.GetID().Equal(b.bestChain.GetID()) {
+		return false, nil
+	}
+
+	parentBlock := chain.GetParentBlock()
+	if parentBlock == nil {
+		return false, nil
+	}
+
+	return b.forksBeforeCheckpoint(parentBlock.GetNumber(), opts...)
+}
+
+// forksBeforeCheckpoint checks whether a branch
+// whose parent block is at the given height forks off
+// the main chain before a checkpoint that has been
+// reached by the main chain.
+//
+// NOTE: This method must be called with chain lock held by the caller.
+func (b *Blockchain) forksBeforeCheckpoint(parentNumber uint64,
+	opts ...types.CallOp) (bool, error) {
+
+	if len(b.checkpoints) == 0 || b.bestChain == nil {
+		return false, nil
+	}
+
+	tip, err := b.bestChain.Current(opts...)
+	if err != nil {
+		if err == core.ErrBlockNotFound {
+			return false, nil
+		}
+		return false, err
+	}
+
+	for number := range b.checkpoints {
+		if parentNumber < number && number <= tip.GetNumber() {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
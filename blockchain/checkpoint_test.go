@@ -0,0 +1,166 @@
+package blockchain
+
+import (
+	"math/big"
+	"os"
+	"time"
+
+	. "github.com/ellcrys/elld/blockchain/testutil"
+	"github.com/ellcrys/elld/blockchain/txpool"
+	"github.com/ellcrys/elld/config"
+	"github.com/ellcrys/elld/crypto"
+	"github.com/ellcrys/elld/elldb"
+	"github.com/ellcrys/elld/testutil"
+	"github.com/ellcrys/elld/types"
+	"github.com/ellcrys/elld/types/core"
+	"github.com/ellcrys/elld/util"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Checkpoint", func() {
+
+	var err error
+	var bc *Blockchain
+	var cfg *config.EngineConfig
+	var db elldb.DB
+	var genesisBlock types.Block
+	var sender, receiver *crypto.Key
+
+	BeforeEach(func() {
+		cfg, err = testutil.SetTestCfg()
+		Expect(err).To(BeNil())
+
+		db = elldb.NewDB(cfg.NetDataDir())
+		err = db.Open(util.RandString(5))
+		Expect(err).To(BeNil())
+
+		sender = crypto.NewKeyFromIntSeed(1)
+		receiver = crypto.NewKeyFromIntSeed(2)
+
+		bc = New(txpool.New(100, nil), nil, newEngine(nil), cfg, log)
+		bc.SetDB(db)
+		bc.SetCoinbase(crypto.NewKeyFromIntSeed(1234))
+
+		genesisBlock, err = LoadBlockFromFile("genesis-test.json")
+		Expect(err).To(BeNil())
+		bc.SetGenesisBlock(genesisBlock)
+	})
+
+	AfterEach(func() {
+		db.Close()
+		err = os.RemoveAll(cfg.DataDir())
+		Expect(err).To(BeNil())
+	})
+
+	makeBlock := func(nonce uint64, timestamp int64) types.Block {
+		return MakeTestBlock(bc, bc.bestChain, &types.GenerateBlockParams{
+			Transactions: []types.Transaction{
+				core.NewTx(core.TxTypeBalance, nonce, receiver.Addr(), sender, "1", "2.5", time.Now().UnixNano()),
+			},
+			Creator:           sender,
+			Nonce:             util.EncodeNonce(1),
+			Difficulty:        new(big.Int).SetInt64(131072),
+			OverrideTimestamp: timestamp,
+		})
+	}
+
+	Describe(".loadCheckpoints", func() {
+		It("should load checkpoints in the chain configuration", func() {
+			cfg.Chain = &config.ChainConfig{Checkpoints: map[uint64]string{
+				1: genesisBlock.GetHash().HexStr(),
+			}}
+			err = bc.Up()
+			Expect(err).To(BeNil())
+
+			hash, ok := bc.GetCheckpoint(1)
+			Expect(ok).To(BeTrue())
+			Expect(hash).To(Equal(genesisBlock.GetHash()))
+		})
+
+		It("should return error if a checkpoint hash is invalid", func() {
+			cfg.Chain = &config.ChainConfig{Checkpoints: map[uint64]string{1: "0xinvalid"}}
+			err = bc.Up()
+			Expect(err).ToNot(BeNil())
+			Expect(err.Error()).To(ContainSubstring("invalid checkpoint hash at block 1"))
+		})
+	})
+
+	Describe(".CheckCheckpoint", func() {
+		BeforeEach(func() {
+			bc.checkpoints = map[uint64]util.Hash{1: genesisBlock.GetHash()}
+		})
+
+		It("should return nil if no checkpoint exists at the block number", func() {
+			Expect(bc.CheckCheckpoint(2, util.StrToHash("abc"))).To(BeNil())
+		})
+
+		It("should return nil if the hash matches the checkpoint", func() {
+			Expect(bc.CheckCheckpoint(1, genesisBlock.GetHash())).To(BeNil())
+		})
+
+		It("should return ErrCheckpointMismatch if the hash does not match the checkpoint", func() {
+			Expect(bc.CheckCheckpoint(1, util.StrToHash("abc"))).To(Equal(core.ErrCheckpointMismatch))
+		})
+	})
+
+	Context("with blocks 2 and 3 on the main chain", func() {
+
+		var block2, block2b, block3 types.Block
+
+		BeforeEach(func() {
+			err = bc.Up()
+			Expect(err).To(BeNil())
+
+			block2 = makeBlock(1, time.Now().Unix()+1)
+			block2b = makeBlock(1, time.Now().Unix()+2)
+			_, err = bc.ProcessBlock(block2)
+			Expect(err).To(BeNil())
+
+			block3 = makeBlock(2, time.Now().Unix()+3)
+			_, err = bc.ProcessBlock(block3)
+			Expect(err).To(BeNil())
+		})
+
+		Describe(".ProcessBlock", func() {
+			It("should reject a block that does not match the checkpoint at its height", func() {
+				bc.checkpoints = map[uint64]util.Hash{2: block2.GetHash()}
+				_, err = bc.ProcessBlock(block2b)
+				Expect(err).To(Equal(core.ErrCheckpointMismatch))
+				Expect(bc.isRejected(block2b)).To(BeTrue())
+			})
+
+			It("should reject a block of a branch that forks off before a checkpoint", func() {
+				bc.checkpoints = map[uint64]util.Hash{3: block3.GetHash()}
+				_, err = bc.ProcessBlock(block2b)
+				Expect(err).To(Equal(core.ErrCheckpointConflict))
+				Expect(bc.isRejected(block2b)).To(BeTrue())
+			})
+
+			It("should accept a block of a branch that forks off after all checkpoints", func() {
+				bc.checkpoints = map[uint64]util.Hash{1: genesisBlock.GetHash()}
+				_, err = bc.ProcessBlock(block2b)
+				Expect(err).To(BeNil())
+			})
+		})
+
+		Describe(".chooseBestChain", func() {
+			It("should ignore a branch that conflicts with a checkpoint", func() {
+				_, err = bc.ProcessBlock(block2b)
+				Expect(err).To(BeNil())
+				Expect(bc.chains).To(HaveLen(2))
+
+				bc.checkpoints = map[uint64]util.Hash{3: block3.GetHash()}
+				for _, chain := range bc.chains {
+					conflicts, err := bc.conflictsWithCheckpoint(chain)
+					Expect(err).To(BeNil())
+					Expect(conflicts).To(Equal(chain.GetID() != bc.bestChain.GetID()))
+				}
+
+				best, err := bc.chooseBestChain()
+				Expect(err).To(BeNil())
+				Expect(best.GetID()).To(Equal(bc.bestChain.GetID()))
+			})
+		})
+	})
+})
@@ -105,6 +105,18 @@ func GetChainerOp(opts ...types.CallOp) *OpChainer {
 	return &OpChainer{}
 }
 
+// GetBlockNumberOp is a convenience method to get the
+// value of OpBlockNumber. It returns 0 if not found.
+func GetBlockNumberOp(opts ...types.CallOp) uint64 {
+	for _, op := range opts {
+		switch _op := op.(type) {
+		case OpBlockNumber:
+			return uint64(_op)
+		}
+	}
+	return 0
+}
+
 // ExecAllowed is a convenience method to get
 // the value of OpAllowExec
 func ExecAllowed(opts ...types.CallOp) bool {
@@ -74,6 +74,27 @@ var _ = Describe("Common", func() {
 		})
 	})
 
+	Describe("OpTx.AfterCommit", func() {
+
+		It("should call the function only when the transaction is committed", func() {
+			txOp := GetTxOp(db)
+			called := false
+			txOp.AfterCommit(func() { called = true })
+			Expect(txOp.SetFinishable(false).Commit()).To(BeNil())
+			Expect(called).To(BeFalse())
+			Expect(txOp.SetFinishable(true).Commit()).To(BeNil())
+			Expect(called).To(BeTrue())
+		})
+
+		It("should not call the function when the transaction is rolled back", func() {
+			txOp := GetTxOp(db)
+			called := false
+			txOp.AfterCommit(func() { called = true })
+			Expect(txOp.Rollback()).To(BeNil())
+			Expect(called).To(BeFalse())
+		})
+	})
+
 	Describe(".GetBlockQueryRangeOp", func() {
 		It("should get the block range passed to it", func() {
 			br := &OpBlockQueryRange{Min: 2, Max: 10}
@@ -38,6 +38,21 @@ var (
 	// TagReOrg represents a meta object
 	TagReOrg = []byte("r")
 
+	// TagRejectedReOrg represents a rejected reorganization object
+	TagRejectedReOrg = []byte("j")
+
+	// TagAddressTransaction represents an address to transaction index object
+	TagAddressTransaction = []byte("d")
+
+	// TagAddressIndex represents the status of the address index of a chain
+	TagAddressIndex = []byte("x")
+
+	// TagPruneHorizon represents the prune horizon of a chain
+	TagPruneHorizon = []byte("p")
+
+	// TagName represents a name record object
+	TagName = []byte("e")
+
 	// TagMinedBlock represents a mined block data
 	TagMinedBlockHeader = []byte("m")
 )
@@ -114,6 +129,17 @@ func MakeKeyBlockHash(chainID []byte, hash []byte) []byte {
 	)
 }
 
+// MakeQueryKeyChain constructs a key for querying
+// all objects (blocks, transactions, accounts etc)
+// stored in a chain.
+// Prefixes: tag_chain + chain ID
+func MakeQueryKeyChain(chainID []byte) []byte {
+	return elldb.MakePrefix(
+		TagChain,
+		chainID,
+	)
+}
+
 // MakeKeyChain constructs a key for storing chain
 // information.
 // Prefixes: tag_chain_info + chain ID
@@ -167,6 +193,118 @@ func MakeQueryKeyTransactions(chainID []byte) []byte {
 	)
 }
 
+// MakeKeyAddressTransaction constructs a key for indexing
+// a transaction sent or received by an address.
+// Prefixes: tag_chain + chain ID + tag_address_transaction +
+// address + block number (big endian) + tx index (big endian)
+func MakeKeyAddressTransaction(chainID, address []byte, blockNumber,
+	txIndex uint64) []byte {
+	return elldb.MakeKey(
+		append(util.EncodeNumber(blockNumber), util.EncodeNumber(txIndex)...),
+		TagChain,
+		chainID,
+		TagAddressTransaction,
+		address,
+	)
+}
+
+// MakeQueryKeyAddressTransactions constructs a key for
+// querying the indexed transactions of an address.
+// The key/prefix separator is included to prevent
+// matching addresses that begin with the given address.
+// Prefixes: tag_chain + chain ID + tag_address_transaction +
+// address
+func MakeQueryKeyAddressTransactions(chainID, address []byte) []byte {
+	return append(elldb.MakePrefix(
+		TagChain,
+		chainID,
+		TagAddressTransaction,
+		address,
+	), []byte(elldb.KeyPrefixSeparator)...)
+}
+
+// MakeQueryKeyAddressIndex constructs a key for
+// querying all address to transaction index objects
+// of a chain.
+// Prefixes: tag_chain + chain ID + tag_address_transaction
+func MakeQueryKeyAddressIndex(chainID []byte) []byte {
+	return elldb.MakePrefix(
+		TagChain,
+		chainID,
+		TagAddressTransaction,
+	)
+}
+
+// MakeKeyAddressIndexStatus constructs a key for storing
+// the status of the address index of a chain.
+// Prefixes: tag_chain + chain ID + tag_address_index
+func MakeKeyAddressIndexStatus(chainID []byte) []byte {
+	return elldb.MakePrefix(
+		TagChain,
+		chainID,
+		TagAddressIndex,
+	)
+}
+
+// MakeKeyPruneHorizon constructs a key for storing
+// the prune horizon of a chain.
+// Prefixes: tag_chain + chain ID + tag_prune_horizon
+func MakeKeyPruneHorizon(chainID []byte) []byte {
+	return elldb.MakePrefix(
+		TagChain,
+		chainID,
+		TagPruneHorizon,
+	)
+}
+
+// MakeKeyName constructs a key for storing a name record.
+// Prefixes: tag_chain + chain ID + tag_name + name +
+// block number (big endian)
+func MakeKeyName(blockNum uint64, chainID, name []byte) []byte {
+	return elldb.MakeKey(
+		util.EncodeNumber(blockNum),
+		TagChain,
+		chainID,
+		TagName,
+		name,
+	)
+}
+
+// MakeQueryKeyName constructs a key for finding
+// the versions of a name record. The key/prefix
+// separator is included to prevent matching names
+// that begin with the given name.
+// Prefixes: tag_chain + chain ID + tag_name + name
+func MakeQueryKeyName(chainID, name []byte) []byte {
+	return append(elldb.MakePrefix(
+		TagChain,
+		chainID,
+		TagName,
+		name,
+	), []byte(elldb.KeyPrefixSeparator)...)
+}
+
+// MakeQueryKeyNames constructs a key for querying
+// all name records in a chain.
+// Prefixes: tag_chain + chain ID + tag_name
+func MakeQueryKeyNames(chainID []byte) []byte {
+	return elldb.MakePrefix(
+		TagChain,
+		chainID,
+		TagName,
+	)
+}
+
+// MakeTreeKeyName constructs the key of
+// a name record in the state tree.
+// Prefixes: tag_name + name
+func MakeTreeKeyName(name []byte) []byte {
+	return elldb.MakePrefix(
+		TagName,
+		name,
+	)
+}
+
 // MakeKeyMinedBlock constructs a key for recording
 // information about blocks mined
 func MakeKeyMinedBlock(chainID []byte, blockNumber uint64) []byte {
@@ -213,3 +351,21 @@ func MakeQueryKeyReOrg() []byte {
 		TagReOrg,
 	)
 }
+
+// MakeKeyRejectedReOrg constructs a key for storing
+// information about a rejected reorganization.
+// Prefixes: tag_rejectedReOrg + timestamp (big endian)
+func MakeKeyRejectedReOrg(timestamp int64) []byte {
+	return elldb.MakeKey(util.EncodeNumber(uint64(timestamp)),
+		TagRejectedReOrg,
+	)
+}
+
+// MakeQueryKeyRejectedReOrg constructs a key for
+// querying rejected reorganizations.
+// Prefixes: tag_rejectedReOrg
+func MakeQueryKeyRejectedReOrg() []byte {
+	return elldb.MakePrefix(
+		TagRejectedReOrg,
+	)
+}
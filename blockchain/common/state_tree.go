@@ -0,0 +1,278 @@
+package common
+
+import (
+	"github.com/ellcrys/elld/types"
+	"github.com/ellcrys/elld/util"
+)
+
+var (
+	// stateLeafPrefix is prepended to the content
+	// of a leaf node before it is hashed
+	stateLeafPrefix = []byte{0x00}
+
+	// stateNodePrefix is prepended to the content
+	// of an internal node before it is hashed
+	stateNodePrefix = []byte{0x01}
+)
+
+// stateNode represents a node in the state tree. A leaf
+// node holds a key and the hash of its value while an
+// internal node holds the two subtrees below it. Nodes
+// are never changed once created, so they can be shared
+// by copies of a tree.
+type stateNode struct {
+	leaf  bool
+	key   util.Hash
+	value util.Hash
+	left  *stateNode
+	right *stateNode
+	hash  util.Hash
+}
+
+// newStateLeaf creates a leaf node
+func newStateLeaf(key, value util.Hash) *stateNode {
+	return &stateNode{
+		leaf:  true,
+		key:   key,
+		value: value,
+		hash:  hashStateLeaf(key, value),
+	}
+}
+
+// newStateInternal creates an internal node
+func newStateInternal(left, right *stateNode) *stateNode {
+	return &stateNode{
+		left:  left,
+		right: right,
+		hash:  hashStateNode(left.getHash(), right.getHash()),
+	}
+}
+
+// getHash returns the hash of the node.
+// A nil node is an empty subtree.
+func (n *stateNode) getHash() util.Hash {
+	if n == nil {
+		return util.EmptyHash
+	}
+	return n.hash
+}
+
+// StateTree is a sparse merkle tree that commits to the
+// full world state. Objects are keyed by the blake2b-256
+// hash of their address (the path) and the leaf stores
+// the blake2b-256 hash of the object's value.
+//
+// A subtree with no leaf has an empty hash as its root.
+// A subtree with a single leaf is collapsed into that
+// leaf, so the depth of the tree grows with the log of
+// the number of leaves rather than the length of the path.
+// Since the shape of the tree depends only on the set of
+// keys, the root uniquely identifies the set of objects.
+//
+// The hash of every node is kept, so setting a key only
+// rehashes the nodes on its path. The nodes on the path
+// are replaced rather than changed, which allows copies
+// of the tree to share the rest of the nodes.
+type StateTree struct {
+	root *stateNode
+	size int
+}
+
+// NewStateTree creates an empty StateTree
+func NewStateTree() *StateTree {
+	return &StateTree{}
+}
+
+// StateTreeKey returns the path of a key in the state tree
+func StateTreeKey(key []byte) util.Hash {
+	return util.BytesToHash(util.Blake2b256(key))
+}
+
+// Set adds a key with the given value to the tree.
+// If the key already exists, its value is replaced.
+func (t *StateTree) Set(key, value []byte) {
+	var added bool
+	t.root, added = insertStateLeaf(t.root, 0, StateTreeKey(key),
+		util.BytesToHash(util.Blake2b256(value)))
+	if added {
+		t.size++
+	}
+}
+
+// insertStateLeaf adds a leaf to the subtree whose root
+// is at the given depth and returns the new root of the
+// subtree. It returns true if the key did not exist.
+func insertStateLeaf(n *stateNode, depth int, key, value util.Hash) (*stateNode, bool) {
+
+	if n == nil {
+		return newStateLeaf(key, value), true
+	}
+
+	if n.leaf {
+		if n.key.Equal(key) {
+			if n.value.Equal(value) {
+				return n, false
+			}
+			return newStateLeaf(key, value), false
+		}
+		return joinStateLeaves(n, newStateLeaf(key, value), depth), true
+	}
+
+	var added bool
+	left, right := n.left, n.right
+	if pathBit(key, depth) == 0 {
+		left, added = insertStateLeaf(left, depth+1, key, value)
+	} else {
+		right, added = insertStateLeaf(right, depth+1, key, value)
+	}
+
+	return newStateInternal(left, right), added
+}
+
+// joinStateLeaves creates the subtree at the given
+// depth containing two leaves with different paths
+func joinStateLeaves(a, b *stateNode, depth int) *stateNode {
+	bitA, bitB := pathBit(a.key, depth), pathBit(b.key, depth)
+	switch {
+	case bitA == 0 && bitB == 0:
+		return newStateInternal(joinStateLeaves(a, b, depth+1), nil)
+	case bitA == 1 && bitB == 1:
+		return newStateInternal(nil, joinStateLeaves(a, b, depth+1))
+	case bitA == 0:
+		return newStateInternal(a, b)
+	default:
+		return newStateInternal(b, a)
+	}
+}
+
+// getStateLeaf returns the leaf of a path.
+// It returns nil if the path does not exist.
+func (t *StateTree) getStateLeaf(path util.Hash) *stateNode {
+	n := t.root
+	for depth := 0; n != nil && !n.leaf; depth++ {
+		if pathBit(path, depth) == 0 {
+			n = n.left
+			continue
+		}
+		n = n.right
+	}
+	if n == nil || !n.key.Equal(path) {
+		return nil
+	}
+	return n
+}
+
+// Get returns the hash of the value stored for a key.
+// It returns false if the key does not exist.
+func (t *StateTree) Get(key []byte) (util.Hash, bool) {
+	leaf := t.getStateLeaf(StateTreeKey(key))
+	if leaf == nil {
+		return util.EmptyHash, false
+	}
+	return leaf.value, true
+}
+
+// Len returns the number of leaves in the tree
+func (t *StateTree) Len() int {
+	return t.size
+}
+
+// Copy returns a copy of the tree. Changes
+// to the copy do not affect the tree.
+func (t *StateTree) Copy() *StateTree {
+	return &StateTree{root: t.root, size: t.size}
+}
+
+// Root returns the root of the tree.
+// An empty tree has an empty hash as its root.
+func (t *StateTree) Root() util.Hash {
+	return t.root.getHash()
+}
+
+// pathBit returns the bit of a path at the given depth
+func pathBit(path util.Hash, depth int) byte {
+	return (path[depth/8] >> (7 - uint(depth%8))) & 1
+}
+
+// hashStateLeaf returns the hash of a leaf node
+func hashStateLeaf(key, value util.Hash) util.Hash {
+	data := append(append(append([]byte{}, stateLeafPrefix...),
+		key.Bytes()...), value.Bytes()...)
+	return util.BytesToHash(util.Blake2b256(data))
+}
+
+// hashStateNode returns the hash of an internal node
+func hashStateNode(left, right util.Hash) util.Hash {
+	data := append(append(append([]byte{}, stateNodePrefix...),
+		left.Bytes()...), right.Bytes()...)
+	return util.BytesToHash(util.Blake2b256(data))
+}
+
+// StateProof is a merkle proof of the inclusion
+// of a key and its value in a state tree.
+type StateProof struct {
+
+	// Siblings are the roots of the subtrees adjacent
+	// to the path of the key. They are ordered from
+	// the top of the tree down to the leaf.
+	Siblings []util.Hash `json:"siblings" msgpack:"siblings"`
+}
+
+// Prove returns a proof of the inclusion of a key in
+// the tree. It returns false if the key does not exist.
+func (t *StateTree) Prove(key []byte) (*StateProof, bool) {
+
+	path := StateTreeKey(key)
+	if t.getStateLeaf(path) == nil {
+		return nil, false
+	}
+
+	// Walk down the path of the key. At every
+	// internal node, record the root of the
+	// subtree that is not on the path.
+	proof := &StateProof{Siblings: []util.Hash{}}
+	n := t.root
+	for depth := 0; !n.leaf; depth++ {
+		if pathBit(path, depth) == 0 {
+			proof.Siblings = append(proof.Siblings, n.right.getHash())
+			n = n.left
+			continue
+		}
+		proof.Siblings = append(proof.Siblings, n.left.getHash())
+		n = n.right
+	}
+
+	return proof, true
+}
+
+// VerifyStateProof checks whether a proof shows that a
+// key and value are included in a state tree with the
+// given root.
+func VerifyStateProof(root util.Hash, key, value []byte, proof *StateProof) bool {
+
+	if proof == nil || len(proof.Siblings) > util.HashLength*8 {
+		return false
+	}
+
+	// Compute the leaf and hash it together with
+	// the siblings, from the bottom of the tree up
+	path := StateTreeKey(key)
+	node := hashStateLeaf(path, util.BytesToHash(util.Blake2b256(value)))
+	for depth := len(proof.Siblings) - 1; depth >= 0; depth-- {
+		if pathBit(path, depth) == 0 {
+			node = hashStateNode(node, proof.Siblings[depth])
+			continue
+		}
+		node = hashStateNode(proof.Siblings[depth], node)
+	}
+
+	return node.Equal(root)
+}
+
+// VerifyAccountProof checks whether a proof shows that
+// an account is included in a state tree with the given
+// root. The root is usually the state root of a header.
+func VerifyAccountProof(root util.Hash, account types.Account, proof *StateProof) bool {
+	return VerifyStateProof(root, account.GetAddress().Bytes(),
+		util.ObjectToBytes(account), proof)
+}
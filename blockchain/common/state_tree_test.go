@@ -0,0 +1,151 @@
+package common
+
+import (
+	"github.com/ellcrys/elld/util"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("StateTree", func() {
+
+	var tree *StateTree
+
+	BeforeEach(func() {
+		tree = NewStateTree()
+	})
+
+	Describe(".Set", func() {
+		It("should add a new key", func() {
+			tree.Set([]byte("a"), []byte("1"))
+			Expect(tree.Len()).To(Equal(1))
+			v, ok := tree.Get([]byte("a"))
+			Expect(ok).To(BeTrue())
+			Expect(v).To(Equal(util.BytesToHash(util.Blake2b256([]byte("1")))))
+		})
+
+		It("should replace the value of an existing key", func() {
+			tree.Set([]byte("a"), []byte("1"))
+			tree.Set([]byte("a"), []byte("2"))
+			Expect(tree.Len()).To(Equal(1))
+			v, _ := tree.Get([]byte("a"))
+			Expect(v).To(Equal(util.BytesToHash(util.Blake2b256([]byte("2")))))
+		})
+	})
+
+	Describe(".Get", func() {
+		It("should return false if key does not exist", func() {
+			_, ok := tree.Get([]byte("a"))
+			Expect(ok).To(BeFalse())
+		})
+	})
+
+	Describe(".Copy", func() {
+		It("should return a tree that can be changed independently", func() {
+			tree.Set([]byte("a"), []byte("1"))
+			cp := tree.Copy()
+			Expect(cp.Root()).To(Equal(tree.Root()))
+
+			cp.Set([]byte("b"), []byte("2"))
+			Expect(cp.Len()).To(Equal(2))
+			Expect(tree.Len()).To(Equal(1))
+		})
+
+		It("should not change the root of the tree when an existing key of the copy changes", func() {
+			tree.Set([]byte("a"), []byte("1"))
+			tree.Set([]byte("b"), []byte("2"))
+			root := tree.Root()
+
+			cp := tree.Copy()
+			cp.Set([]byte("a"), []byte("3"))
+			Expect(cp.Root()).ToNot(Equal(root))
+			Expect(tree.Root()).To(Equal(root))
+			v, _ := tree.Get([]byte("a"))
+			Expect(v).To(Equal(util.BytesToHash(util.Blake2b256([]byte("1")))))
+		})
+	})
+
+	Describe(".Root", func() {
+		It("should return empty hash when tree is empty", func() {
+			Expect(tree.Root()).To(Equal(util.EmptyHash))
+		})
+
+		It("should return expected root with one key", func() {
+			tree.Set([]byte("a"), []byte("1"))
+			Expect(tree.Root()).To(Equal(util.Hash{105, 94, 186, 41, 153, 228, 15, 163, 12, 5, 160, 87, 252, 187, 70, 72, 13, 188, 128, 111, 248, 237, 206, 155, 251, 70, 197, 113, 143, 214, 110, 74}))
+		})
+
+		It("should return expected root with three keys", func() {
+			tree.Set([]byte("a"), []byte("1"))
+			tree.Set([]byte("b"), []byte("2"))
+			tree.Set([]byte("c"), []byte("3"))
+			Expect(tree.Root()).To(Equal(util.Hash{32, 206, 249, 253, 241, 164, 245, 84, 234, 63, 194, 150, 245, 217, 226, 116, 104, 187, 192, 98, 201, 98, 145, 243, 188, 171, 70, 46, 229, 98, 191, 226}))
+		})
+
+		It("should return the same root regardless of insertion order", func() {
+			tree.Set([]byte("a"), []byte("1"))
+			tree.Set([]byte("b"), []byte("2"))
+			tree2 := NewStateTree()
+			tree2.Set([]byte("b"), []byte("2"))
+			tree2.Set([]byte("a"), []byte("1"))
+			Expect(tree.Root()).To(Equal(tree2.Root()))
+		})
+
+		It("should return a different root when a value changes", func() {
+			tree.Set([]byte("a"), []byte("1"))
+			tree.Set([]byte("b"), []byte("2"))
+			root := tree.Root()
+			tree.Set([]byte("b"), []byte("3"))
+			Expect(tree.Root()).ToNot(Equal(root))
+		})
+	})
+
+	Describe(".Prove", func() {
+		It("should return false if key does not exist", func() {
+			tree.Set([]byte("a"), []byte("1"))
+			_, ok := tree.Prove([]byte("b"))
+			Expect(ok).To(BeFalse())
+		})
+
+		It("should return a proof with no sibling when tree has one key", func() {
+			tree.Set([]byte("a"), []byte("1"))
+			proof, ok := tree.Prove([]byte("a"))
+			Expect(ok).To(BeTrue())
+			Expect(proof.Siblings).To(BeEmpty())
+			Expect(VerifyStateProof(tree.Root(), []byte("a"), []byte("1"), proof)).To(BeTrue())
+		})
+	})
+
+	Describe(".VerifyStateProof", func() {
+
+		var root util.Hash
+
+		BeforeEach(func() {
+			for i := 0; i < 50; i++ {
+				tree.Set([]byte{byte(i)}, []byte{byte(i * 2)})
+			}
+			root = tree.Root()
+		})
+
+		It("should return true for every key in the tree", func() {
+			for i := 0; i < 50; i++ {
+				proof, ok := tree.Prove([]byte{byte(i)})
+				Expect(ok).To(BeTrue())
+				Expect(VerifyStateProof(root, []byte{byte(i)}, []byte{byte(i * 2)}, proof)).To(BeTrue())
+			}
+		})
+
+		It("should return false if value is not the value in the tree", func() {
+			proof, _ := tree.Prove([]byte{1})
+			Expect(VerifyStateProof(root, []byte{1}, []byte{3}, proof)).To(BeFalse())
+		})
+
+		It("should return false if proof belongs to another key", func() {
+			proof, _ := tree.Prove([]byte{1})
+			Expect(VerifyStateProof(root, []byte{2}, []byte{4}, proof)).To(BeFalse())
+		})
+
+		It("should return false if proof is nil", func() {
+			Expect(VerifyStateProof(root, []byte{1}, []byte{2}, nil)).To(BeFalse())
+		})
+	})
+})
@@ -57,3 +57,18 @@ func (o *OpNewAccountBalance) Equal(t Transition) bool {
 	}
 	return false
 }
+
+// OpNewName represents a transition to a new name record.
+// The name is used as the address.
+type OpNewName struct {
+	*OpBase
+	Record *types.NameRecord
+}
+
+// Equal checks whether a Transition t is equal to o
+func (o *OpNewName) Equal(t Transition) bool {
+	if _t, yes := t.(*OpNewName); yes && o.Address() == _t.Address() {
+		return true
+	}
+	return false
+}
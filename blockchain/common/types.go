@@ -20,6 +20,10 @@ type OpTx struct {
 	Tx        elldb.Tx
 	CanFinish bool
 	finished  bool
+
+	// afterCommit are functions to call
+	// once the transaction is committed
+	afterCommit []func()
 }
 
 // Closed gets the status of the transaction
@@ -40,6 +44,7 @@ func (t *OpTx) Discard() error {
 	}
 	t.Tx.Discard()
 	t.finished = true
+	t.afterCommit = nil
 	return nil
 }
 
@@ -53,6 +58,10 @@ func (t *OpTx) Commit() error {
 		return err
 	}
 	t.finished = true
+	for _, f := range t.afterCommit {
+		f()
+	}
+	t.afterCommit = nil
 	return nil
 }
 
@@ -64,9 +73,18 @@ func (t *OpTx) Rollback() error {
 	}
 	t.Tx.Rollback()
 	t.finished = true
+	t.afterCommit = nil
 	return nil
 }
 
+// AfterCommit registers a function to be called
+// once the transaction has been committed. The
+// function is not called if the transaction is
+// rolled back or discarded.
+func (t *OpTx) AfterCommit(f func()) {
+	t.afterCommit = append(t.afterCommit, f)
+}
+
 // Finishable makes the transaction finishable
 func (t *OpTx) Finishable() *OpTx {
 	t.CanFinish = true
@@ -110,6 +128,15 @@ func (t OpAllowExec) GetName() string {
 	return "OpAllowExec"
 }
 
+// OpBlockNumber defines a CallOp for passing the
+// number of the block whose transactions are processed
+type OpBlockNumber uint64
+
+// GetName implements core.CallOp
+func (t OpBlockNumber) GetName() string {
+	return "OpBlockNumber"
+}
+
 // OpChainer defines a CallOp for
 // passing a chain
 type OpChainer struct {
@@ -135,6 +162,10 @@ type StateObject struct {
 	// to persist the object to database
 	Key []byte
 
+	// TreeKey is the key of the object
+	// in the state tree
+	TreeKey []byte
+
 	// Value is the content of this state
 	// object. It is written to the database
 	// and the tree
@@ -0,0 +1,115 @@
+package blockchain
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+
+	"github.com/jinzhu/copier"
+	"github.com/vmihailenco/msgpack"
+
+	"github.com/ellcrys/elld/types/core"
+)
+
+// Export writes the blocks of the main chain from
+// block number from to block number to (inclusive)
+// into w. The blocks are written in ascending order
+// as a stream of msgpack encoded core.BlockBody objects.
+// The tip block is used as the last block if to is zero.
+// It returns the number of blocks written.
+func (b *Blockchain) Export(w io.Writer, from, to uint64) (int, error) {
+	b.chainLock.RLock()
+	chain := b.bestChain
+	b.chainLock.RUnlock()
+
+	if chain == nil {
+		return 0, core.ErrBestChainUnknown
+	}
+
+	tip, err := chain.Current()
+	if err != nil {
+		return 0, err
+	}
+
+	if from == 0 {
+		from = 1
+	}
+
+	if to == 0 || to > tip.GetNumber() {
+		to = tip.GetNumber()
+	}
+
+	if from > to {
+		return 0, fmt.Errorf("start block cannot be greater than end block")
+	}
+
+	bw := bufio.NewWriter(w)
+	enc := msgpack.NewEncoder(bw)
+
+	var count int
+	for num := from; num <= to; num++ {
+		block, err := chain.GetBlock(num)
+		if err != nil {
+			return count, fmt.Errorf("failed to get block %d: %s", num, err)
+		}
+
+		var blockBody core.BlockBody
+		copier.Copy(&blockBody, block)
+		if err := enc.Encode(blockBody); err != nil {
+			return count, fmt.Errorf("failed to write block %d: %s", num, err)
+		}
+
+		count++
+	}
+
+	return count, bw.Flush()
+}
+
+// Import reads blocks written by Export from r
+// and processes them in the order they were written.
+// Blocks that are already known are skipped. It
+// returns the number of blocks that were processed.
+func (b *Blockchain) Import(r io.Reader) (int, error) {
+
+	dec := msgpack.NewDecoder(bufio.NewReader(r))
+
+	var count int
+	for {
+		var blockBody core.BlockBody
+		if err := dec.Decode(&blockBody); err != nil {
+			if err == io.EOF {
+				break
+			}
+			return count, fmt.Errorf("failed to read block: %s", err)
+		}
+
+		var block core.Block
+		copier.Copy(&block, blockBody)
+
+		// Skip known blocks. This also skips
+		// the genesis block if it is the same
+		// as the local genesis block.
+		known, err := b.HaveBlock(block.GetHash())
+		if err != nil {
+			return count, err
+		} else if known {
+			continue
+		}
+
+		if _, err := b.ProcessBlock(&block); err != nil {
+			return count, fmt.Errorf("failed to process block %d: %s",
+				block.GetNumber(), err)
+		}
+
+		// A block whose parent is unknown is held
+		// as an orphan rather than being rejected
+		if b.isOrphanBlock(block.GetHash()) {
+			return count, fmt.Errorf("failed to process block %d: parent block is unknown",
+				block.GetNumber())
+		}
+
+		count++
+	}
+
+	return count, nil
+}
@@ -0,0 +1,145 @@
+package blockchain
+
+import (
+	"bytes"
+	"math/big"
+	"os"
+	"time"
+
+	. "github.com/ellcrys/elld/blockchain/testutil"
+	"github.com/ellcrys/elld/blockchain/txpool"
+	"github.com/ellcrys/elld/config"
+	"github.com/ellcrys/elld/crypto"
+	"github.com/ellcrys/elld/elldb"
+	"github.com/ellcrys/elld/testutil"
+	"github.com/ellcrys/elld/types"
+	"github.com/ellcrys/elld/types/core"
+	"github.com/ellcrys/elld/util"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Export & Import", func() {
+
+	var err error
+	var bc *Blockchain
+	var cfg *config.EngineConfig
+	var db elldb.DB
+	var genesisBlock types.Block
+	var sender, receiver *crypto.Key
+
+	BeforeEach(func() {
+		cfg, err = testutil.SetTestCfg()
+		Expect(err).To(BeNil())
+
+		db = elldb.NewDB(cfg.NetDataDir())
+		err = db.Open(util.RandString(5))
+		Expect(err).To(BeNil())
+
+		sender = crypto.NewKeyFromIntSeed(1)
+		receiver = crypto.NewKeyFromIntSeed(2)
+
+		bc = New(txpool.New(100, nil), nil, newEngine(nil), cfg, log)
+		bc.SetDB(db)
+		bc.SetCoinbase(crypto.NewKeyFromIntSeed(1234))
+	})
+
+	BeforeEach(func() {
+		genesisBlock, err = LoadBlockFromFile("genesis-test.json")
+		Expect(err).To(BeNil())
+		bc.SetGenesisBlock(genesisBlock)
+		err = bc.Up()
+		Expect(err).To(BeNil())
+
+		// Add blocks 2 and 3 to the main chain
+		for nonce := uint64(1); nonce <= 2; nonce++ {
+			block := MakeTestBlock(bc, bc.bestChain, &types.GenerateBlockParams{
+				Transactions: []types.Transaction{
+					core.NewTx(core.TxTypeBalance, nonce, receiver.Addr(), sender, "1", "2.5", time.Now().UnixNano()),
+				},
+				Creator:           sender,
+				Nonce:             util.EncodeNonce(1),
+				Difficulty:        new(big.Int).SetInt64(131072),
+				OverrideTimestamp: time.Now().Unix() + int64(nonce),
+			})
+			_, err = bc.ProcessBlock(block)
+			Expect(err).To(BeNil())
+		}
+	})
+
+	AfterEach(func() {
+		db.Close()
+		err = os.RemoveAll(cfg.DataDir())
+		Expect(err).To(BeNil())
+	})
+
+	Describe(".Export", func() {
+		It("should return error if start block is greater than end block", func() {
+			_, err := bc.Export(bytes.NewBuffer(nil), 3, 2)
+			Expect(err).ToNot(BeNil())
+			Expect(err.Error()).To(Equal("start block cannot be greater than end block"))
+		})
+
+		It("should export all blocks up to the tip if end block is not set", func() {
+			n, err := bc.Export(bytes.NewBuffer(nil), 1, 0)
+			Expect(err).To(BeNil())
+			Expect(n).To(Equal(3))
+		})
+
+		It("should export only blocks within the range", func() {
+			n, err := bc.Export(bytes.NewBuffer(nil), 2, 2)
+			Expect(err).To(BeNil())
+			Expect(n).To(Equal(1))
+		})
+	})
+
+	Describe(".Import", func() {
+
+		var bc2 *Blockchain
+		var db2 elldb.DB
+
+		BeforeEach(func() {
+			db2 = elldb.NewDB(cfg.NetDataDir())
+			err = db2.Open(util.RandString(5))
+			Expect(err).To(BeNil())
+
+			bc2 = New(txpool.New(100, nil), nil, newEngine(nil), cfg, log)
+			bc2.SetDB(db2)
+			bc2.SetCoinbase(crypto.NewKeyFromIntSeed(1234))
+			bc2.SetGenesisBlock(genesisBlock)
+			err = bc2.Up()
+			Expect(err).To(BeNil())
+		})
+
+		AfterEach(func() {
+			db2.Close()
+		})
+
+		It("should process exported blocks and skip known blocks", func() {
+			buf := bytes.NewBuffer(nil)
+			_, err := bc.Export(buf, 1, 0)
+			Expect(err).To(BeNil())
+
+			n, err := bc2.Import(buf)
+			Expect(err).To(BeNil())
+			Expect(n).To(Equal(2))
+
+			tip, err := bc.bestChain.GetBlock(0)
+			Expect(err).To(BeNil())
+			tip2, err := bc2.bestChain.GetBlock(0)
+			Expect(err).To(BeNil())
+			Expect(tip2.GetHash()).To(Equal(tip.GetHash()))
+		})
+
+		It("should return error if a block cannot be processed", func() {
+			buf := bytes.NewBuffer(nil)
+			_, err := bc.Export(buf, 3, 3)
+			Expect(err).To(BeNil())
+
+			n, err := bc2.Import(buf)
+			Expect(err).ToNot(BeNil())
+			Expect(err.Error()).To(ContainSubstring("failed to process block 3"))
+			Expect(n).To(Equal(0))
+		})
+	})
+})
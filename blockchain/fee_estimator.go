@@ -0,0 +1,163 @@
+package blockchain
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/ellcrys/elld/blockchain/txpool"
+	"github.com/ellcrys/elld/params"
+	"github.com/ellcrys/elld/types"
+	"github.com/ellcrys/elld/types/core"
+	"github.com/ellcrys/elld/util"
+	"github.com/shopspring/decimal"
+)
+
+// FeeEstimate is the result of a fee estimation
+type FeeEstimate struct {
+	FeeRate util.String `json:"feeRate"`
+	Fee     util.String `json:"fee"`
+}
+
+// FeeEstimator suggests the fee a transaction should
+// pay to be included in a block within a number of
+// blocks. It uses the fee rates of transactions included
+// in recent blocks of the main chain and of transactions
+// still waiting in the pool.
+type FeeEstimator struct {
+	bchain types.Blockchain
+	txPool types.TxPool
+
+	// blockSpace is the size of transactions
+	// that can fit in a block
+	blockSpace int64
+}
+
+// NewFeeEstimator creates a FeeEstimator
+func NewFeeEstimator(bchain types.Blockchain, txPool types.TxPool) *FeeEstimator {
+	return &FeeEstimator{
+		bchain:     bchain,
+		txPool:     txPool,
+		blockSpace: bchain.GetChainSpec().MaxBlockTxsSize,
+	}
+}
+
+// historyFeeRate returns a fee rate of the transactions
+// included in the recent blocks. The smaller targetBlocks
+// is, the higher the percentile of the rates returned;
+// starting from the 90th percentile for the next block and
+// tending toward the median. It returns zero if no
+// transaction was included in the recent blocks.
+func (e *FeeEstimator) historyFeeRate(targetBlocks int) (decimal.Decimal, error) {
+
+	tip, err := e.bchain.ChainReader().Current()
+	if err != nil {
+		return decimal.Zero, err
+	}
+
+	var rates []decimal.Decimal
+	for i := 0; i < params.FeeEstimateBlocks && uint64(i) < tip.GetNumber(); i++ {
+		block, err := e.bchain.ChainReader().GetBlock(tip.GetNumber() - uint64(i))
+		if err != nil {
+			return decimal.Zero, err
+		}
+		for _, tx := range block.GetTransactions() {
+			if tx.GetType() == core.TxTypeAlloc {
+				continue
+			}
+			rates = append(rates, txpool.CalcFeeRate(tx).Decimal())
+		}
+	}
+
+	if len(rates) == 0 {
+		return decimal.Zero, nil
+	}
+
+	sort.Slice(rates, func(i, j int) bool {
+		return rates[i].LessThan(rates[j])
+	})
+
+	percentile := 0.5 + 0.4/float64(targetBlocks)
+	return rates[int(percentile*float64(len(rates)-1))], nil
+}
+
+// poolFeeRate returns the lowest fee rate of the highest
+// paying transactions in the pool that fill the next
+// targetBlocks blocks. It returns zero if the transactions
+// in the pool do not fill the blocks.
+func (e *FeeEstimator) poolFeeRate(targetBlocks int) decimal.Decimal {
+
+	type entry struct {
+		rate decimal.Decimal
+		size int64
+	}
+
+	var entries []entry
+	e.txPool.Container().IFind(func(tx types.Transaction) bool {
+		entries = append(entries, entry{
+			rate: txpool.CalcFeeRate(tx).Decimal(),
+			size: tx.GetSizeNoFee(),
+		})
+		return false
+	})
+
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].rate.GreaterThan(entries[j].rate)
+	})
+
+	var total int64
+	var space = e.blockSpace * int64(targetBlocks)
+	for _, entry := range entries {
+		if total += entry.size; total >= space {
+			return entry.rate
+		}
+	}
+
+	return decimal.Zero
+}
+
+// roundUp rounds a value up to params.Decimals decimal places
+func roundUp(d decimal.Decimal) decimal.Decimal {
+	rounded := d.Truncate(params.Decimals)
+	if rounded.LessThan(d) {
+		rounded = rounded.Add(decimal.New(1, -params.Decimals))
+	}
+	return rounded
+}
+
+// Estimate estimates the fee a transaction of the given
+// size should pay to be included within targetBlocks blocks.
+// The fee rate is never below the fee per byte of
+// the chain specification.
+func (e *FeeEstimator) Estimate(txSize int64, targetBlocks int) (*FeeEstimate, error) {
+
+	if txSize <= 0 {
+		return nil, fmt.Errorf("transaction size must be greater than zero")
+	}
+
+	if targetBlocks <= 0 {
+		return nil, fmt.Errorf("target blocks must be greater than zero")
+	}
+
+	rate := e.bchain.GetChainSpec().FeePerByte
+
+	historyRate, err := e.historyFeeRate(targetBlocks)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get recent fee rates: %s", err)
+	}
+	if historyRate.GreaterThan(rate) {
+		rate = historyRate
+	}
+
+	if poolRate := e.poolFeeRate(targetBlocks); poolRate.GreaterThan(rate) {
+		rate = poolRate
+	}
+
+	// The fee is rounded up so that it is
+	// not below the minimum fee for the size
+	fee := roundUp(rate.Mul(decimal.New(txSize, 0)))
+
+	return &FeeEstimate{
+		FeeRate: util.String(roundUp(rate).StringFixed(params.Decimals)),
+		Fee:     util.String(fee.StringFixed(params.Decimals)),
+	}, nil
+}
@@ -0,0 +1,133 @@
+package blockchain
+
+import (
+	"math/big"
+	"os"
+	"time"
+
+	. "github.com/ellcrys/elld/blockchain/testutil"
+	"github.com/ellcrys/elld/blockchain/txpool"
+	"github.com/ellcrys/elld/config"
+	"github.com/ellcrys/elld/crypto"
+	"github.com/ellcrys/elld/elldb"
+	"github.com/ellcrys/elld/params"
+	"github.com/ellcrys/elld/testutil"
+	"github.com/ellcrys/elld/types"
+	"github.com/ellcrys/elld/types/core"
+	"github.com/ellcrys/elld/util"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/shopspring/decimal"
+)
+
+var _ = Describe("FeeEstimator", func() {
+
+	var err error
+	var bc *Blockchain
+	var cfg *config.EngineConfig
+	var db elldb.DB
+	var genesisBlock types.Block
+	var sender, receiver *crypto.Key
+	var estimator *FeeEstimator
+
+	BeforeEach(func() {
+		cfg, err = testutil.SetTestCfg()
+		Expect(err).To(BeNil())
+
+		db = elldb.NewDB(cfg.NetDataDir())
+		err = db.Open(util.RandString(5))
+		Expect(err).To(BeNil())
+
+		sender = crypto.NewKeyFromIntSeed(1)
+		receiver = crypto.NewKeyFromIntSeed(2)
+
+		bc = New(txpool.New(100, nil), nil, newEngine(nil), cfg, log)
+		bc.SetDB(db)
+		bc.SetCoinbase(crypto.NewKeyFromIntSeed(1234))
+	})
+
+	BeforeEach(func() {
+		genesisBlock, err = LoadBlockFromFile("genesis-test.json")
+		Expect(err).To(BeNil())
+		bc.SetGenesisBlock(genesisBlock)
+		err = bc.Up()
+		Expect(err).To(BeNil())
+		estimator = NewFeeEstimator(bc, bc.GetTxPool())
+	})
+
+	AfterEach(func() {
+		db.Close()
+		err = os.RemoveAll(cfg.DataDir())
+		Expect(err).To(BeNil())
+	})
+
+	Describe(".Estimate", func() {
+
+		It("should return error if the transaction size is not set", func() {
+			_, err := estimator.Estimate(0, 1)
+			Expect(err).ToNot(BeNil())
+			Expect(err.Error()).To(Equal("transaction size must be greater than zero"))
+		})
+
+		It("should return error if the target blocks is not set", func() {
+			_, err := estimator.Estimate(100, 0)
+			Expect(err).ToNot(BeNil())
+			Expect(err.Error()).To(Equal("target blocks must be greater than zero"))
+		})
+
+		It("should return the minimum fee when there is no recent transaction", func() {
+			estimate, err := estimator.Estimate(100, 1)
+			Expect(err).To(BeNil())
+			Expect(estimate.FeeRate).To(Equal(util.String(roundUp(params.FeePerByte).StringFixed(params.Decimals))))
+			Expect(estimate.Fee.Decimal().LessThan(params.FeePerByte.Mul(decimal.New(100, 0)))).To(BeFalse())
+		})
+
+		Context("when a block with a transaction is added", func() {
+
+			var tx types.Transaction
+
+			BeforeEach(func() {
+				tx = core.NewTx(core.TxTypeBalance, 1, receiver.Addr(), sender, "1", "2.5", time.Now().Unix())
+				block := MakeTestBlock(bc, bc.bestChain, &types.GenerateBlockParams{
+					Transactions:         []types.Transaction{tx},
+					Creator:              sender,
+					Nonce:                util.EncodeNonce(1),
+					Difficulty:           new(big.Int).SetInt64(131072),
+					OverrideTimestamp:    time.Now().Unix(),
+					NoPoolAdditionInTest: true,
+				})
+				_, err = bc.ProcessBlock(block)
+				Expect(err).To(BeNil())
+			})
+
+			It("should return the fee rate of the transaction", func() {
+				estimate, err := estimator.Estimate(100, 1)
+				Expect(err).To(BeNil())
+				Expect(estimate.FeeRate).To(Equal(txpool.CalcFeeRate(tx)))
+			})
+		})
+
+		Context("when the pool transactions fill the target blocks", func() {
+
+			var tx types.Transaction
+
+			BeforeEach(func() {
+				tx = core.NewTx(core.TxTypeBalance, 1, receiver.Addr(), sender, "1", "2.5", time.Now().Unix())
+				Expect(bc.GetTxPool().Put(tx)).To(BeNil())
+				estimator.blockSpace = tx.GetSizeNoFee()
+			})
+
+			It("should return the fee rate of the lowest paying transaction that fills the blocks", func() {
+				estimate, err := estimator.Estimate(100, 1)
+				Expect(err).To(BeNil())
+				Expect(estimate.FeeRate).To(Equal(txpool.CalcFeeRate(tx)))
+			})
+
+			It("should return the minimum fee if the pool transactions do not fill the blocks", func() {
+				estimate, err := estimator.Estimate(100, 2)
+				Expect(err).To(BeNil())
+				Expect(estimate.FeeRate).To(Equal(util.String(roundUp(params.FeePerByte).StringFixed(params.Decimals))))
+			})
+		})
+	})
+})
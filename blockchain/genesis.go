@@ -0,0 +1,129 @@
+package blockchain
+
+import (
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/ellcrys/elld/blockchain/common"
+	"github.com/ellcrys/elld/blockchain/txpool"
+	"github.com/ellcrys/elld/config"
+	"github.com/ellcrys/elld/crypto"
+	"github.com/ellcrys/elld/elldb"
+	"github.com/ellcrys/elld/params"
+	"github.com/ellcrys/elld/types"
+	"github.com/ellcrys/elld/types/core"
+	"github.com/ellcrys/elld/util"
+	"github.com/ellcrys/elld/util/logger"
+)
+
+// GenesisAlloc is an amount of coins
+// allocated to an address in the genesis block
+type GenesisAlloc struct {
+	Address util.String `json:"address"`
+	Balance util.String `json:"balance"`
+}
+
+// GenesisSpec describes a genesis block
+type GenesisSpec struct {
+
+	// CreatorKey is the base58 encoded private
+	// key of the creator of the genesis block
+	CreatorKey string `json:"creatorKey"`
+
+	// Difficulty is the difficulty of the genesis block. The
+	// genesis difficulty of the chain specification is used
+	// if not set.
+	Difficulty *big.Int `json:"difficulty"`
+
+	// Timestamp is the unix time of the genesis
+	// block. The current time is used if not set.
+	Timestamp int64 `json:"timestamp"`
+
+	// Allocs are the allocations of the genesis block
+	Allocs []*GenesisAlloc `json:"allocs"`
+}
+
+// validate checks the fields of the spec
+func (s *GenesisSpec) validate() error {
+
+	if s.CreatorKey == "" {
+		return fmt.Errorf("creator key is required")
+	} else if crypto.IsValidPrivKey(s.CreatorKey) != nil {
+		return fmt.Errorf("creator key is not valid")
+	}
+
+	if s.Difficulty != nil && s.Difficulty.Sign() <= 0 {
+		return fmt.Errorf("difficulty must be greater than zero")
+	}
+
+	if s.Timestamp < 0 {
+		return fmt.Errorf("timestamp is not valid")
+	}
+
+	if len(s.Allocs) == 0 {
+		return fmt.Errorf("at least one allocation is required")
+	}
+
+	for i, alloc := range s.Allocs {
+		if crypto.IsValidAddr(alloc.Address.String()) != nil {
+			return fmt.Errorf("allocation %d: address is not valid", i)
+		}
+		if !alloc.Balance.IsDecimal() || alloc.Balance.Decimal().Sign() <= 0 {
+			return fmt.Errorf("allocation %d: balance must be a positive number", i)
+		}
+	}
+
+	return nil
+}
+
+// GenerateGenesisBlock creates a genesis block described by
+// spec and signed by the creator key. The allocations are
+// executed against db to compute the state root; it should
+// be an empty, temporary database. chainSpec is the chain
+// specification of the network. The main network's chain
+// specification is used if chainSpec is nil.
+func GenerateGenesisBlock(spec *GenesisSpec, chainSpec *params.ChainSpec,
+	db elldb.DB, cfg *config.EngineConfig, log logger.Logger) (types.Block, error) {
+
+	if err := spec.validate(); err != nil {
+		return nil, err
+	}
+
+	if chainSpec == nil {
+		chainSpec = params.DefaultChainSpec()
+	}
+
+	privKey, _ := crypto.PrivKeyFromBase58(spec.CreatorKey)
+	creator := crypto.NewKeyFromPrivKey(privKey)
+
+	difficulty := chainSpec.GenesisDifficulty
+	if spec.Difficulty != nil {
+		difficulty = spec.Difficulty
+	}
+
+	timestamp := spec.Timestamp
+	if timestamp == 0 {
+		timestamp = time.Now().Unix()
+	}
+
+	var txs []types.Transaction
+	for _, alloc := range spec.Allocs {
+		txs = append(txs, core.NewTx(core.TxTypeAlloc, 0, alloc.Address, creator,
+			alloc.Balance, "0", timestamp))
+	}
+
+	// The genesis block is not sealed, so
+	// no consensus engine is required
+	bc := New(txpool.New(params.PoolCapacity, chainSpec), chainSpec, nil, cfg, log)
+	bc.SetDB(db)
+
+	return bc.Generate(&types.GenerateBlockParams{
+		Transactions:            txs,
+		Creator:                 creator,
+		Nonce:                   util.EncodeNonce(1),
+		Difficulty:              difficulty,
+		OverrideTotalDifficulty: difficulty,
+		OverrideTimestamp:       timestamp,
+	}, &common.OpChainer{Chain: NewChain("genesis", db, cfg, log)})
+}
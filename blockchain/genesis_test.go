@@ -0,0 +1,136 @@
+package blockchain
+
+import (
+	"math/big"
+	"os"
+
+	"github.com/ellcrys/elld/blockchain/txpool"
+	"github.com/ellcrys/elld/config"
+	"github.com/ellcrys/elld/crypto"
+	"github.com/ellcrys/elld/elldb"
+	"github.com/ellcrys/elld/params"
+	"github.com/ellcrys/elld/testutil"
+	"github.com/ellcrys/elld/types"
+	"github.com/ellcrys/elld/util"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/shopspring/decimal"
+)
+
+var _ = Describe("Genesis", func() {
+
+	var err error
+	var cfg *config.EngineConfig
+	var db elldb.DB
+	var creator, receiver *crypto.Key
+	var spec *GenesisSpec
+
+	BeforeEach(func() {
+		cfg, err = testutil.SetTestCfg()
+		Expect(err).To(BeNil())
+
+		db = elldb.NewDB(cfg.NetDataDir())
+		err = db.Open(util.RandString(5))
+		Expect(err).To(BeNil())
+
+		creator = crypto.NewKeyFromIntSeed(1)
+		receiver = crypto.NewKeyFromIntSeed(2)
+
+		spec = &GenesisSpec{
+			CreatorKey: creator.PrivKey().Base58(),
+			Difficulty: big.NewInt(131072),
+			Timestamp:  1547485409,
+			Allocs: []*GenesisAlloc{
+				{Address: receiver.Addr(), Balance: "100"},
+			},
+		}
+	})
+
+	AfterEach(func() {
+		db.Close()
+		err = os.RemoveAll(cfg.DataDir())
+		Expect(err).To(BeNil())
+	})
+
+	Describe(".GenerateGenesisBlock", func() {
+
+		It("should return error if the creator key is not set", func() {
+			spec.CreatorKey = ""
+			_, err := GenerateGenesisBlock(spec, nil, db, cfg, log)
+			Expect(err).ToNot(BeNil())
+			Expect(err.Error()).To(Equal("creator key is required"))
+		})
+
+		It("should return error if no allocation is set", func() {
+			spec.Allocs = nil
+			_, err := GenerateGenesisBlock(spec, nil, db, cfg, log)
+			Expect(err).ToNot(BeNil())
+			Expect(err.Error()).To(Equal("at least one allocation is required"))
+		})
+
+		It("should return error if an allocation address is not valid", func() {
+			spec.Allocs[0].Address = "invalid"
+			_, err := GenerateGenesisBlock(spec, nil, db, cfg, log)
+			Expect(err).ToNot(BeNil())
+			Expect(err.Error()).To(Equal("allocation 0: address is not valid"))
+		})
+
+		It("should return error if an allocation balance is not positive", func() {
+			spec.Allocs[0].Balance = "0"
+			_, err := GenerateGenesisBlock(spec, nil, db, cfg, log)
+			Expect(err).ToNot(BeNil())
+			Expect(err.Error()).To(Equal("allocation 0: balance must be a positive number"))
+		})
+
+		It("should use the genesis difficulty if difficulty is not set", func() {
+			spec.Difficulty = nil
+			block, err := GenerateGenesisBlock(spec, nil, db, cfg, log)
+			Expect(err).To(BeNil())
+			Expect(block.GetHeader().GetDifficulty()).To(Equal(params.GenesisDifficulty))
+		})
+
+		It("should use the genesis difficulty of the chain specification if difficulty is not set", func() {
+			spec.Difficulty = nil
+			chainSpec := params.DefaultChainSpec()
+			chainSpec.GenesisDifficulty = big.NewInt(1000)
+			block, err := GenerateGenesisBlock(spec, chainSpec, db, cfg, log)
+			Expect(err).To(BeNil())
+			Expect(block.GetHeader().GetDifficulty()).To(Equal(big.NewInt(1000)))
+		})
+
+		Context("with a valid spec", func() {
+
+			var block types.Block
+
+			BeforeEach(func() {
+				block, err = GenerateGenesisBlock(spec, nil, db, cfg, log)
+				Expect(err).To(BeNil())
+			})
+
+			It("should create a signed genesis block with the allocations", func() {
+				Expect(block.GetNumber()).To(Equal(uint64(1)))
+				Expect(block.GetHeader().GetTimestamp()).To(Equal(spec.Timestamp))
+				Expect(block.GetHeader().GetDifficulty()).To(Equal(spec.Difficulty))
+				Expect(block.GetHeader().GetCreatorPubKey()).To(Equal(util.String(creator.PubKey().Base58())))
+				Expect(block.GetSignature()).ToNot(BeEmpty())
+				Expect(block.GetTransactions()).To(HaveLen(1))
+				Expect(block.GetTransactions()[0].GetTo()).To(Equal(receiver.Addr()))
+			})
+
+			It("should be usable as the genesis block of a blockchain", func() {
+				genesisDB := elldb.NewDB(cfg.NetDataDir())
+				Expect(genesisDB.Open(util.RandString(5))).To(BeNil())
+				defer genesisDB.Close()
+
+				bc := New(txpool.New(100, nil), nil, newEngine(nil), cfg, log)
+				bc.SetDB(genesisDB)
+				bc.SetGenesisBlock(block)
+				Expect(bc.Up()).To(BeNil())
+
+				account, err := bc.GetAccount(receiver.Addr())
+				Expect(err).To(BeNil())
+				Expect(account.GetBalance().Decimal().Equal(decimal.New(100, 0))).To(BeTrue())
+			})
+		})
+	})
+})
@@ -0,0 +1,92 @@
+package blockchain
+
+import (
+	"fmt"
+
+	"github.com/ellcrys/elld/crypto"
+	"github.com/ellcrys/elld/params"
+	"github.com/ellcrys/elld/types"
+	"github.com/ellcrys/elld/types/core"
+	"github.com/ellcrys/elld/util"
+)
+
+// isNameTx checks whether a transaction
+// registers, renews or transfers a name
+func isNameTx(tx types.Transaction) bool {
+	switch tx.GetType() {
+	case core.TxTypeNameRegister, core.TxTypeNameRenew, core.TxTypeNameTransfer:
+		return true
+	}
+	return false
+}
+
+// canSendToName checks whether the recipient
+// of a transaction can be a registered name
+func canSendToName(tx types.Transaction) bool {
+	return tx.GetType() == core.TxTypeBalance ||
+		tx.GetType() == core.TxTypeMultiSigSpend
+}
+
+// isName checks whether a recipient is a
+// name rather than an address
+func isName(to util.String) bool {
+	return crypto.IsValidAddr(to.String()) != nil &&
+		core.IsValidName(to.String())
+}
+
+// checkNameTx checks whether a name transaction can be
+// included in a block with the given number. record
+// is the current record of the name or nil if the
+// name has never been registered.
+func checkNameTx(tx types.Transaction, record *types.NameRecord,
+	blockNumber uint64) error {
+
+	registered := record != nil && !record.IsExpired(blockNumber)
+
+	switch tx.GetType() {
+	case core.TxTypeNameRegister:
+		if registered {
+			return fmt.Errorf("name is already registered")
+		}
+	case core.TxTypeNameRenew, core.TxTypeNameTransfer:
+		if !registered {
+			return fmt.Errorf("name is not registered")
+		}
+		if !record.Owner.Equal(tx.GetFrom()) {
+			return fmt.Errorf("sender is not the owner of the name")
+		}
+	}
+
+	return nil
+}
+
+// applyNameTx returns the record of the name of a name
+// transaction after the transaction is applied.
+// Expects the transaction to have passed checkNameTx.
+func applyNameTx(tx types.Transaction, record *types.NameRecord,
+	blockNumber uint64) *types.NameRecord {
+
+	switch tx.GetType() {
+	case core.TxTypeNameRenew:
+		return &types.NameRecord{
+			Name:      record.Name,
+			Owner:     record.Owner,
+			Address:   tx.GetTo(),
+			ExpiresAt: record.ExpiresAt + params.NameRegistrationPeriod,
+		}
+	case core.TxTypeNameTransfer:
+		return &types.NameRecord{
+			Name:      record.Name,
+			Owner:     tx.GetTo(),
+			Address:   tx.GetTo(),
+			ExpiresAt: record.ExpiresAt,
+		}
+	default:
+		return &types.NameRecord{
+			Name:      tx.GetName(),
+			Owner:     tx.GetFrom(),
+			Address:   tx.GetTo(),
+			ExpiresAt: blockNumber + params.NameRegistrationPeriod,
+		}
+	}
+}
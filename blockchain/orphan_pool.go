@@ -0,0 +1,204 @@
+package blockchain
+
+import (
+	"sync"
+	"time"
+
+	"github.com/ellcrys/elld/types"
+	"github.com/ellcrys/elld/types/core"
+)
+
+// orphanEntry is an orphan block held by an OrphanPool
+type orphanEntry struct {
+	block      types.Block
+	peerID     string
+	expiration time.Time
+}
+
+// OrphanPool holds blocks whose parents are unknown.
+//
+// The pool is bounded. When it is full, the oldest
+// orphan is removed to make room for a new one. Each
+// peer can only have a limited number of orphans in
+// the pool. When a peer reaches its quota, its oldest
+// orphan is removed. Orphans are also removed once
+// they expire.
+//
+// Implements types.CacheReader
+type OrphanPool struct {
+	sync.RWMutex
+	capacity  int
+	peerQuota int
+	ttl       time.Duration
+	entries   map[string]*orphanEntry
+	order     []string
+	peerCount map[string]int
+}
+
+// NewOrphanPool creates an OrphanPool that holds at
+// most capacity orphans and at most peerQuota orphans
+// from a single peer. Orphans expire after ttl.
+func NewOrphanPool(capacity, peerQuota int, ttl time.Duration) *OrphanPool {
+	return &OrphanPool{
+		capacity:  capacity,
+		peerQuota: peerQuota,
+		ttl:       ttl,
+		entries:   make(map[string]*orphanEntry),
+		peerCount: make(map[string]int),
+	}
+}
+
+// getPeerID returns the ID of the peer that
+// broadcast the block. Blocks without
+// a broadcaster share an empty peer ID.
+func getPeerID(block types.Block) string {
+	if b, ok := block.(*core.Block); ok {
+		if broadcaster := b.GetBroadcaster(); broadcaster != nil {
+			return broadcaster.StringID()
+		}
+	}
+	return ""
+}
+
+// Add adds an orphan block to the pool. It returns
+// false if the block already exists in the pool.
+func (p *OrphanPool) Add(block types.Block) bool {
+	return p.add(block, getPeerID(block))
+}
+
+// add adds an orphan block broadcast by the given peer
+func (p *OrphanPool) add(block types.Block, peerID string) bool {
+	p.Lock()
+	defer p.Unlock()
+
+	p.removeExpired()
+
+	key := block.GetHashAsHex()
+	if _, ok := p.entries[key]; ok {
+		return false
+	}
+
+	// Remove the oldest orphan of the peer
+	// if the peer has reached its quota
+	if p.peerQuota > 0 && p.peerCount[peerID] >= p.peerQuota {
+		for _, k := range p.order {
+			if p.entries[k].peerID == peerID {
+				p.remove(k)
+				break
+			}
+		}
+	}
+
+	// Remove the oldest orphan if the pool is full
+	if p.capacity > 0 && len(p.entries) >= p.capacity {
+		p.remove(p.order[0])
+	}
+
+	p.entries[key] = &orphanEntry{
+		block:      block,
+		peerID:     peerID,
+		expiration: time.Now().Add(p.ttl),
+	}
+	p.order = append(p.order, key)
+	p.peerCount[peerID]++
+
+	return true
+}
+
+// remove removes an orphan.
+// It must be called with the lock held.
+func (p *OrphanPool) remove(key string) {
+	entry, ok := p.entries[key]
+	if !ok {
+		return
+	}
+
+	delete(p.entries, key)
+	for i, k := range p.order {
+		if k == key {
+			p.order = append(p.order[:i], p.order[i+1:]...)
+			break
+		}
+	}
+
+	p.peerCount[entry.peerID]--
+	if p.peerCount[entry.peerID] <= 0 {
+		delete(p.peerCount, entry.peerID)
+	}
+}
+
+// removeExpired removes expired orphans.
+// It must be called with the lock held.
+func (p *OrphanPool) removeExpired() {
+	if p.ttl <= 0 {
+		return
+	}
+	now := time.Now()
+	for _, k := range append([]string{}, p.order...) {
+		if now.After(p.entries[k].expiration) {
+			p.remove(k)
+		}
+	}
+}
+
+// Remove removes an orphan block
+func (p *OrphanPool) Remove(key interface{}) {
+	p.Lock()
+	defer p.Unlock()
+	if k, ok := key.(string); ok {
+		p.remove(k)
+	}
+}
+
+// Get gets an orphan block by its hash (hex encoded).
+// It returns nil if the orphan does not exist.
+func (p *OrphanPool) Get(key interface{}) interface{} {
+	p.RLock()
+	defer p.RUnlock()
+	k, _ := key.(string)
+	entry, ok := p.entries[k]
+	if !ok || (p.ttl > 0 && time.Now().After(entry.expiration)) {
+		return nil
+	}
+	return entry.block
+}
+
+// Peek is like Get
+func (p *OrphanPool) Peek(key interface{}) interface{} {
+	return p.Get(key)
+}
+
+// Has checks whether an orphan block exists
+func (p *OrphanPool) Has(key interface{}) bool {
+	return p.Get(key) != nil
+}
+
+// Keys returns the hashes (hex encoded) of the
+// orphan blocks, oldest first
+func (p *OrphanPool) Keys() []interface{} {
+	p.Lock()
+	defer p.Unlock()
+	p.removeExpired()
+	var keys []interface{}
+	for _, k := range p.order {
+		keys = append(keys, k)
+	}
+	return keys
+}
+
+// Len returns the number of orphan blocks
+func (p *OrphanPool) Len() int {
+	p.Lock()
+	defer p.Unlock()
+	p.removeExpired()
+	return len(p.entries)
+}
+
+// PeerLen returns the number of orphan
+// blocks broadcast by a peer
+func (p *OrphanPool) PeerLen(peerID string) int {
+	p.Lock()
+	defer p.Unlock()
+	p.removeExpired()
+	return p.peerCount[peerID]
+}
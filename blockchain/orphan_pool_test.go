@@ -0,0 +1,87 @@
+package blockchain
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/ellcrys/elld/types/core"
+	"github.com/ellcrys/elld/util"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("OrphanPool", func() {
+
+	makeBlock := func(n int) *core.Block {
+		return &core.Block{
+			Header: &core.Header{Number: uint64(n)},
+			Hash:   util.StrToHash(fmt.Sprintf("block_%d", n)),
+		}
+	}
+
+	Describe(".Add", func() {
+		It("should add an orphan block", func() {
+			pool := NewOrphanPool(10, 5, time.Hour)
+			block := makeBlock(1)
+			Expect(pool.Add(block)).To(BeTrue())
+			Expect(pool.Has(block.GetHashAsHex())).To(BeTrue())
+			Expect(pool.Get(block.GetHashAsHex())).To(Equal(block))
+			Expect(pool.Len()).To(Equal(1))
+		})
+
+		It("should return false if the block already exists", func() {
+			pool := NewOrphanPool(10, 5, time.Hour)
+			block := makeBlock(1)
+			Expect(pool.Add(block)).To(BeTrue())
+			Expect(pool.Add(block)).To(BeFalse())
+			Expect(pool.Len()).To(Equal(1))
+		})
+
+		It("should remove the oldest orphan when the pool is full", func() {
+			pool := NewOrphanPool(2, 5, time.Hour)
+			pool.add(makeBlock(1), "peer_a")
+			pool.add(makeBlock(2), "peer_b")
+			pool.add(makeBlock(3), "peer_c")
+			Expect(pool.Len()).To(Equal(2))
+			Expect(pool.Has(makeBlock(1).GetHashAsHex())).To(BeFalse())
+			Expect(pool.Keys()).To(Equal([]interface{}{
+				makeBlock(2).GetHashAsHex(),
+				makeBlock(3).GetHashAsHex(),
+			}))
+		})
+
+		It("should remove the oldest orphan of a peer that reached its quota", func() {
+			pool := NewOrphanPool(10, 2, time.Hour)
+			pool.add(makeBlock(1), "peer_a")
+			pool.add(makeBlock(2), "peer_b")
+			pool.add(makeBlock(3), "peer_a")
+			pool.add(makeBlock(4), "peer_a")
+			Expect(pool.Len()).To(Equal(3))
+			Expect(pool.PeerLen("peer_a")).To(Equal(2))
+			Expect(pool.PeerLen("peer_b")).To(Equal(1))
+			Expect(pool.Has(makeBlock(1).GetHashAsHex())).To(BeFalse())
+			Expect(pool.Has(makeBlock(2).GetHashAsHex())).To(BeTrue())
+		})
+	})
+
+	Describe(".Remove", func() {
+		It("should remove an orphan block", func() {
+			pool := NewOrphanPool(10, 5, time.Hour)
+			pool.add(makeBlock(1), "peer_a")
+			pool.Remove(makeBlock(1).GetHashAsHex())
+			Expect(pool.Len()).To(Equal(0))
+			Expect(pool.PeerLen("peer_a")).To(Equal(0))
+		})
+	})
+
+	Describe("expiry", func() {
+		It("should not return expired orphan blocks", func() {
+			pool := NewOrphanPool(10, 5, 10*time.Millisecond)
+			pool.add(makeBlock(1), "peer_a")
+			time.Sleep(20 * time.Millisecond)
+			Expect(pool.Get(makeBlock(1).GetHashAsHex())).To(BeNil())
+			Expect(pool.Len()).To(Equal(0))
+			Expect(pool.PeerLen("peer_a")).To(Equal(0))
+		})
+	})
+})
@@ -36,6 +36,15 @@ func addOp(ops []common.Transition, op common.Transition) []common.Transition {
 // a recipient's account. The nonce of the sender
 // account is incremented.
 //
+// It also processes TxTypeMultiSigRegister transactions,
+// which additionally turn the recipient account into a
+// multi-signature account, and TxTypeMultiSigSpend
+// transactions, which must be sent from one.
+//
+// If the recipient is a name, the value is sent to
+// the address the name resolves to as at the block
+// with the given number.
+//
 // The recipient account is searched in the
 // given ops which contains other transition objects
 // effected by other transactions in same block.
@@ -43,10 +52,25 @@ func addOp(ops []common.Transition, op common.Transition) []common.Transition {
 // It will create a OpCreateAccount transition
 // object if the recipient account does not exist.
 func (b *Blockchain) processBalanceTx(tx types.Transaction, ops []common.Transition,
-	chain types.Chainer, opts ...types.CallOp) ([]common.Transition, error) {
+	chain types.Chainer, blockNumber uint64, opts ...types.CallOp) ([]common.Transition, error) {
 	var err error
 	var txOps []common.Transition
 	var senderAcct, recipientAcct types.Account
+	var to = tx.GetTo()
+
+	// Resolve the address of a recipient name
+	if canSendToName(tx) && isName(to) {
+		record, err := b.findName(to, ops, chain, opts...)
+		if err != nil {
+			if err != core.ErrNameNotFound {
+				return nil, fmt.Errorf("failed to get name: %s", err)
+			}
+		}
+		if record == nil || record.IsExpired(blockNumber) {
+			return nil, fmt.Errorf("recipient name is not registered")
+		}
+		to = record.Address
+	}
 
 	// Find the current account object in previous operations
 	// passed via ops. If an account has been updated by
@@ -58,7 +82,7 @@ func (b *Blockchain) processBalanceTx(tx types.Transaction, ops []common.Transit
 			senderAcct = opNewBalance.Account
 		}
 		if opNewBalance, yes := prevOp.(*common.OpNewAccountBalance); yes &&
-			opNewBalance.Address() == tx.GetTo() {
+			opNewBalance.Address() == to {
 			recipientAcct = opNewBalance.Account
 		}
 	}
@@ -73,35 +97,55 @@ func (b *Blockchain) processBalanceTx(tx types.Transaction, ops []common.Transit
 		}
 	}
 
+	if tx.GetType() == core.TxTypeMultiSigSpend &&
+		senderAcct.GetType() != core.AccountTypeMultiSig {
+		return nil, fmt.Errorf("sender is not a multi-signature account")
+	}
+
 	// If the sender and recipient account
 	// are the same, assign the sender account
 	// to the recipient account variable.
-	if tx.GetFrom().Equal(tx.GetTo()) {
+	if tx.GetFrom().Equal(to) {
 		recipientAcct = senderAcct
 	}
 
+	// Convert the amount to be sent to decimal
+	sendingAmount := tx.GetValue().Decimal()
+
 	// If we don't know the recipient account yet,
-	// we must fetch it from the database or create it
+	// we must fetch it from the database or create it.
+	// Once ForkNoEmptyAccounts is active, the account is
+	// not created if the transaction transfers no value.
 	if recipientAcct == nil {
-		recipientAcct, err = b.NewWorldReader().GetAccount(chain, tx.GetTo(), opts...)
+		recipientAcct, err = b.NewWorldReader().GetAccount(chain, to, opts...)
 		if err != nil {
 			if err != core.ErrAccountNotFound {
 				return nil, fmt.Errorf("failed to retrieve recipient account: %s", err)
 			}
-			recipientAcct = &core.Account{
-				Type:    core.AccountTypeBalance,
-				Address: tx.GetTo(),
-				Balance: "0",
+			recipientAcct = nil
+			if !sendingAmount.IsZero() || tx.GetType() == core.TxTypeMultiSigRegister ||
+				!b.spec.IsActive(params.ForkNoEmptyAccounts, blockNumber) {
+				recipientAcct = &core.Account{
+					Type:    core.AccountTypeBalance,
+					Address: to,
+					Balance: "0",
+				}
+				txOps = append(txOps, &common.OpCreateAccount{
+					OpBase:  &common.OpBase{Addr: to},
+					Account: recipientAcct,
+				})
 			}
-			txOps = append(txOps, &common.OpCreateAccount{
-				OpBase:  &common.OpBase{Addr: tx.GetTo()},
-				Account: recipientAcct,
-			})
 		}
 	}
 
-	// Convert the amount to be sent to decimal
-	sendingAmount := tx.GetValue().Decimal()
+	// Register the recipient as a multi-signature account
+	if tx.GetType() == core.TxTypeMultiSigRegister {
+		if recipientAcct.GetMultiSig() != nil {
+			return nil, fmt.Errorf("multi-signature account is already registered")
+		}
+		recipientAcct.SetMultiSig(tx.GetMultiSig())
+	}
+
 	fee := tx.GetFee().Decimal()
 	deductable := sendingAmount.Add(fee)
 
@@ -122,14 +166,16 @@ func (b *Blockchain) processBalanceTx(tx types.Transaction, ops []common.Transit
 	})
 
 	// Add an operation to set a new balance
-	// of the recipient
-	newRecipientBal := recipientAcct.GetBalance().Decimal().
-		Add(sendingAmount).StringFixed(params.Decimals)
-	recipientAcct.SetBalance(util.String(newRecipientBal))
-	txOps = append(txOps, &common.OpNewAccountBalance{
-		OpBase:  &common.OpBase{Addr: tx.GetTo()},
-		Account: recipientAcct,
-	})
+	// of the recipient if it has an account
+	if recipientAcct != nil {
+		newRecipientBal := recipientAcct.GetBalance().Decimal().
+			Add(sendingAmount).StringFixed(params.Decimals)
+		recipientAcct.SetBalance(util.String(newRecipientBal))
+		txOps = append(txOps, &common.OpNewAccountBalance{
+			OpBase:  &common.OpBase{Addr: to},
+			Account: recipientAcct,
+		})
+	}
 
 	// increment the sender's nonce
 	senderAcct.IncrNonce()
@@ -137,6 +183,60 @@ func (b *Blockchain) processBalanceTx(tx types.Transaction, ops []common.Transit
 	return txOps, nil
 }
 
+// findName finds the current record of a name. The
+// record is searched in the given ops which contains
+// other transition objects effected by other
+// transactions in same block before the world state
+// is queried.
+func (b *Blockchain) findName(name util.String, ops []common.Transition,
+	chain types.Chainer, opts ...types.CallOp) (*types.NameRecord, error) {
+
+	var record *types.NameRecord
+	for _, prevOp := range ops {
+		if opNewName, yes := prevOp.(*common.OpNewName); yes &&
+			opNewName.Address() == name {
+			record = opNewName.Record
+		}
+	}
+
+	if record != nil {
+		return record, nil
+	}
+
+	return b.NewWorldReader().GetName(chain, name, opts...)
+}
+
+// processNameTx processes TxTypeNameRegister,
+// TxTypeNameRenew and TxTypeNameTransfer transactions.
+// The value of the transaction is sent to the recipient
+// like a TxTypeBalance transaction. It will create an
+// OpNewName transition object with the new record of
+// the name.
+func (b *Blockchain) processNameTx(tx types.Transaction, ops []common.Transition,
+	chain types.Chainer, blockNumber uint64, opts ...types.CallOp) ([]common.Transition, error) {
+
+	record, err := b.findName(tx.GetName(), ops, chain, opts...)
+	if err != nil {
+		if err != core.ErrNameNotFound {
+			return nil, fmt.Errorf("failed to get name: %s", err)
+		}
+	}
+
+	if err := checkNameTx(tx, record, blockNumber); err != nil {
+		return nil, err
+	}
+
+	txOps, err := b.processBalanceTx(tx, ops, chain, blockNumber, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(txOps, &common.OpNewName{
+		OpBase: &common.OpBase{Addr: tx.GetName()},
+		Record: applyNameTx(tx, record, blockNumber),
+	}), nil
+}
+
 // processAllocCoinTx process a TxTypeAllocCoin. It
 // allocates value set in a transaction to specific
 // account.
@@ -213,14 +313,24 @@ func (b *Blockchain) opsToStateObjects(block types.Block, chain types.Chainer,
 			stateObjs = append(stateObjs, &common.StateObject{
 				Key: common.MakeKeyAccount(block.GetNumber(), chain.GetID().Bytes(),
 					_op.Address().Bytes()),
-				Value: util.ObjectToBytes(_op.Account),
+				TreeKey: _op.Address().Bytes(),
+				Value:   util.ObjectToBytes(_op.Account),
 			})
 
 		case *common.OpNewAccountBalance:
 			stateObjs = append(stateObjs, &common.StateObject{
 				Key: common.MakeKeyAccount(block.GetNumber(),
 					chain.GetID().Bytes(), _op.Address().Bytes()),
-				Value: util.ObjectToBytes(_op.Account),
+				TreeKey: _op.Address().Bytes(),
+				Value:   util.ObjectToBytes(_op.Account),
+			})
+
+		case *common.OpNewName:
+			stateObjs = append(stateObjs, &common.StateObject{
+				Key: common.MakeKeyName(block.GetNumber(),
+					chain.GetID().Bytes(), _op.Address().Bytes()),
+				TreeKey: common.MakeTreeKeyName(_op.Address().Bytes()),
+				Value:   util.ObjectToBytes(_op.Record),
 			})
 
 		default:
@@ -233,20 +343,39 @@ func (b *Blockchain) opsToStateObjects(block types.Block, chain types.Chainer,
 
 // ProcessTransactions computes the state transition operations
 // for each transactions that must be applied to the state tree
-// and world state
+// and world state.
+//
+// The number of the block the transactions are included in
+// can be passed using common.OpBlockNumber. If not provided,
+// the block after the tip of the chain is assumed.
 func (b *Blockchain) ProcessTransactions(txs []types.Transaction, chain types.Chainer,
 	opts ...types.CallOp) ([]common.Transition, error) {
 	b.chainLock.RLock()
 	defer b.chainLock.RUnlock()
 
+	var blockNumber = common.GetBlockNumberOp(opts...)
+	if blockNumber == 0 {
+		blockNumber = 1
+		tip, err := chain.Current(opts...)
+		if err != nil {
+			if err != core.ErrBlockNotFound {
+				return nil, err
+			}
+		} else {
+			blockNumber = tip.GetNumber() + 1
+		}
+	}
+
 	var ops = common.GetTransitions(opts...)
 	for i, tx := range txs {
 		var err error
 		var newOps []common.Transition
 
 		switch tx.GetType() {
-		case core.TxTypeBalance:
-			newOps, err = b.processBalanceTx(tx, ops, chain, opts...)
+		case core.TxTypeBalance, core.TxTypeMultiSigRegister, core.TxTypeMultiSigSpend:
+			newOps, err = b.processBalanceTx(tx, ops, chain, blockNumber, opts...)
+		case core.TxTypeNameRegister, core.TxTypeNameRenew, core.TxTypeNameTransfer:
+			newOps, err = b.processNameTx(tx, ops, chain, blockNumber, opts...)
 		case core.TxTypeAlloc:
 			newOps, err = b.processAllocCoinTx(tx, ops, chain, opts...)
 		}
@@ -361,6 +490,26 @@ func (b *Blockchain) maybeAcceptBlock(block types.Block, chain *Chain,
 	}
 
 process:
+	// A block cannot be added to a branch that forks
+	// off the main chain before a checkpoint, since
+	// such a branch can never become the main chain.
+	if parentBlock != nil && (createNewChain || chain.HasParent()) {
+		forkNumber := parentBlock.GetNumber()
+		if !createNewChain {
+			forkNumber = chain.GetParentBlock().GetNumber()
+		}
+		conflicts, err := b.forksBeforeCheckpoint(forkNumber, opts...)
+		if err != nil {
+			return nil, err
+		} else if conflicts {
+			b.log.Info("Block belongs to a branch that conflicts with a checkpoint",
+				"BlockNo", block.GetNumber(),
+				"ForkBlockNo", forkNumber)
+			b.addRejectedBlock(block)
+			return nil, core.ErrCheckpointConflict
+		}
+	}
+
 	// Verify that the block's PoW for non-genesis blocks is valid.
 	// Only do this in production or development mode
 	if (b.cfg.Node.Mode != config.ModeTest) && block.GetNumber() > 1 {
@@ -439,13 +588,13 @@ process:
 
 	// Compare the state root in the block header with
 	// the root obtained from the mock execution of the block.
-	if !block.GetHeader().GetStateRoot().Equal(newStateRoot) {
+	if errs := bValidator.CheckStateRoot(newStateRoot); len(errs) > 0 {
 		txOp.SetFinishable(!hasInjectTx).Rollback()
 		b.log.Error("Compute state root and block state root do not match",
 			"BlockNo", block.GetNumber(),
 			"BlockStateRoot", block.GetHeader().GetStateRoot().HexStr(),
 			"ComputedStateRoot", newStateRoot.HexStr())
-		return nil, core.ErrBlockStateRootInvalid
+		return nil, errs[0]
 	}
 
 	// We need to update the world state using
@@ -489,6 +638,10 @@ commit:
 		return nil, fmt.Errorf("commit error: %s", err)
 	}
 
+	// Apply the block's state objects
+	// to the cached state tree
+	chain.updateStateTree(block, stateObjs)
+
 	// Cache the chain if it has
 	// not been seen before
 	b.addChain(chain)
@@ -497,16 +650,27 @@ commit:
 	// This could potentially cause a reorganization.
 	// We will skip this step if a reorganization is ongoing
 	if !b.reOrgIsActive() {
-		var err error
 		if !hasInjectTx {
 			txOp = nil
 		}
 
-		err = b.decideBestChain(txOp)
+		detachedTxs, err := b.decideBestChain(txOp)
 		if err != nil {
 			b.log.Error("Failed to decide best chain", "Err", err)
 			return nil, fmt.Errorf("failed to choose best chain: %s", err)
 		}
+
+		// Return the transactions of the detached blocks
+		// to the pool so they can be mined again. If the
+		// transaction was injected, we must wait for the
+		// caller to commit it.
+		if len(detachedTxs) > 0 {
+			if hasInjectTx {
+				txOp.AfterCommit(func() { b.repoolTxs(detachedTxs) })
+			} else {
+				b.repoolTxs(detachedTxs)
+			}
+		}
 	}
 
 	// When the chain is the best chain, emit a new block
@@ -561,6 +725,16 @@ func (b *Blockchain) ProcessBlock(block types.Block,
 		return nil, core.ErrBlockRejected
 	}
 
+	// Reject the block if it does not match the
+	// checkpoint at its height
+	if err := b.CheckCheckpoint(block.GetNumber(), block.GetHash()); err != nil {
+		b.log.Info("Block does not match checkpoint",
+			"BlockNo", block.GetNumber(),
+			"Hash", block.GetHash().SS())
+		b.addRejectedBlock(block)
+		return nil, err
+	}
+
 	// Check whether the block has previously been detected as an orphan.
 	// We do not need to go re-process this block if it is an orphan.
 	if b.isOrphanBlock(block.GetHash()) {
@@ -598,7 +772,8 @@ func (b *Blockchain) execBlock(chain types.Chainer,
 
 	// Process the transactions to produce a series of transitions
 	// that must be applied to the blockchain state.
-	ops, err := b.ProcessTransactions(block.GetTransactions(), chain, opts...)
+	ops, err := b.ProcessTransactions(block.GetTransactions(), chain,
+		append([]types.CallOp{common.OpBlockNumber(block.GetNumber())}, opts...)...)
 	if err != nil {
 		return util.EmptyHash, nil, fmt.Errorf("transaction error: %s", err)
 	}
@@ -612,24 +787,20 @@ func (b *Blockchain) execBlock(chain types.Chainer,
 		return util.EmptyHash, nil, err
 	}
 
-	// Get a new state tree. The tree is
-	// seeded with the state root of the parent block
+	// Get the state tree. The tree contains the
+	// accounts as at the tip of the chain
 	tree, err := chain.NewStateTree(opts...)
 	if err != nil {
 		return util.EmptyHash, nil,
 			fmt.Errorf("failed to create new state tree: %s", err)
 	}
 
-	// Add the state value into the tree.
+	// Apply the state objects to the tree.
 	for _, so := range stateObjs {
-		tree.Add(common.TreeItem(so.Value))
-	}
-
-	// Build the tree and compute new state root
-	if err = tree.Build(); err != nil {
-		return util.EmptyHash, nil, err
+		tree.Set(so.TreeKey, so.Value)
 	}
 
+	// Compute the new state root
 	root = tree.Root()
 	return
 }
@@ -666,7 +837,13 @@ func (b *Blockchain) processOrphanBlocks(latestBlockHash string) error {
 
 			// find an orphan block with a parent hash that
 			// is same has the latestBlockHash
-			orphanBlock := b.orphanBlocks.Peek(oBKey).(types.Block)
+			// The orphan may have expired since
+			// the keys were retrieved
+			orphanBlock, ok := b.orphanBlocks.Peek(oBKey).(types.Block)
+			if !ok {
+				continue
+			}
+
 			if orphanBlock.GetHeader().GetParentHash().HexStr() != curParentHash {
 				continue
 			}
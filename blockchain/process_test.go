@@ -11,6 +11,7 @@ import (
 	"github.com/ellcrys/elld/config"
 	"github.com/ellcrys/elld/crypto"
 	"github.com/ellcrys/elld/elldb"
+	"github.com/ellcrys/elld/params"
 	"github.com/ellcrys/elld/testutil"
 	"github.com/ellcrys/elld/types"
 	"github.com/ellcrys/elld/types/core"
@@ -41,7 +42,7 @@ var _ = Describe("Process", func() {
 		sender = crypto.NewKeyFromIntSeed(1)
 		receiver = crypto.NewKeyFromIntSeed(2)
 
-		bc = New(txpool.New(100), cfg, log)
+		bc = New(txpool.New(100, nil), nil, newEngine(nil), cfg, log)
 		bc.SetDB(db)
 		bc.SetCoinbase(crypto.NewKeyFromIntSeed(1234))
 	})
@@ -211,6 +212,44 @@ var _ = Describe("Process", func() {
 			})
 		})
 
+		Context("recipient does not have an account and the transaction transfers no value", func() {
+
+			var txs []types.Transaction
+
+			BeforeEach(func() {
+				txs = []types.Transaction{
+					&core.Transaction{
+						Type: 1, Nonce: 1,
+						To:           "e6i7rxApBYUt7w94gGDKTz45A5J567JfkS",
+						From:         sender.Addr(),
+						SenderPubKey: "48d9u6L7tWpSVYmTE4zBDChMUasjP5pvoXE7kPw5HbJnXRnZBNC",
+						Value:        "0",
+						Timestamp:    1532730724,
+						Fee:          "0.1", Sig: []uint8{},
+						Hash: util.Hash{},
+					},
+				}
+			})
+
+			It("should create the recipient account", func() {
+				ops, err := bc.ProcessTransactions(txs, genesisChain)
+				Expect(err).To(BeNil())
+				Expect(ops).To(HaveLen(3))
+				Expect(ops[0]).To(BeAssignableToTypeOf(&common.OpCreateAccount{}))
+			})
+
+			When("ForkNoEmptyAccounts is active", func() {
+				It("should not create the recipient account", func() {
+					bc.spec.Forks = []params.Fork{{Name: params.ForkNoEmptyAccounts, ActivationHeight: 2}}
+					ops, err := bc.ProcessTransactions(txs, genesisChain)
+					Expect(err).To(BeNil())
+					Expect(ops).To(HaveLen(1))
+					Expect(ops[0].Address()).To(Equal(txs[0].GetFrom()))
+					Expect(ops[0].(*common.OpNewAccountBalance).Account.GetBalance()).To(Equal(util.String("9.900000000000000000")))
+				})
+			})
+		})
+
 		Context("recipient has an account", func() {
 			var receiver = crypto.NewKeyFromIntSeed(3)
 			var ops []common.Transition
@@ -392,6 +431,239 @@ var _ = Describe("Process", func() {
 		})
 	})
 
+	Describe(".processTransactions (multi-signature transactions)", func() {
+
+		var multiSig *types.MultiSig
+		var signer1, signer2 *crypto.Key
+
+		BeforeEach(func() {
+			signer1, signer2 = crypto.NewKeyFromIntSeed(3), crypto.NewKeyFromIntSeed(4)
+			multiSig = &types.MultiSig{
+				Threshold: 2,
+				PubKeys: []util.String{
+					util.String(signer1.PubKey().Base58()),
+					util.String(signer2.PubKey().Base58()),
+				},
+			}
+			err = bc.CreateAccount(1, genesisChain, &core.Account{
+				Type:    core.AccountTypeBalance,
+				Address: util.String(sender.Addr()),
+				Balance: "10",
+			})
+			Expect(err).To(BeNil())
+		})
+
+		It("should register the recipient of a TxTypeMultiSigRegister transaction as a multi-signature account", func() {
+			var txs = []types.Transaction{
+				&core.Transaction{Type: core.TxTypeMultiSigRegister, Nonce: 1, To: multiSig.Address(),
+					From: sender.Addr(), MultiSig: multiSig, Value: "5", Fee: "0.1"},
+			}
+			ops, err := bc.ProcessTransactions(txs, genesisChain)
+			Expect(err).To(BeNil())
+			Expect(ops).To(HaveLen(3))
+			account := ops[2].(*common.OpNewAccountBalance).Account
+			Expect(account.GetType()).To(Equal(int32(core.AccountTypeMultiSig)))
+			Expect(account.GetMultiSig()).To(Equal(multiSig))
+			Expect(account.GetBalance()).To(Equal(util.String("5.000000000000000000")))
+		})
+
+		It("should return error if the multi-signature account is already registered", func() {
+			account := &core.Account{Address: multiSig.Address(), Balance: "0"}
+			account.SetMultiSig(multiSig)
+			Expect(bc.CreateAccount(1, genesisChain, account)).To(BeNil())
+
+			var txs = []types.Transaction{
+				&core.Transaction{Type: core.TxTypeMultiSigRegister, Nonce: 1, To: multiSig.Address(),
+					From: sender.Addr(), MultiSig: multiSig, Value: "5", Fee: "0.1"},
+			}
+			_, err := bc.ProcessTransactions(txs, genesisChain)
+			Expect(err).ToNot(BeNil())
+			Expect(err.Error()).To(Equal("index{0}: multi-signature account is already registered"))
+		})
+
+		It("should return error if the sender of a TxTypeMultiSigSpend transaction is not a multi-signature account", func() {
+			Expect(bc.CreateAccount(1, genesisChain, &core.Account{
+				Type:    core.AccountTypeBalance,
+				Address: multiSig.Address(),
+				Balance: "10",
+			})).To(BeNil())
+
+			var txs = []types.Transaction{
+				core.NewMultiSigSpendTx(1, receiver.Addr(), multiSig, "1", "0.1", 1532730724, signer1, signer2),
+			}
+			_, err := bc.ProcessTransactions(txs, genesisChain)
+			Expect(err).ToNot(BeNil())
+			Expect(err.Error()).To(Equal("index{0}: sender is not a multi-signature account"))
+		})
+
+		It("should spend from a registered multi-signature account", func() {
+			var txs = []types.Transaction{
+				&core.Transaction{Type: core.TxTypeMultiSigRegister, Nonce: 1, To: multiSig.Address(),
+					From: sender.Addr(), MultiSig: multiSig, Value: "5", Fee: "0.1"},
+				core.NewMultiSigSpendTx(1, receiver.Addr(), multiSig, "1", "0.1", 1532730724, signer1, signer2),
+			}
+			ops, err := bc.ProcessTransactions(txs, genesisChain)
+			Expect(err).To(BeNil())
+
+			for _, op := range ops {
+				if op.Address().Equal(multiSig.Address()) {
+					if opNewBalance, ok := op.(*common.OpNewAccountBalance); ok {
+						Expect(opNewBalance.Account.GetBalance()).To(Equal(util.String("3.900000000000000000")))
+						Expect(opNewBalance.Account.GetNonce()).To(Equal(uint64(1)))
+					}
+				}
+			}
+		})
+	})
+
+	Describe(".processTransactions (name transactions)", func() {
+
+		var name = util.String("alice")
+
+		BeforeEach(func() {
+			for _, key := range []*crypto.Key{sender, receiver} {
+				err = bc.CreateAccount(1, genesisChain, &core.Account{
+					Type:    core.AccountTypeBalance,
+					Address: util.String(key.Addr()),
+					Balance: "10",
+				})
+				Expect(err).To(BeNil())
+			}
+		})
+
+		findName := func(ops []common.Transition) *types.NameRecord {
+			for _, op := range ops {
+				if opNewName, ok := op.(*common.OpNewName); ok && opNewName.Address().Equal(name) {
+					return opNewName.Record
+				}
+			}
+			return nil
+		}
+
+		It("should register a name", func() {
+			var txs = []types.Transaction{
+				&core.Transaction{Type: core.TxTypeNameRegister, Nonce: 1, To: sender.Addr(),
+					From: sender.Addr(), Name: name, Value: "0", Fee: "0.1"},
+			}
+			ops, err := bc.ProcessTransactions(txs, genesisChain)
+			Expect(err).To(BeNil())
+			Expect(findName(ops)).To(Equal(&types.NameRecord{
+				Name:      name,
+				Owner:     sender.Addr(),
+				Address:   sender.Addr(),
+				ExpiresAt: 2 + params.NameRegistrationPeriod,
+			}))
+		})
+
+		It("should return error if the name is already registered", func() {
+			var txs = []types.Transaction{
+				&core.Transaction{Type: core.TxTypeNameRegister, Nonce: 1, To: sender.Addr(),
+					From: sender.Addr(), Name: name, Value: "0", Fee: "0.1"},
+				&core.Transaction{Type: core.TxTypeNameRegister, Nonce: 1, To: receiver.Addr(),
+					From: receiver.Addr(), Name: name, Value: "0", Fee: "0.1"},
+			}
+			_, err := bc.ProcessTransactions(txs, genesisChain)
+			Expect(err).ToNot(BeNil())
+			Expect(err.Error()).To(Equal("index{1}: name is already registered"))
+		})
+
+		It("should register a name whose registration has expired", func() {
+			ops, err := bc.ProcessTransactions([]types.Transaction{
+				&core.Transaction{Type: core.TxTypeNameRegister, Nonce: 1, To: sender.Addr(),
+					From: sender.Addr(), Name: name, Value: "0", Fee: "0.1"},
+			}, genesisChain)
+			Expect(err).To(BeNil())
+
+			transitions := common.OpTransitions(ops)
+			ops, err = bc.ProcessTransactions([]types.Transaction{
+				&core.Transaction{Type: core.TxTypeNameRegister, Nonce: 1, To: receiver.Addr(),
+					From: receiver.Addr(), Name: name, Value: "0", Fee: "0.1"},
+			}, genesisChain, &transitions, common.OpBlockNumber(3+params.NameRegistrationPeriod))
+			Expect(err).To(BeNil())
+			Expect(findName(ops).Owner).To(Equal(receiver.Addr()))
+		})
+
+		It("should return error if the sender of a TxTypeNameRenew transaction is not the owner", func() {
+			var txs = []types.Transaction{
+				&core.Transaction{Type: core.TxTypeNameRegister, Nonce: 1, To: sender.Addr(),
+					From: sender.Addr(), Name: name, Value: "0", Fee: "0.1"},
+				&core.Transaction{Type: core.TxTypeNameRenew, Nonce: 1, To: receiver.Addr(),
+					From: receiver.Addr(), Name: name, Value: "0", Fee: "0.1"},
+			}
+			_, err := bc.ProcessTransactions(txs, genesisChain)
+			Expect(err).ToNot(BeNil())
+			Expect(err.Error()).To(Equal("index{1}: sender is not the owner of the name"))
+		})
+
+		It("should return error if a TxTypeNameRenew transaction renews an unregistered name", func() {
+			var txs = []types.Transaction{
+				&core.Transaction{Type: core.TxTypeNameRenew, Nonce: 1, To: sender.Addr(),
+					From: sender.Addr(), Name: name, Value: "0", Fee: "0.1"},
+			}
+			_, err := bc.ProcessTransactions(txs, genesisChain)
+			Expect(err).ToNot(BeNil())
+			Expect(err.Error()).To(Equal("index{0}: name is not registered"))
+		})
+
+		It("should extend the registration and update the address of a renewed name", func() {
+			var txs = []types.Transaction{
+				&core.Transaction{Type: core.TxTypeNameRegister, Nonce: 1, To: sender.Addr(),
+					From: sender.Addr(), Name: name, Value: "0", Fee: "0.1"},
+				&core.Transaction{Type: core.TxTypeNameRenew, Nonce: 2, To: receiver.Addr(),
+					From: sender.Addr(), Name: name, Value: "0", Fee: "0.1"},
+			}
+			ops, err := bc.ProcessTransactions(txs, genesisChain)
+			Expect(err).To(BeNil())
+			record := findName(ops)
+			Expect(record.Owner).To(Equal(sender.Addr()))
+			Expect(record.Address).To(Equal(receiver.Addr()))
+			Expect(record.ExpiresAt).To(Equal(2 + 2*params.NameRegistrationPeriod))
+		})
+
+		It("should transfer a name to the recipient", func() {
+			var txs = []types.Transaction{
+				&core.Transaction{Type: core.TxTypeNameRegister, Nonce: 1, To: sender.Addr(),
+					From: sender.Addr(), Name: name, Value: "0", Fee: "0.1"},
+				&core.Transaction{Type: core.TxTypeNameTransfer, Nonce: 2, To: receiver.Addr(),
+					From: sender.Addr(), Name: name, Value: "0", Fee: "0.1"},
+			}
+			ops, err := bc.ProcessTransactions(txs, genesisChain)
+			Expect(err).To(BeNil())
+			record := findName(ops)
+			Expect(record.Owner).To(Equal(receiver.Addr()))
+			Expect(record.Address).To(Equal(receiver.Addr()))
+			Expect(record.ExpiresAt).To(Equal(2 + params.NameRegistrationPeriod))
+		})
+
+		It("should send value to the address a recipient name resolves to", func() {
+			var txs = []types.Transaction{
+				&core.Transaction{Type: core.TxTypeNameRegister, Nonce: 1, To: receiver.Addr(),
+					From: receiver.Addr(), Name: name, Value: "0", Fee: "0.1"},
+				&core.Transaction{Type: core.TxTypeBalance, Nonce: 1, To: name,
+					From: sender.Addr(), Value: "1", Fee: "0.1"},
+			}
+			ops, err := bc.ProcessTransactions(txs, genesisChain)
+			Expect(err).To(BeNil())
+
+			for _, op := range ops {
+				if opNewBalance, ok := op.(*common.OpNewAccountBalance); ok &&
+					op.Address().Equal(receiver.Addr()) {
+					Expect(opNewBalance.Account.GetBalance()).To(Equal(util.String("10.900000000000000000")))
+				}
+			}
+		})
+
+		It("should return error if a recipient name is not registered", func() {
+			var txs = []types.Transaction{
+				&core.Transaction{Type: core.TxTypeBalance, Nonce: 1, To: name,
+					From: sender.Addr(), Value: "1", Fee: "0.1"},
+			}
+			_, err := bc.ProcessTransactions(txs, genesisChain)
+			Expect(err).ToNot(BeNil())
+			Expect(err.Error()).To(Equal("index{0}: recipient name is not registered"))
+		})
+	})
+
 	Describe(".ComputeTxsRoot", func() {
 		It("should return expected root", func() {
 			txs := []types.Transaction{
@@ -430,7 +702,7 @@ var _ = Describe("ExecBlock", func() {
 		sender = crypto.NewKeyFromIntSeed(1)
 		receiver = crypto.NewKeyFromIntSeed(2)
 
-		bc = New(txpool.New(100), cfg, log)
+		bc = New(txpool.New(100, nil), nil, newEngine(nil), cfg, log)
 		bc.SetDB(db)
 		bc.SetCoinbase(crypto.NewKeyFromIntSeed(1234))
 	})
@@ -539,7 +811,7 @@ var _ = Describe("ProcessBlock", func() {
 		sender = crypto.NewKeyFromIntSeed(1)
 		receiver = crypto.NewKeyFromIntSeed(2)
 
-		bc = New(txpool.New(100), cfg, log)
+		bc = New(txpool.New(100, nil), nil, newEngine(nil), cfg, log)
 		bc.SetDB(db)
 		bc.SetCoinbase(crypto.NewKeyFromIntSeed(1234))
 	})
@@ -583,7 +855,7 @@ var _ = Describe("ProcessBlock", func() {
 		})
 
 		It("should return error if block has been added to the orphaned cache", func() {
-			bc.orphanBlocks.Add(block.GetHashAsHex(), block)
+			bc.orphanBlocks.Add(block)
 			_, err = bc.ProcessBlock(block)
 			Expect(err).ToNot(BeNil())
 			Expect(err).To(Equal(fmt.Errorf("orphan block")))
@@ -790,7 +1062,7 @@ var _ = Describe("ProcessBlock", func() {
 			db = elldb.NewDB(cfg.NetDataDir())
 			err = db.Open(util.RandString(5))
 
-			bc2 = New(bc.txPool, cfg, log)
+			bc2 = New(bc.txPool, nil, newEngine(nil), cfg, log)
 			bc2.SetDB(db)
 			bc2.SetCoinbase(crypto.NewKeyFromIntSeed(1234))
 			bc2.SetGenesisBlock(genesisBlock)
@@ -0,0 +1,219 @@
+package blockchain
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/ellcrys/elld/blockchain/common"
+	"github.com/ellcrys/elld/types/core"
+	"github.com/ellcrys/elld/util"
+	"github.com/ellcrys/elld/util/logger"
+)
+
+// Pruner removes old versions of accounts from the
+// main chain when the node runs in pruned mode.
+//
+// Every balance change stores a new version of an
+// account. The pruner keeps the most recent versions
+// of each account and every version stored in the most
+// recent blocks. It also keeps the latest version stored
+// before those blocks, so that the state as at any of
+// those blocks can still be read during a reorg.
+type Pruner struct {
+	sync.Mutex
+	bchain       *Blockchain
+	log          logger.Logger
+	keepVersions int
+	keepBlocks   uint64
+	interval     time.Duration
+	tickerDone   chan bool
+	stopped      bool
+}
+
+// NewPruner creates a Pruner using the chain
+// configuration of the blockchain. It returns an
+// error if the maximum reorganization depth is not
+// set, since a reorg of unlimited depth may require
+// account versions that have been pruned.
+func NewPruner(bchain *Blockchain, log logger.Logger) (*Pruner, error) {
+
+	cfg := bchain.cfg.Chain
+	if cfg == nil || cfg.MaxReOrgDepth <= 0 {
+		return nil, fmt.Errorf("pruned mode requires a maximum reorg depth greater than zero")
+	}
+
+	p := &Pruner{
+		bchain:       bchain,
+		log:          log,
+		tickerDone:   make(chan bool),
+		keepVersions: int(cfg.PruneKeepVersions),
+		keepBlocks:   uint64(cfg.PruneKeepBlocks),
+		interval:     time.Duration(cfg.PruneInterval) * time.Second,
+	}
+
+	// Keep the versions required to apply
+	// the deepest reorg the node allows
+	if cfg.MaxReOrgDepth > cfg.PruneKeepBlocks {
+		p.keepBlocks = uint64(cfg.MaxReOrgDepth)
+	}
+
+	// Always keep at least the latest
+	// version of an account
+	if p.keepVersions < 1 {
+		p.keepVersions = 1
+	}
+
+	return p, nil
+}
+
+// Manage starts periodic pruning
+func (p *Pruner) Manage() {
+	if p.interval <= 0 {
+		p.log.Warn("Pruning not started: prune interval must be greater than zero")
+		return
+	}
+	go p.run(p.tickerDone)
+}
+
+// run prunes the main chain on every tick
+func (p *Pruner) run(done chan bool) {
+	ticker := time.NewTicker(p.interval)
+	for {
+		select {
+		case <-ticker.C:
+			n, err := p.Prune()
+			if err != nil {
+				p.log.Error("Failed to prune account versions", "Err", err.Error())
+				continue
+			}
+			if n > 0 {
+				p.log.Debug("Pruned account versions", "NumVersions", n)
+			}
+		case <-done:
+			ticker.Stop()
+			return
+		}
+	}
+}
+
+// Stop stops periodic pruning
+func (p *Pruner) Stop() {
+	p.Lock()
+	defer p.Unlock()
+	if p.stopped {
+		return
+	}
+	p.stopped = true
+	close(p.tickerDone)
+}
+
+// Prune removes old versions of accounts from
+// the main chain. It returns the number of
+// versions removed.
+func (p *Pruner) Prune() (int, error) {
+
+	p.bchain.chainLock.RLock()
+	chain := p.bchain.bestChain
+	p.bchain.chainLock.RUnlock()
+
+	if chain == nil {
+		return 0, core.ErrBestChainUnknown
+	}
+
+	tip, err := chain.Current()
+	if err != nil {
+		return 0, err
+	}
+
+	// Versions stored after the horizon are never pruned
+	if tip.GetNumber() <= p.keepBlocks {
+		return 0, nil
+	}
+	horizon := tip.GetNumber() - p.keepBlocks
+
+	// Record the horizon before removing versions so
+	// that queries for the state as at older blocks
+	// fail instead of reading incomplete state
+	curHorizon, err := chain.store.GetPruneHorizon()
+	if err != nil {
+		return 0, err
+	}
+	if horizon > curHorizon {
+		if err := chain.store.SetPruneHorizon(horizon); err != nil {
+			return 0, err
+		}
+	}
+
+	accounts, err := chain.GetAccounts()
+	if err != nil {
+		return 0, err
+	}
+
+	var total int
+	for _, account := range accounts {
+		n, err := p.pruneAccount(chain, account.GetAddress(), horizon)
+		if err != nil {
+			return total, err
+		}
+		total += n
+	}
+
+	return total, nil
+}
+
+// pruneAccount removes the old versions of
+// an account stored at or before the horizon.
+// It returns the number of versions removed.
+func (p *Pruner) pruneAccount(chain *Chain, address util.String,
+	horizon uint64) (int, error) {
+
+	// Prevent blocks from being processed
+	// while versions are being removed
+	p.bchain.processLock.Lock()
+	defer p.bchain.processLock.Unlock()
+
+	txOp := common.GetTxOp(chain.store.DB())
+	if txOp.Closed() {
+		return 0, nil
+	}
+	txOp.CanFinish = false
+
+	versions, err := chain.GetAccountVersions(address, txOp)
+	if err != nil {
+		txOp.Finishable().Discard()
+		return 0, err
+	}
+
+	// Find the latest version stored at or before
+	// the horizon. Versions before it are not
+	// required to read the state as at any block
+	// after the horizon.
+	var latestBeforeHorizon = -1
+	for i, v := range versions {
+		if v.BlockNumber > horizon {
+			break
+		}
+		latestBeforeHorizon = i
+	}
+
+	end := len(versions) - p.keepVersions
+	if latestBeforeHorizon < end {
+		end = latestBeforeHorizon
+	}
+
+	if end <= 0 {
+		txOp.Finishable().Discard()
+		return 0, nil
+	}
+
+	for _, v := range versions[:end] {
+		key := common.MakeKeyAccount(v.BlockNumber, chain.id.Bytes(), address.Bytes())
+		if err := chain.store.Delete(key, txOp); err != nil {
+			txOp.Finishable().Rollback()
+			return 0, err
+		}
+	}
+
+	return end, txOp.Finishable().Commit()
+}
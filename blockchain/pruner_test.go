@@ -0,0 +1,185 @@
+package blockchain
+
+import (
+	"math/big"
+	"os"
+	"time"
+
+	"github.com/ellcrys/elld/blockchain/common"
+	. "github.com/ellcrys/elld/blockchain/testutil"
+	"github.com/ellcrys/elld/blockchain/txpool"
+	"github.com/ellcrys/elld/config"
+	"github.com/ellcrys/elld/crypto"
+	"github.com/ellcrys/elld/elldb"
+	"github.com/ellcrys/elld/testutil"
+	"github.com/ellcrys/elld/types"
+	"github.com/ellcrys/elld/types/core"
+	"github.com/ellcrys/elld/util"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Pruner", func() {
+
+	var err error
+	var bc *Blockchain
+	var cfg *config.EngineConfig
+	var db elldb.DB
+	var genesisBlock types.Block
+	var sender, receiver *crypto.Key
+
+	BeforeEach(func() {
+		cfg, err = testutil.SetTestCfg()
+		Expect(err).To(BeNil())
+
+		db = elldb.NewDB(cfg.NetDataDir())
+		err = db.Open(util.RandString(5))
+		Expect(err).To(BeNil())
+
+		sender = crypto.NewKeyFromIntSeed(1)
+		receiver = crypto.NewKeyFromIntSeed(2)
+
+		bc = New(txpool.New(100, nil), nil, newEngine(nil), cfg, log)
+		bc.SetDB(db)
+		bc.SetCoinbase(crypto.NewKeyFromIntSeed(1234))
+	})
+
+	BeforeEach(func() {
+		genesisBlock, err = LoadBlockFromFile("genesis-test.json")
+		Expect(err).To(BeNil())
+		bc.SetGenesisBlock(genesisBlock)
+		err = bc.Up()
+		Expect(err).To(BeNil())
+	})
+
+	AfterEach(func() {
+		db.Close()
+		err = os.RemoveAll(cfg.DataDir())
+		Expect(err).To(BeNil())
+	})
+
+	Describe(".NewPruner", func() {
+		It("should use the chain configuration", func() {
+			cfg.Chain = &config.ChainConfig{PruneKeepVersions: 5, PruneKeepBlocks: 10, PruneInterval: 2, MaxReOrgDepth: 10}
+			p, err := NewPruner(bc, log)
+			Expect(err).To(BeNil())
+			Expect(p.keepVersions).To(Equal(5))
+			Expect(p.keepBlocks).To(Equal(uint64(10)))
+			Expect(p.interval).To(Equal(2 * time.Second))
+		})
+
+		It("should return error if the max reorg depth is not set", func() {
+			cfg.Chain = &config.ChainConfig{PruneKeepBlocks: 10}
+			_, err := NewPruner(bc, log)
+			Expect(err).ToNot(BeNil())
+			Expect(err.Error()).To(Equal("pruned mode requires a maximum reorg depth greater than zero"))
+		})
+
+		It("should keep at least the blocks required by the max reorg depth", func() {
+			cfg.Chain = &config.ChainConfig{PruneKeepBlocks: 10, MaxReOrgDepth: 50}
+			p, err := NewPruner(bc, log)
+			Expect(err).To(BeNil())
+			Expect(p.keepBlocks).To(Equal(uint64(50)))
+		})
+
+		It("should keep at least one version", func() {
+			cfg.Chain = &config.ChainConfig{PruneKeepVersions: 0, MaxReOrgDepth: 1}
+			p, err := NewPruner(bc, log)
+			Expect(err).To(BeNil())
+			Expect(p.keepVersions).To(Equal(1))
+		})
+	})
+
+	Describe(".Prune", func() {
+
+		var p *Pruner
+
+		// Add blocks 2, 3 and 4. Each stores
+		// a new version of the sender's account
+		BeforeEach(func() {
+			for nonce := uint64(1); nonce <= 3; nonce++ {
+				block := MakeTestBlock(bc, bc.bestChain, &types.GenerateBlockParams{
+					Transactions: []types.Transaction{
+						core.NewTx(core.TxTypeBalance, nonce, receiver.Addr(), sender, "1", "2.5", time.Now().UnixNano()),
+					},
+					Creator:           sender,
+					Nonce:             util.EncodeNonce(1),
+					Difficulty:        new(big.Int).SetInt64(131072),
+					OverrideTimestamp: time.Now().Unix() + int64(nonce),
+				})
+				_, err = bc.ProcessBlock(block)
+				Expect(err).To(BeNil())
+			}
+
+			versions, err := bc.bestChain.GetAccountVersions(sender.Addr())
+			Expect(err).To(BeNil())
+			Expect(versions).To(HaveLen(4))
+
+			cfg.Chain = &config.ChainConfig{MaxReOrgDepth: 1}
+			p, err = NewPruner(bc, log)
+			Expect(err).To(BeNil())
+		})
+
+		It("should not prune versions stored in the most recent blocks", func() {
+			p.keepVersions = 1
+			p.keepBlocks = 10
+			n, err := p.Prune()
+			Expect(err).To(BeNil())
+			Expect(n).To(Equal(0))
+		})
+
+		It("should keep the latest version stored before the most recent blocks", func() {
+			p.keepVersions = 1
+			p.keepBlocks = 1
+			n, err := p.Prune()
+			Expect(err).To(BeNil())
+			Expect(n).To(BeNumerically(">=", 2))
+
+			versions, err := bc.bestChain.GetAccountVersions(sender.Addr())
+			Expect(err).To(BeNil())
+			Expect(versions).To(HaveLen(2))
+			Expect(versions[0].BlockNumber).To(Equal(uint64(3)))
+			Expect(versions[1].BlockNumber).To(Equal(uint64(4)))
+
+			account, err := bc.bestChain.GetAccount(sender.Addr(), &common.OpBlockQueryRange{Max: 3})
+			Expect(err).To(BeNil())
+			Expect(account.GetNonce()).To(Equal(uint64(2)))
+		})
+
+		It("should return error when querying the state as at a block below the prune horizon", func() {
+			p.keepVersions = 1
+			p.keepBlocks = 1
+			_, err := p.Prune()
+			Expect(err).To(BeNil())
+
+			horizon, err := bc.bestChain.store.GetPruneHorizon()
+			Expect(err).To(BeNil())
+			Expect(horizon).To(Equal(uint64(3)))
+
+			_, err = bc.GetAccount(sender.Addr(), &common.OpBlockQueryRange{Max: 2})
+			Expect(err).To(Equal(core.ErrStatePruned))
+
+			_, err = bc.GetBalanceHistory(sender.Addr(), 2, 0)
+			Expect(err).To(Equal(core.ErrStatePruned))
+
+			_, _, _, err = bc.GetAccountProof(sender.Addr(), 2)
+			Expect(err).To(Equal(core.ErrStatePruned))
+
+			account, err := bc.GetAccount(sender.Addr(), &common.OpBlockQueryRange{Max: 3})
+			Expect(err).To(BeNil())
+			Expect(account.GetNonce()).To(Equal(uint64(2)))
+		})
+
+		It("should keep the most recent versions", func() {
+			p.keepVersions = 3
+			p.keepBlocks = 1
+			_, err := p.Prune()
+			Expect(err).To(BeNil())
+
+			versions, err := bc.bestChain.GetAccountVersions(sender.Addr())
+			Expect(err).To(BeNil())
+			Expect(versions).To(HaveLen(3))
+			Expect(versions[0].BlockNumber).To(Equal(uint64(2)))
+		})
+	})
+})
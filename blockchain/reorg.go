@@ -33,8 +33,20 @@ type ReOrgInfo struct {
 // 2. The chain that was received first.
 // 3. The chain with the larger pointer
 //
+// Branches that conflict with a checkpoint or whose
+// reorganization was rejected are not considered.
+//
 // NOTE: This method must be called with chain lock held by the caller.
 func (b *Blockchain) chooseBestChain(opts ...types.CallOp) (*Chain, error) {
+	return b.chooseBestChainExcept(nil, opts...)
+}
+
+// chooseBestChainExcept is like chooseBestChain but
+// also ignores the chains in the excluded set.
+//
+// NOTE: This method must be called with chain lock held by the caller.
+func (b *Blockchain) chooseBestChainExcept(excluded map[util.String]struct{},
+	opts ...types.CallOp) (*Chain, error) {
 
 	var highTDChains = []*Chain{}
 	var curHighestTD = new(big.Int).SetInt64(0)
@@ -66,6 +78,23 @@ func (b *Blockchain) chooseBestChain(opts ...types.CallOp) (*Chain, error) {
 	// difficulty, then that indicates a tie and as such the highTDChains
 	// will also include these chains.
 	for _, chain := range b.chains {
+
+		// Ignore branches whose reorganization was rejected
+		if _, rejected := b.rejectedBranches[chain.GetID()]; rejected {
+			continue
+		}
+		if _, ok := excluded[chain.GetID()]; ok {
+			continue
+		}
+
+		// Ignore branches that conflict with a checkpoint
+		conflicts, err := b.conflictsWithCheckpoint(chain, txOp)
+		if err != nil {
+			return nil, err
+		} else if conflicts {
+			continue
+		}
+
 		tip, err := chain.Current(txOp)
 		if err != nil {
 			// A chain with no tip is ignored.
@@ -131,11 +160,13 @@ func (b *Blockchain) chooseBestChain(opts ...types.CallOp) (*Chain, error) {
 }
 
 // decideBestChain determines and sets the current best chain
-// based on the split resolution rules.
-func (b *Blockchain) decideBestChain(opts ...types.CallOp) error {
+// based on the split resolution rules. It returns the transactions
+// of the blocks detached by a reorganization. The caller must
+// return them to the pool once the transaction is committed.
+func (b *Blockchain) decideBestChain(opts ...types.CallOp) ([]types.Transaction, error) {
 	txOp := common.GetTxOp(b.db, opts...)
 	if txOp.Closed() {
-		return leveldb.ErrClosed
+		return nil, leveldb.ErrClosed
 	}
 
 	// If a db transaction was not injected,
@@ -147,11 +178,48 @@ func (b *Blockchain) decideBestChain(opts ...types.CallOp) error {
 		txOp.CanFinish = false
 	}
 
-	proposedBestChain, err := b.chooseBestChain(txOp)
-	if err != nil {
-		txOp.SetFinishable(!hasInjectTx).Rollback()
-		b.log.Error("Unable to determine best chain", "Err", err.Error())
-		return err
+	// Choose the best chain. If switching to it requires
+	// a reorganization deeper than allowed, the branch
+	// is rejected and a best chain is chosen again.
+	// Rejected branches are only marked as rejected once
+	// the transaction is committed, so we keep track of
+	// them here until then.
+	var proposedBestChain *Chain
+	var rejectedBranches = make(map[util.String]struct{})
+	for {
+		var err error
+		proposedBestChain, err = b.chooseBestChainExcept(rejectedBranches, txOp)
+		if err != nil {
+			txOp.SetFinishable(!hasInjectTx).Rollback()
+			b.log.Error("Unable to determine best chain", "Err", err.Error())
+			return nil, err
+		}
+
+		if proposedBestChain == nil {
+			break
+		}
+
+		// When no best chain has been set (e.g on start up),
+		// the root chain of the proposed chain is treated
+		// as the main chain to measure the reorg depth.
+		mainChain := b.bestChain
+		if mainChain == nil {
+			mainChain = b.getRootChain(proposedBestChain)
+		}
+
+		if mainChain.GetID() == proposedBestChain.GetID() {
+			break
+		}
+
+		rejected, err := b.maybeRejectReOrg(mainChain, proposedBestChain, txOp)
+		if err != nil {
+			txOp.SetFinishable(!hasInjectTx).Rollback()
+			return nil, err
+		} else if !rejected {
+			break
+		}
+
+		rejectedBranches[proposedBestChain.GetID()] = struct{}{}
 	}
 
 	// At this point, we were just not able to choose a best chain.
@@ -159,24 +227,34 @@ func (b *Blockchain) decideBestChain(opts ...types.CallOp) error {
 	if proposedBestChain == nil {
 		txOp.SetFinishable(!hasInjectTx).Rollback()
 		b.log.Debug("Unable to choose best chain")
-		return fmt.Errorf("unable to choose best chain")
+		return nil, fmt.Errorf("unable to choose best chain")
 	}
 
 	// If the current best chain and the new best chain
 	// are not the same. Then we must reorganize
+	var detachedTxs []types.Transaction
 	if b.bestChain != nil && b.bestChain.GetID() != proposedBestChain.GetID() {
 		b.log.Info("New best chain detected. Re-organizing...",
 			"CurBestChainID", b.bestChain.GetID().SS(),
 			"ProposedChainID",
 			proposedBestChain.GetID().SS())
 
+		// Collect the transactions that will be
+		// dropped along with the detached blocks
+		var err error
+		detachedTxs, err = b.getDetachedTxs(b.bestChain, proposedBestChain, txOp)
+		if err != nil {
+			txOp.SetFinishable(!hasInjectTx).Rollback()
+			return nil, fmt.Errorf("failed to get detached transactions: %s", err)
+		}
+
 		b.setReOrgStatus(true)
 		newBestChain, err := b.reOrg(proposedBestChain, txOp)
 		if err != nil {
 			txOp.SetFinishable(!hasInjectTx).Rollback()
 			b.log.Error(err.Error())
 			b.setReOrgStatus(false)
-			return fmt.Errorf("Reorganization error: %s", err)
+			return nil, fmt.Errorf("Reorganization error: %s", err)
 		}
 
 		b.setReOrgStatus(false)
@@ -192,16 +270,141 @@ func (b *Blockchain) decideBestChain(opts ...types.CallOp) error {
 		b.log.Info("Best chain set", "CurBestChainID", b.bestChain.GetID().SS())
 	}
 
-	return txOp.SetFinishable(!hasInjectTx).Commit()
+	if err := txOp.SetFinishable(!hasInjectTx).Commit(); err != nil {
+		return nil, err
+	}
+
+	return detachedTxs, nil
 }
 
-// recordReOrg stores a record of a reorganization
+// getDetachedTxs returns the transactions of the mainChain
+// blocks that a reorganization to branch would remove and
+// that are not included in the branch. Allocation
+// transactions are not returned.
 // NOTE: This method must be called with write chain lock held by the caller.
-func (b *Blockchain) recordReOrg(timestamp int64, branch *Chain, opts ...types.CallOp) error {
+func (b *Blockchain) getDetachedTxs(mainChain, branch *Chain,
+	opts ...types.CallOp) ([]types.Transaction, error) {
 
-	var txOp = common.GetTxOp(b.db, opts...)
+	if branch.parentBlock == nil {
+		return nil, fmt.Errorf("parent block not set on branch")
+	}
+
+	mainTip, err := mainChain.Current(opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	sideTip, err := branch.Current(opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	// Index the transactions of the branch
+	var branchTxs = make(map[util.Hash]struct{})
+	for n := branch.parentBlock.GetNumber() + 1; n <= sideTip.GetNumber(); n++ {
+		block, err := branch.GetBlock(n, opts...)
+		if err != nil {
+			return nil, err
+		}
+		for _, tx := range block.GetTransactions() {
+			branchTxs[tx.GetHash()] = struct{}{}
+		}
+	}
+
+	var txs []types.Transaction
+	for n := branch.parentBlock.GetNumber() + 1; n <= mainTip.GetNumber(); n++ {
+		block, err := mainChain.GetBlock(n, opts...)
+		if err != nil {
+			return nil, err
+		}
+		for _, tx := range block.GetTransactions() {
+			if tx.GetType() == core.TxTypeAlloc {
+				continue
+			}
+			if _, ok := branchTxs[tx.GetHash()]; ok {
+				continue
+			}
+			txs = append(txs, tx)
+		}
+	}
+
+	return txs, nil
+}
+
+// repoolTxs revalidates transactions removed from the
+// main chain by a reorganization against the new main
+// chain and adds the valid ones back to the pool.
+// core.EventTransactionPooled is emitted for each
+// transaction added to the pool.
+func (b *Blockchain) repoolTxs(txs []types.Transaction) {
+	var numPooled int
+	for _, tx := range txs {
+		txValidator := NewTxValidator(tx, b.txPool, b)
+		if errs := txValidator.Validate(); len(errs) > 0 {
+			b.log.Debug("Dropped detached transaction", "Hash", tx.GetHash().SS(),
+				"Err", errs[0].Error())
+			continue
+		}
+
+		if err := b.txPool.Put(tx); err != nil {
+			b.log.Debug("Failed to add detached transaction to the pool",
+				"Hash", tx.GetHash().SS(), "Err", err.Error())
+			continue
+		}
+
+		numPooled++
+		go b.eventEmitter.Emit(core.EventTransactionPooled, tx)
+	}
+
+	b.log.Info("Returned detached transactions to the pool",
+		"NumTxs", len(txs), "NumPooled", numPooled)
+}
+
+// maxReOrgDepth returns the maximum number of main
+// chain blocks a reorganization can roll back.
+// Zero means there is no limit.
+func (b *Blockchain) maxReOrgDepth() uint64 {
+	if b.cfg == nil || b.cfg.Chain == nil || b.cfg.Chain.MaxReOrgDepth <= 0 {
+		return 0
+	}
+	return uint64(b.cfg.Chain.MaxReOrgDepth)
+}
+
+// getRootChain returns the chain that the ancestry
+// of the given chain starts from. The given chain is
+// returned if it has no known parent chain.
+// NOTE: This method must be called with write chain lock held by the caller.
+func (b *Blockchain) getRootChain(chain *Chain) *Chain {
+	for chain.info != nil && chain.info.ParentChainID != "" {
+		parent, ok := b.chains[chain.info.ParentChainID]
+		if !ok || parent.GetID() == chain.GetID() {
+			break
+		}
+		chain = parent
+	}
+	return chain
+}
+
+// maybeRejectReOrg rejects a reorganization of mainChain to the branch
+// if it would roll back more main chain blocks than the
+// maximum reorg depth. A rejected branch is never considered
+// as the best chain. The rejection is recorded and, once the
+// transaction is committed, the branch is marked as rejected
+// and core.EventReOrgRejected is emitted. It returns true
+// if the reorganization was rejected.
+//
+// NOTE: This method must be called with write chain lock held by the caller.
+func (b *Blockchain) maybeRejectReOrg(mainChain, branch *Chain,
+	opts ...types.CallOp) (bool, error) {
+
+	maxDepth := b.maxReOrgDepth()
+	if maxDepth == 0 {
+		return false, nil
+	}
+
+	txOp := common.GetTxOp(b.db, opts...)
 	if txOp.Closed() {
-		return leveldb.ErrClosed
+		return false, leveldb.ErrClosed
 	}
 
 	// If a db transaction was not injected,
@@ -213,29 +416,90 @@ func (b *Blockchain) recordReOrg(timestamp int64, branch *Chain, opts ...types.C
 		txOp.CanFinish = false
 	}
 
+	reOrgInfo, err := b.makeReOrgInfo(time.Now().UnixNano(), mainChain, branch, txOp)
+	if err != nil {
+		txOp.SetFinishable(!hasInjectTx).Rollback()
+		return false, err
+	}
+
+	if reOrgInfo.ReOrgLen <= maxDepth {
+		txOp.SetFinishable(!hasInjectTx).Rollback()
+		return false, nil
+	}
+
+	key := common.MakeKeyRejectedReOrg(reOrgInfo.Timestamp)
+	if err := b.storeReOrgInfo(key, reOrgInfo, txOp); err != nil {
+		txOp.SetFinishable(!hasInjectTx).Rollback()
+		return false, fmt.Errorf("failed to store rejected re-org record: %s", err)
+	}
+
+	b.log.Warn("Rejected re-organization deeper than the maximum reorg depth",
+		"MainChainID", mainChain.GetID().SS(),
+		"BranchID", branch.GetID().SS(),
+		"ReOrgLen", reOrgInfo.ReOrgLen,
+		"MaxReOrgDepth", maxDepth)
+
+	// Only mark the branch as rejected when the
+	// rejection record has been persisted
+	txOp.AfterCommit(func() {
+		b.rejectedBranches[branch.GetID()] = struct{}{}
+		go b.eventEmitter.Emit(core.EventReOrgRejected, reOrgInfo)
+	})
+
+	return true, txOp.SetFinishable(!hasInjectTx).Commit()
+}
+
+// makeReOrgInfo creates information about
+// a reorganization of mainChain to branch
+// NOTE: This method must be called with write chain lock held by the caller.
+func (b *Blockchain) makeReOrgInfo(timestamp int64, mainChain, branch *Chain,
+	opts ...types.CallOp) (*ReOrgInfo, error) {
+
+	if branch.parentBlock == nil {
+		return nil, fmt.Errorf("parent block not set on branch")
+	}
+
 	var reOrgInfo = &ReOrgInfo{
-		MainChainID: b.bestChain.id.String(),
+		MainChainID: mainChain.id.String(),
 		BranchID:    branch.id.String(),
 		Timestamp:   timestamp,
 	}
 
-	mainTip, err := b.bestChain.Current(txOp)
+	mainTip, err := mainChain.Current(opts...)
 	if err != nil {
-		txOp.SetFinishable(!hasInjectTx).Rollback()
-		return err
+		return nil, err
 	}
 
-	sideTip, err := branch.Current(txOp)
+	sideTip, err := branch.Current(opts...)
 	if err != nil {
-		txOp.SetFinishable(!hasInjectTx).Rollback()
-		return err
+		return nil, err
 	}
 
 	reOrgInfo.BranchLen = sideTip.GetNumber() - branch.parentBlock.GetNumber()
 	reOrgInfo.ReOrgLen = mainTip.GetNumber() - branch.parentBlock.GetNumber()
 
-	key := common.MakeKeyReOrg(timestamp)
-	err = txOp.Tx.Put([]*elldb.KVObject{elldb.NewKVObject(key, util.ObjectToBytes(reOrgInfo))})
+	return reOrgInfo, nil
+}
+
+// storeReOrgInfo stores reorganization info with the given key
+func (b *Blockchain) storeReOrgInfo(key []byte, reOrgInfo *ReOrgInfo,
+	opts ...types.CallOp) error {
+
+	var txOp = common.GetTxOp(b.db, opts...)
+	if txOp.Closed() {
+		return leveldb.ErrClosed
+	}
+
+	// If a db transaction was not injected,
+	// then we must prevent methods that we pass
+	// this transaction to from finishing it
+	// (commit/rollback)
+	hasInjectTx := common.HasTxOp(opts...)
+	if !hasInjectTx {
+		txOp.CanFinish = false
+	}
+
+	err := txOp.Tx.Put([]*elldb.KVObject{elldb.NewKVObject(key, util.ObjectToBytes(reOrgInfo))})
 	if err != nil {
 		txOp.SetFinishable(!hasInjectTx).Rollback()
 		return err
@@ -244,14 +508,25 @@ func (b *Blockchain) recordReOrg(timestamp int64, branch *Chain, opts ...types.C
 	return txOp.SetFinishable(!hasInjectTx).Commit()
 }
 
-// getReOrgs fetches information about all reorganizations
-func (b *Blockchain) getReOrgs(opts ...types.CallOp) []*ReOrgInfo {
+// recordReOrg stores a record of a reorganization
+// NOTE: This method must be called with write chain lock held by the caller.
+func (b *Blockchain) recordReOrg(timestamp int64, branch *Chain, opts ...types.CallOp) error {
+	reOrgInfo, err := b.makeReOrgInfo(timestamp, b.bestChain, branch, opts...)
+	if err != nil {
+		return err
+	}
+	return b.storeReOrgInfo(common.MakeKeyReOrg(timestamp), reOrgInfo, opts...)
+}
+
+// getReOrgInfos fetches reorganization info stored
+// with the given prefix, sorted by timestamp
+// in descending order.
+func (b *Blockchain) getReOrgInfos(prefix []byte) []*ReOrgInfo {
 	b.chainLock.RLock()
 	defer b.chainLock.RUnlock()
 
 	var reOrgs = []*ReOrgInfo{}
-	key := common.MakeQueryKeyReOrg()
-	result := b.db.GetByPrefix(key)
+	result := b.db.GetByPrefix(prefix)
 	for _, r := range result {
 		var reOrg ReOrgInfo
 		r.Scan(&reOrg)
@@ -266,6 +541,35 @@ func (b *Blockchain) getReOrgs(opts ...types.CallOp) []*ReOrgInfo {
 	return reOrgs
 }
 
+// getReOrgs fetches information about all reorganizations
+func (b *Blockchain) getReOrgs() []*ReOrgInfo {
+	return b.getReOrgInfos(common.MakeQueryKeyReOrg())
+}
+
+// getRejectedReOrgs fetches information about
+// all rejected reorganizations
+func (b *Blockchain) getRejectedReOrgs() []*ReOrgInfo {
+	return b.getReOrgInfos(common.MakeQueryKeyRejectedReOrg())
+}
+
+// loadRejectedBranches marks the known branches whose
+// reorganization was previously rejected as rejected,
+// so that they are not chosen as the best chain
+// after a restart.
+func (b *Blockchain) loadRejectedBranches() {
+	rejectedReOrgs := b.getRejectedReOrgs()
+
+	b.chainLock.Lock()
+	defer b.chainLock.Unlock()
+
+	for _, reOrg := range rejectedReOrgs {
+		branchID := util.String(reOrg.BranchID)
+		if _, ok := b.chains[branchID]; ok {
+			b.rejectedBranches[branchID] = struct{}{}
+		}
+	}
+}
+
 // reOrg overwrites the main chain with blocks of
 // branch. The blocks after the branch's parent/root
 // blocks are deleted from the main branch and replaced
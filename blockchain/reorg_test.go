@@ -42,7 +42,7 @@ var _ = Describe("ReOrg", func() {
 		sender = crypto.NewKeyFromIntSeed(1)
 		receiver = crypto.NewKeyFromIntSeed(2)
 
-		bc = New(txpool.New(100), cfg, log)
+		bc = New(txpool.New(100, nil), nil, newEngine(nil), cfg, log)
 		bc.SetDB(db)
 		bc.SetCoinbase(crypto.NewKeyFromIntSeed(1234))
 	})
@@ -438,6 +438,98 @@ var _ = Describe("ReOrg", func() {
 		})
 	})
 
+	Describe(".decideBestChain: detached transactions", func() {
+
+		var forkedChain *Chain
+		var tx types.Transaction
+
+		// Build two chains having the following shapes:
+		// [1]-[2] 			- Genesis chain
+		//  |__[2]-[3] 		- forked chain 1
+		BeforeEach(func() {
+			tx = core.NewTx(core.TxTypeBalance, 1, util.String(receiver.Addr()), sender, "1", "2.5", time.Now().Unix())
+			genesisB2 := MakeTestBlock(bc, genesisChain, &types.GenerateBlockParams{
+				Transactions:         []types.Transaction{tx},
+				Creator:              sender,
+				Nonce:                util.EncodeNonce(1),
+				Difficulty:           new(big.Int).SetInt64(131072),
+				AddFeeAlloc:          true,
+				NoPoolAdditionInTest: true,
+			})
+			_, err = bc.ProcessBlock(genesisB2)
+			Expect(err).To(BeNil())
+		})
+
+		Context("when the detached transactions are not in the new main chain", func() {
+
+			BeforeEach(func() {
+				forkChainB2 := MakeBlockWithNoTx(bc, genesisChain, sender, receiver)
+				forkedChainReader, err := bc.ProcessBlock(forkChainB2, common.OpAllowExec(true))
+				Expect(err).To(BeNil())
+				forkedChain = bc.chains[forkedChainReader.GetID()]
+
+				forkChainB3 := MakeBlockWithNoTx(bc, forkedChain, sender, receiver)
+				_, err = bc.ProcessBlock(forkChainB3, common.OpAllowExec(true))
+				Expect(err).To(BeNil())
+			})
+
+			It("should add the transactions back to the pool", func() {
+				Expect(bc.bestChain.GetID()).To(Equal(forkedChain.GetID()))
+				Expect(bc.txPool.Has(tx)).To(BeTrue())
+			})
+		})
+
+		Context("when the reorganization is done in an injected transaction", func() {
+
+			var txOp *common.OpTx
+
+			BeforeEach(func() {
+				forkChainB2 := MakeBlockWithNoTx(bc, genesisChain, sender, receiver)
+				forkedChainReader, err := bc.ProcessBlock(forkChainB2, common.OpAllowExec(true))
+				Expect(err).To(BeNil())
+				forkedChain = bc.chains[forkedChainReader.GetID()]
+
+				forkChainB3 := MakeBlockWithNoTx(bc, forkedChain, sender, receiver)
+				txOp = common.GetTxOp(db)
+				_, err = bc.maybeAcceptBlock(forkChainB3, nil, txOp, common.OpAllowExec(true))
+				Expect(err).To(BeNil())
+			})
+
+			It("should add the transactions back to the pool only after the transaction is committed", func() {
+				Expect(bc.bestChain.GetID()).To(Equal(forkedChain.GetID()))
+				Expect(bc.txPool.Has(tx)).To(BeFalse())
+				Expect(txOp.SetFinishable(true).Commit()).To(BeNil())
+				Expect(bc.txPool.Has(tx)).To(BeTrue())
+			})
+		})
+
+		Context("when the detached transactions are in the new main chain", func() {
+
+			BeforeEach(func() {
+				forkChainB2 := MakeTestBlock(bc, genesisChain, &types.GenerateBlockParams{
+					Transactions:         []types.Transaction{tx},
+					Creator:              sender,
+					Nonce:                util.EncodeNonce(1),
+					Difficulty:           new(big.Int).SetInt64(131072),
+					AddFeeAlloc:          true,
+					NoPoolAdditionInTest: true,
+				})
+				forkedChainReader, err := bc.ProcessBlock(forkChainB2, common.OpAllowExec(true))
+				Expect(err).To(BeNil())
+				forkedChain = bc.chains[forkedChainReader.GetID()]
+
+				forkChainB3 := MakeBlockWithNoTx(bc, forkedChain, sender, receiver)
+				_, err = bc.ProcessBlock(forkChainB3, common.OpAllowExec(true))
+				Expect(err).To(BeNil())
+			})
+
+			It("should not add the transactions to the pool", func() {
+				Expect(bc.bestChain.GetID()).To(Equal(forkedChain.GetID()))
+				Expect(bc.txPool.Has(tx)).To(BeFalse())
+			})
+		})
+	})
+
 	Describe(".recordReOrg", func() {
 
 		var branch *Chain
@@ -456,6 +548,143 @@ var _ = Describe("ReOrg", func() {
 		})
 	})
 
+	Describe(".maybeRejectReOrg", func() {
+
+		var branch *Chain
+
+		// Build a main chain with blocks 2 and 3
+		// and a branch whose parent is the genesis block
+		BeforeEach(func() {
+			for i := int64(1); i <= 2; i++ {
+				block := MakeTestBlock(bc, genesisChain, &types.GenerateBlockParams{
+					Transactions: []types.Transaction{
+						core.NewTx(core.TxTypeBalance, uint64(i), util.String(receiver.Addr()), sender, "1", "2.5", time.Now().Unix()),
+					},
+					Creator:                 sender,
+					Nonce:                   util.EncodeNonce(1),
+					Difficulty:              new(big.Int).SetInt64(1),
+					OverrideTotalDifficulty: new(big.Int).SetInt64(10 + i),
+				})
+				err := genesisChain.append(block)
+				Expect(err).To(BeNil())
+			}
+
+			branch = NewChain("s1", db, cfg, log)
+			err := branch.append(genesisBlock)
+			branch.parentBlock = genesisBlock
+			Expect(err).To(BeNil())
+		})
+
+		It("should not reject re-org if max reorg depth is not set", func() {
+			cfg.Chain.MaxReOrgDepth = 0
+			rejected, err := bc.maybeRejectReOrg(genesisChain, branch)
+			Expect(err).To(BeNil())
+			Expect(rejected).To(BeFalse())
+		})
+
+		It("should not reject re-org that does not exceed the max reorg depth", func() {
+			cfg.Chain.MaxReOrgDepth = 2
+			rejected, err := bc.maybeRejectReOrg(genesisChain, branch)
+			Expect(err).To(BeNil())
+			Expect(rejected).To(BeFalse())
+			Expect(bc.getRejectedReOrgs()).To(BeEmpty())
+		})
+
+		It("should reject and record re-org that exceeds the max reorg depth", func() {
+			cfg.Chain.MaxReOrgDepth = 1
+			rejected, err := bc.maybeRejectReOrg(genesisChain, branch)
+			Expect(err).To(BeNil())
+			Expect(rejected).To(BeTrue())
+			Expect(bc.rejectedBranches).To(HaveKey(branch.GetID()))
+
+			rejectedReOrgs := bc.getRejectedReOrgs()
+			Expect(rejectedReOrgs).To(HaveLen(1))
+			Expect(rejectedReOrgs[0].BranchID).To(Equal(branch.GetID().String()))
+			Expect(rejectedReOrgs[0].ReOrgLen).To(Equal(uint64(2)))
+			Expect(bc.getReOrgs()).To(BeEmpty())
+		})
+
+		It("should not mark the branch as rejected if the transaction is rolled back", func() {
+			cfg.Chain.MaxReOrgDepth = 1
+			txOp := common.GetTxOp(db)
+			rejected, err := bc.maybeRejectReOrg(genesisChain, branch, txOp)
+			Expect(err).To(BeNil())
+			Expect(rejected).To(BeTrue())
+			Expect(bc.rejectedBranches).ToNot(HaveKey(branch.GetID()))
+
+			Expect(txOp.SetFinishable(true).Rollback()).To(BeNil())
+			Expect(bc.rejectedBranches).ToNot(HaveKey(branch.GetID()))
+			Expect(bc.getRejectedReOrgs()).To(BeEmpty())
+		})
+
+		It("should emit core.EventReOrgRejected", func(done Done) {
+			cfg.Chain.MaxReOrgDepth = 1
+			go func() {
+				evt := <-bc.eventEmitter.Once(core.EventReOrgRejected)
+				Expect(evt.Args[0].(*ReOrgInfo).BranchID).To(Equal(branch.GetID().String()))
+				close(done)
+			}()
+			_, err := bc.maybeRejectReOrg(genesisChain, branch)
+			Expect(err).To(BeNil())
+		})
+
+		It("should not consider a rejected branch when choosing the best chain", func() {
+			bc.rejectedBranches[genesisChain.GetID()] = struct{}{}
+			bc.addChain(branch)
+			bestChain, err := bc.chooseBestChain()
+			Expect(err).To(BeNil())
+			Expect(bestChain.GetID()).To(Equal(branch.GetID()))
+		})
+
+		Context("when the best chain has not been chosen", func() {
+
+			var deepBranch *Chain
+
+			// Create a branch with a higher total difficulty
+			// whose parent is the genesis block
+			BeforeEach(func() {
+				block := MakeTestBlock(bc, genesisChain, &types.GenerateBlockParams{
+					Transactions: []types.Transaction{
+						core.NewTx(core.TxTypeBalance, 3, util.String(receiver.Addr()), sender, "1", "2.5", time.Now().Unix()),
+					},
+					Creator:                 sender,
+					Nonce:                   util.EncodeNonce(1),
+					Difficulty:              new(big.Int).SetInt64(1),
+					OverrideTotalDifficulty: new(big.Int).SetInt64(100),
+				})
+
+				deepBranch = NewChain("s2", db, cfg, log)
+				Expect(deepBranch.append(block)).To(BeNil())
+				Expect(bc.saveChain(deepBranch, genesisChain.GetID(), genesisBlock.GetNumber())).To(BeNil())
+				deepBranch.parentBlock = genesisBlock
+			})
+
+			It("should reject the branch if the re-org exceeds the max reorg depth", func() {
+				cfg.Chain.MaxReOrgDepth = 1
+				bc.bestChain = nil
+				_, err := bc.decideBestChain()
+				Expect(err).To(BeNil())
+				Expect(bc.bestChain.GetID()).To(Equal(genesisChain.GetID()))
+				Expect(bc.rejectedBranches).To(HaveKey(deepBranch.GetID()))
+			})
+
+			It("should not choose a branch rejected before a restart", func() {
+				cfg.Chain.MaxReOrgDepth = 1
+				rejected, err := bc.maybeRejectReOrg(genesisChain, deepBranch)
+				Expect(err).To(BeNil())
+				Expect(rejected).To(BeTrue())
+
+				cfg.Chain.MaxReOrgDepth = 0
+				restarted := New(txpool.New(100, nil), nil, newEngine(nil), cfg, log)
+				restarted.SetDB(db)
+				restarted.SetGenesisBlock(genesisBlock)
+				Expect(restarted.Up()).To(BeNil())
+				Expect(restarted.rejectedBranches).To(HaveKey(deepBranch.GetID()))
+				Expect(restarted.bestChain.GetID()).To(Equal(genesisChain.GetID()))
+			})
+		})
+	})
+
 	Describe(".getReOrgs", func() {
 		var branch *Chain
 
@@ -0,0 +1,212 @@
+package blockchain
+
+import (
+	"sync"
+	"time"
+
+	"github.com/ellcrys/elld/blockchain/common"
+	"github.com/ellcrys/elld/types"
+	"github.com/ellcrys/elld/types/core"
+	"github.com/ellcrys/elld/util"
+	"github.com/ellcrys/elld/util/logger"
+)
+
+// SideChainCollector removes stale side chains.
+//
+// A side chain is stale when its tip is more than a
+// number of blocks behind the tip of the main chain
+// and the tip block is older than a given age. The
+// blocks, transactions and accounts of a stale side
+// chain are deleted. A stale side chain that has a side
+// chain that is not stale branching off it is kept.
+type SideChainCollector struct {
+	sync.Mutex
+	bchain     *Blockchain
+	log        logger.Logger
+	maxLag     uint64
+	maxAge     time.Duration
+	interval   time.Duration
+	tickerDone chan bool
+	stopped    bool
+}
+
+// NewSideChainCollector creates a SideChainCollector
+// using the chain configuration of the blockchain
+func NewSideChainCollector(bchain *Blockchain, log logger.Logger) *SideChainCollector {
+	c := &SideChainCollector{
+		bchain:     bchain,
+		log:        log,
+		tickerDone: make(chan bool),
+	}
+
+	if cfg := bchain.cfg.Chain; cfg != nil {
+		c.maxLag = uint64(cfg.SideChainMaxLag)
+		c.maxAge = time.Duration(cfg.SideChainMaxAge) * time.Second
+		c.interval = time.Duration(cfg.SideChainGCInterval) * time.Second
+	}
+
+	return c
+}
+
+// Manage starts periodic collection
+func (c *SideChainCollector) Manage() {
+	if c.interval <= 0 {
+		c.log.Debug("Side chain collection is disabled")
+		return
+	}
+	go c.run(c.tickerDone)
+}
+
+// run collects stale side chains on every tick
+func (c *SideChainCollector) run(done chan bool) {
+	ticker := time.NewTicker(c.interval)
+	for {
+		select {
+		case <-ticker.C:
+			n, err := c.Collect()
+			if err != nil {
+				c.log.Error("Failed to collect stale side chains", "Err", err.Error())
+				continue
+			}
+			if n > 0 {
+				c.log.Info("Removed stale side chains", "NumChains", n)
+			}
+		case <-done:
+			ticker.Stop()
+			return
+		}
+	}
+}
+
+// Stop stops periodic collection
+func (c *SideChainCollector) Stop() {
+	c.Lock()
+	defer c.Unlock()
+	if c.stopped {
+		return
+	}
+	c.stopped = true
+	close(c.tickerDone)
+}
+
+// Collect finds and deletes stale side chains.
+// It returns the number of side chains deleted.
+func (c *SideChainCollector) Collect() (int, error) {
+
+	// Prevent blocks from being processed
+	// while side chains are being removed
+	c.bchain.processLock.Lock()
+	defer c.bchain.processLock.Unlock()
+
+	c.bchain.chainLock.RLock()
+	bestChain := c.bchain.bestChain
+	var chains []*Chain
+	for _, chain := range c.bchain.chains {
+		chains = append(chains, chain)
+	}
+	c.bchain.chainLock.RUnlock()
+
+	if bestChain == nil {
+		return 0, core.ErrBestChainUnknown
+	}
+
+	bestTip, err := bestChain.Current()
+	if err != nil {
+		return 0, err
+	}
+
+	// Find the stale side chains
+	stale := make(map[util.String]*Chain)
+	for _, chain := range chains {
+		if chain.GetID().Equal(bestChain.GetID()) || chain.info.ParentChainID == "" {
+			continue
+		}
+
+		isStale, err := c.isStale(chain, bestTip)
+		if err != nil {
+			return 0, err
+		} else if isStale {
+			stale[chain.GetID()] = chain
+		}
+	}
+
+	// A stale side chain must be kept if a side
+	// chain that is not stale branches off it.
+	for changed := true; changed; {
+		changed = false
+		for _, chain := range chains {
+			if _, ok := stale[chain.GetID()]; ok {
+				continue
+			}
+			if _, ok := stale[chain.info.ParentChainID]; ok {
+				delete(stale, chain.info.ParentChainID)
+				changed = true
+			}
+		}
+	}
+
+	var count int
+	for _, chain := range stale {
+		if err := c.deleteChain(chain); err != nil {
+			return count, err
+		}
+		count++
+	}
+
+	return count, nil
+}
+
+// isStale checks whether a side chain is stale
+func (c *SideChainCollector) isStale(chain *Chain, bestTip types.Header) (bool, error) {
+
+	tip, err := chain.Current()
+	if err != nil {
+		if err == core.ErrBlockNotFound {
+			return false, nil
+		}
+		return false, err
+	}
+
+	if tip.GetNumber() >= bestTip.GetNumber() ||
+		bestTip.GetNumber()-tip.GetNumber() <= c.maxLag {
+		return false, nil
+	}
+
+	tipTime := time.Unix(tip.GetTimestamp(), 0)
+	return time.Since(tipTime) > c.maxAge, nil
+}
+
+// deleteChain deletes a side chain and
+// all objects stored in it
+func (c *SideChainCollector) deleteChain(chain *Chain) error {
+
+	txOp := common.GetTxOp(chain.store.DB())
+	if txOp.Closed() {
+		return nil
+	}
+	txOp.CanFinish = false
+
+	for _, key := range [][]byte{
+		common.MakeQueryKeyChain(chain.GetID().Bytes()),
+		common.MakeKeyChain(chain.GetID().Bytes()),
+	} {
+		if err := chain.store.Delete(key, txOp); err != nil {
+			txOp.Finishable().Rollback()
+			return err
+		}
+	}
+
+	if err := txOp.Finishable().Commit(); err != nil {
+		return err
+	}
+
+	c.bchain.removeChain(chain)
+
+	c.bchain.chainLock.Lock()
+	delete(c.bchain.rejectedBranches, chain.GetID())
+	c.bchain.chainLock.Unlock()
+
+	c.log.Debug("Removed stale side chain", "ChainID", chain.GetID().SS())
+
+	return nil
+}
@@ -0,0 +1,145 @@
+package blockchain
+
+import (
+	"math/big"
+	"os"
+	"time"
+
+	. "github.com/ellcrys/elld/blockchain/testutil"
+	"github.com/ellcrys/elld/blockchain/txpool"
+	"github.com/ellcrys/elld/config"
+	"github.com/ellcrys/elld/crypto"
+	"github.com/ellcrys/elld/elldb"
+	"github.com/ellcrys/elld/testutil"
+	"github.com/ellcrys/elld/types"
+	"github.com/ellcrys/elld/types/core"
+	"github.com/ellcrys/elld/util"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("SideChainCollector", func() {
+
+	var err error
+	var bc *Blockchain
+	var cfg *config.EngineConfig
+	var db elldb.DB
+	var genesisBlock types.Block
+	var sender, receiver *crypto.Key
+
+	BeforeEach(func() {
+		cfg, err = testutil.SetTestCfg()
+		Expect(err).To(BeNil())
+
+		db = elldb.NewDB(cfg.NetDataDir())
+		err = db.Open(util.RandString(5))
+		Expect(err).To(BeNil())
+
+		sender = crypto.NewKeyFromIntSeed(1)
+		receiver = crypto.NewKeyFromIntSeed(2)
+
+		bc = New(txpool.New(100, nil), nil, newEngine(nil), cfg, log)
+		bc.SetDB(db)
+		bc.SetCoinbase(crypto.NewKeyFromIntSeed(1234))
+	})
+
+	BeforeEach(func() {
+		genesisBlock, err = LoadBlockFromFile("genesis-test.json")
+		Expect(err).To(BeNil())
+		bc.SetGenesisBlock(genesisBlock)
+		err = bc.Up()
+		Expect(err).To(BeNil())
+	})
+
+	AfterEach(func() {
+		db.Close()
+		err = os.RemoveAll(cfg.DataDir())
+		Expect(err).To(BeNil())
+	})
+
+	makeBlock := func(nonce uint64, timestamp int64) types.Block {
+		return MakeTestBlock(bc, bc.bestChain, &types.GenerateBlockParams{
+			Transactions: []types.Transaction{
+				core.NewTx(core.TxTypeBalance, nonce, receiver.Addr(), sender, "1", "2.5", time.Now().UnixNano()),
+			},
+			Creator:           sender,
+			Nonce:             util.EncodeNonce(1),
+			Difficulty:        new(big.Int).SetInt64(131072),
+			OverrideTimestamp: timestamp,
+		})
+	}
+
+	Describe(".NewSideChainCollector", func() {
+		It("should use the chain configuration", func() {
+			cfg.Chain = &config.ChainConfig{SideChainMaxLag: 5, SideChainMaxAge: 60, SideChainGCInterval: 2}
+			c := NewSideChainCollector(bc, log)
+			Expect(c.maxLag).To(Equal(uint64(5)))
+			Expect(c.maxAge).To(Equal(60 * time.Second))
+			Expect(c.interval).To(Equal(2 * time.Second))
+		})
+	})
+
+	Describe(".Collect", func() {
+
+		var c *SideChainCollector
+		var forkBlock types.Block
+
+		// Build the following chains:
+		// [1]-[2]-[3]-[4] 	- main chain
+		//  |__[2] 			- side chain
+		BeforeEach(func() {
+			now := time.Now().Unix()
+			block2 := makeBlock(1, now-10)
+			forkBlock = makeBlock(1, now-9)
+
+			_, err = bc.ProcessBlock(block2)
+			Expect(err).To(BeNil())
+
+			for nonce := uint64(2); nonce <= 3; nonce++ {
+				_, err = bc.ProcessBlock(makeBlock(nonce, now-10+int64(nonce)))
+				Expect(err).To(BeNil())
+			}
+
+			_, err = bc.ProcessBlock(forkBlock)
+			Expect(err).To(BeNil())
+			Expect(bc.chains).To(HaveLen(2))
+
+			c = NewSideChainCollector(bc, log)
+		})
+
+		It("should delete a side chain that is too far behind and too old", func() {
+			c.maxLag = 1
+			c.maxAge = 0
+			n, err := c.Collect()
+			Expect(err).To(BeNil())
+			Expect(n).To(Equal(1))
+			Expect(bc.chains).To(HaveLen(1))
+
+			has, err := bc.HaveBlock(forkBlock.GetHash())
+			Expect(err).To(BeNil())
+			Expect(has).To(BeFalse())
+
+			chains, err := bc.getChains()
+			Expect(err).To(BeNil())
+			Expect(chains).To(HaveLen(1))
+		})
+
+		It("should not delete a side chain that is not far enough behind", func() {
+			c.maxLag = 2
+			c.maxAge = 0
+			n, err := c.Collect()
+			Expect(err).To(BeNil())
+			Expect(n).To(Equal(0))
+			Expect(bc.chains).To(HaveLen(2))
+		})
+
+		It("should not delete a side chain that is not old enough", func() {
+			c.maxLag = 1
+			c.maxAge = time.Hour
+			n, err := c.Collect()
+			Expect(err).To(BeNil())
+			Expect(n).To(Equal(0))
+			Expect(bc.chains).To(HaveLen(2))
+		})
+	})
+})
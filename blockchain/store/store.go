@@ -20,9 +20,10 @@ const MetadataKey = "meta"
 // interface meant to be used for persisting and retrieving
 // objects for a given chain.
 type ChainStore struct {
-	db        elldb.DB
-	namespace string
-	chainID   util.String
+	db           elldb.DB
+	namespace    string
+	chainID      util.String
+	addressIndex bool
 }
 
 // New creates an instance of the store
@@ -33,6 +34,12 @@ func New(db elldb.DB, chainID util.String) *ChainStore {
 	}
 }
 
+// SetAddressIndex enables or disables indexing
+// of transactions by sender and recipient address
+func (s *ChainStore) SetAddressIndex(enabled bool) {
+	s.addressIndex = enabled
+}
+
 // DB gets the database
 func (s *ChainStore) DB() elldb.DB {
 	return s.db
@@ -120,6 +127,9 @@ func (s *ChainStore) GetBlock(number uint64, opts ...types.CallOp) (types.Block,
 // can be found as opposed to storing the entire
 // transaction. This saves disk space when considering
 // that the block on disk already contains the transaction.
+//
+// When address indexing is enabled, the transactions
+// are also indexed by their sender and recipient.
 func (s *ChainStore) PutTransactions(txs []types.Transaction, blockNumber uint64, opts ...types.CallOp) error {
 	var txOp = common.GetTxOp(s.db, opts...)
 	if txOp.Closed() {
@@ -134,6 +144,66 @@ func (s *ChainStore) PutTransactions(txs []types.Transaction, blockNumber uint64
 		kvObjs = append(kvObjs, txObj)
 	}
 
+	if s.addressIndex {
+		kvObjs = append(kvObjs, s.makeAddressIndexObjects(txs, blockNumber)...)
+	}
+
+	if err := txOp.Tx.Put(kvObjs); err != nil {
+		txOp.Rollback()
+		return err
+	}
+
+	return txOp.Commit()
+}
+
+// makeAddressIndexObjects creates the objects that index
+// the transactions of a block by their sender and recipient
+func (s *ChainStore) makeAddressIndexObjects(txs []types.Transaction,
+	blockNumber uint64) []*elldb.KVObject {
+	var kvObjs = []*elldb.KVObject{}
+	for i, tx := range txs {
+		for _, address := range TxAddresses(tx) {
+			key := common.MakeKeyAddressTransaction(s.chainID.Bytes(),
+				address.Bytes(), blockNumber, uint64(i))
+			kvObjs = append(kvObjs, elldb.NewKVObject(key, tx.GetHash().Bytes()))
+		}
+	}
+	return kvObjs
+}
+
+// BuildAddressIndex indexes the transactions of all the
+// blocks in the store by their sender and recipient. This
+// allows blocks stored while address indexing was disabled
+// to be indexed. The store is then marked as indexed and
+// subsequent calls do nothing.
+func (s *ChainStore) BuildAddressIndex(opts ...types.CallOp) error {
+	var txOp = common.GetTxOp(s.db, opts...)
+	if txOp.Closed() {
+		return leveldb.ErrClosed
+	}
+
+	statusKey := common.MakeKeyAddressIndexStatus(s.chainID.Bytes())
+	if len(txOp.Tx.GetByPrefix(statusKey)) > 0 {
+		return txOp.Discard()
+	}
+
+	var err error
+	var kvObjs = []*elldb.KVObject{}
+	txOp.Tx.Iterate(common.MakeQueryKeyBlocks(s.chainID.Bytes()), true, func(kv *elldb.KVObject) bool {
+		var block core.Block
+		if err = kv.Scan(&block); err != nil {
+			return true
+		}
+		kvObjs = append(kvObjs, s.makeAddressIndexObjects(block.GetTransactions(),
+			block.GetNumber())...)
+		return false
+	})
+	if err != nil {
+		txOp.Rollback()
+		return err
+	}
+
+	kvObjs = append(kvObjs, elldb.NewKVObject(statusKey, []byte{1}))
 	if err := txOp.Tx.Put(kvObjs); err != nil {
 		txOp.Rollback()
 		return err
@@ -142,6 +212,119 @@ func (s *ChainStore) PutTransactions(txs []types.Transaction, blockNumber uint64
 	return txOp.Commit()
 }
 
+// ResetAddressIndex deletes the address index of the
+// store and removes its indexed mark, so that the index
+// is built again by BuildAddressIndex.
+func (s *ChainStore) ResetAddressIndex(opts ...types.CallOp) error {
+	var txOp = common.GetTxOp(s.db, opts...)
+	if txOp.Closed() {
+		return leveldb.ErrClosed
+	}
+
+	if err := txOp.Tx.DeleteByPrefix(common.MakeQueryKeyAddressIndex(s.chainID.Bytes())); err != nil {
+		txOp.Rollback()
+		return err
+	}
+
+	statusKey := common.MakeKeyAddressIndexStatus(s.chainID.Bytes())
+	if err := txOp.Tx.DeleteByPrefix(statusKey); err != nil {
+		txOp.Rollback()
+		return err
+	}
+
+	return txOp.Commit()
+}
+
+// SetPruneHorizon stores the number of the block at or
+// before which old versions of accounts may have been pruned.
+func (s *ChainStore) SetPruneHorizon(blockNumber uint64, opts ...types.CallOp) error {
+	key := common.MakeKeyPruneHorizon(s.chainID.Bytes())
+	return s.put(key, util.EncodeNumber(blockNumber), opts...)
+}
+
+// GetPruneHorizon gets the prune horizon of the
+// chain. It returns zero if the chain was never pruned.
+func (s *ChainStore) GetPruneHorizon(opts ...types.CallOp) (uint64, error) {
+	var result = []*elldb.KVObject{}
+	key := common.MakeKeyPruneHorizon(s.chainID.Bytes())
+	if err := s.get(key, &result, opts...); err != nil {
+		return 0, err
+	}
+	if len(result) == 0 {
+		return 0, nil
+	}
+	return util.DecodeNumber(result[0].Value), nil
+}
+
+// TxAddresses returns the addresses a transaction
+// is indexed by: the sender and the recipient.
+func TxAddresses(tx types.Transaction) []util.String {
+	if tx.GetFrom().Equal(tx.GetTo()) {
+		return []util.String{tx.GetFrom()}
+	}
+	return []util.String{tx.GetFrom(), tx.GetTo()}
+}
+
+// GetAddressTxPointers gets pointers to transactions sent
+// or received by an address. Only pointers positioned after
+// the cursor (if provided) in the direction of iteration are
+// returned. Iteration begins from the oldest transaction
+// if ascending is true, otherwise the newest.
+func (s *ChainStore) GetAddressTxPointers(address util.String, cursor *types.TxPointer,
+	limit int, ascending bool, opts ...types.CallOp) ([]*types.TxPointer, error) {
+
+	var txOp = common.GetTxOp(s.db, opts...)
+	if txOp.Closed() {
+		return nil, leveldb.ErrClosed
+	}
+
+	var pointers = []*types.TxPointer{}
+	key := common.MakeQueryKeyAddressTransactions(s.chainID.Bytes(), address.Bytes())
+	txOp.Tx.Iterate(key, ascending, func(kv *elldb.KVObject) bool {
+		if len(kv.Key) != 16 {
+			return false
+		}
+
+		pointer := &types.TxPointer{
+			BlockNumber: util.DecodeNumber(kv.Key[:8]),
+			Index:       util.DecodeNumber(kv.Key[8:]),
+			Hash:        util.BytesToHash(kv.Value),
+		}
+
+		// Skip pointers that are not
+		// positioned after the cursor
+		if cursor != nil {
+			cmp := compareTxPointers(pointer, cursor)
+			if (ascending && cmp <= 0) || (!ascending && cmp >= 0) {
+				return false
+			}
+		}
+
+		pointers = append(pointers, pointer)
+		return limit > 0 && len(pointers) >= limit
+	})
+
+	return pointers, txOp.Discard()
+}
+
+// compareTxPointers compares the position of two
+// transaction pointers. It returns -1 if a is positioned
+// before b, 1 if a is positioned after b or 0 if both
+// have the same position.
+func compareTxPointers(a, b *types.TxPointer) int {
+	switch {
+	case a.BlockNumber < b.BlockNumber:
+		return -1
+	case a.BlockNumber > b.BlockNumber:
+		return 1
+	case a.Index < b.Index:
+		return -1
+	case a.Index > b.Index:
+		return 1
+	}
+	return 0
+}
+
 // PutMinedBlock stores a brief information about a
 // block that was created by the blockchain's coinbase key
 func (s *ChainStore) PutMinedBlock(block types.Block, opts ...types.CallOp) error {
@@ -363,6 +546,24 @@ func (s *ChainStore) get(key []byte, result *[]*elldb.KVObject, opts ...types.Ca
 // belonging to a chain
 func (s *ChainStore) GetTransaction(hash util.Hash, opts ...types.CallOp) (types.Transaction, error) {
 
+	block, err := s.GetTransactionBlock(hash, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, tx := range block.GetTransactions() {
+		if tx.GetHash().Equal(hash) {
+			return tx, nil
+		}
+	}
+
+	return nil, core.ErrTxNotFound
+}
+
+// GetTransactionBlock gets the block that
+// includes a transaction (by hash)
+func (s *ChainStore) GetTransactionBlock(hash util.Hash, opts ...types.CallOp) (types.Block, error) {
+
 	var txOp = common.GetTxOp(s.db, opts...)
 	if txOp.Closed() {
 		return nil, leveldb.ErrClosed
@@ -381,8 +582,8 @@ func (s *ChainStore) GetTransaction(hash util.Hash, opts ...types.CallOp) (types
 	}
 
 	// Using the block number stored as the transaction
-	// key value, we must fetch the block and find
-	// the transaction in its list of transactions
+	// key value, we must fetch the block that includes
+	// the transaction
 	blockNumber := util.DecodeNumber(result[0].Value)
 	block, err := s.getBlock(blockNumber, txOp)
 	if err != nil {
@@ -392,15 +593,7 @@ func (s *ChainStore) GetTransaction(hash util.Hash, opts ...types.CallOp) (types
 		return nil, err
 	}
 
-	for _, tx := range block.GetTransactions() {
-		if tx.GetHash().Equal(hash) {
-			return tx, nil
-		}
-	}
-
-	txOp.Discard()
-
-	return nil, core.ErrTxNotFound
+	return block, nil
 }
 
 // CreateAccount creates an account on a target block
@@ -449,7 +642,43 @@ func (s *ChainStore) GetAccount(address util.String, opts ...types.CallOp) (type
 	return &account, txOp.Discard()
 }
 
-// GetAccounts gets all accounts
+// GetAccountVersions gets the versions of an account
+// in ascending order of the block they were stored at.
+func (s *ChainStore) GetAccountVersions(address util.String, opts ...types.CallOp) ([]*types.AccountVersion, error) {
+
+	var versions []*types.AccountVersion
+	var txOp = common.GetTxOp(s.db, opts...)
+	if txOp.Closed() {
+		return nil, leveldb.ErrClosed
+	}
+
+	queryKey := common.MakeQueryKeyAccount(s.chainID.Bytes(), address.Bytes())
+	var blockRangeOp = common.GetBlockQueryRangeOp(opts...)
+	txOp.Tx.Iterate(queryKey, true, func(kv *elldb.KVObject) bool {
+		var bn = util.DecodeNumber(kv.Key)
+
+		// Check block range constraint.
+		if (blockRangeOp.Min > 0 && bn < blockRangeOp.Min) || blockRangeOp.Max > 0 && bn > blockRangeOp.Max {
+			return false
+		}
+
+		var account core.Account
+		kv.Scan(&account)
+		versions = append(versions, &types.AccountVersion{
+			BlockNumber: bn,
+			Account:     &account,
+		})
+
+		return false
+	})
+
+	return versions, txOp.Discard()
+}
+
+// GetAccounts gets the latest version of all accounts.
+// The keys of an account end with the block number, so
+// they are iterated from the last in order to find the
+// latest version of an account before older ones.
 func (s *ChainStore) GetAccounts(opts ...types.CallOp) ([]types.Account, error) {
 
 	var accounts []types.Account
@@ -486,6 +715,81 @@ func (s *ChainStore) GetAccounts(opts ...types.CallOp) ([]types.Account, error)
 	return accounts, txOp.Discard()
 }
 
+// GetName gets the latest version of a name record.
+func (s *ChainStore) GetName(name util.String, opts ...types.CallOp) (*types.NameRecord, error) {
+
+	var r *elldb.KVObject
+	var txOp = common.GetTxOp(s.db, opts...)
+	if txOp.Closed() {
+		return nil, leveldb.ErrClosed
+	}
+
+	queryKey := common.MakeQueryKeyName(s.chainID.Bytes(), name.Bytes())
+	var blockRangeOp = common.GetBlockQueryRangeOp(opts...)
+	txOp.Tx.Iterate(queryKey, false, func(kv *elldb.KVObject) bool {
+		var bn = util.DecodeNumber(kv.Key)
+		if (blockRangeOp.Min > 0 && bn < blockRangeOp.Min) || blockRangeOp.Max > 0 && bn > blockRangeOp.Max {
+			return false
+		}
+		r = kv
+		return true
+	})
+
+	if r == nil {
+		txOp.Discard()
+		return nil, core.ErrNameNotFound
+	}
+
+	var record types.NameRecord
+	if err := r.Scan(&record); err != nil {
+		txOp.Discard()
+		return nil, err
+	}
+
+	return &record, txOp.Discard()
+}
+
+// GetNames gets the latest version of all name records
+func (s *ChainStore) GetNames(opts ...types.CallOp) ([]*types.NameRecord, error) {
+
+	var records []*types.NameRecord
+	var latest = map[util.String]uint64{}
+	var txOp = common.GetTxOp(s.db, opts...)
+	if txOp.Closed() {
+		return nil, leveldb.ErrClosed
+	}
+
+	queryKey := common.MakeQueryKeyNames(s.chainID.Bytes())
+	var blockRangeOp = common.GetBlockQueryRangeOp(opts...)
+	var index = map[util.String]int{}
+	txOp.Tx.Iterate(queryKey, true, func(kv *elldb.KVObject) bool {
+		var bn = util.DecodeNumber(kv.Key)
+		if (blockRangeOp.Min > 0 && bn < blockRangeOp.Min) || blockRangeOp.Max > 0 && bn > blockRangeOp.Max {
+			return false
+		}
+
+		var record types.NameRecord
+		if kv.Scan(&record) != nil {
+			return false
+		}
+
+		// Keep the version stored at the highest block
+		i, has := index[record.Name]
+		if !has {
+			index[record.Name] = len(records)
+			latest[record.Name] = bn
+			records = append(records, &record)
+		} else if bn > latest[record.Name] {
+			latest[record.Name] = bn
+			records[i] = &record
+		}
+
+		return false
+	})
+
+	return records, txOp.Discard()
+}
+
 // NewTx creates and returns a transaction
 func (s *ChainStore) NewTx() (elldb.Tx, error) {
 	return s.db.NewTx()
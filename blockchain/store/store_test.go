@@ -69,6 +69,157 @@ var _ = Describe("Store", func() {
 		})
 	})
 
+	Describe(".GetAddressTxPointers", func() {
+
+		BeforeEach(func() {
+			store.SetAddressIndex(true)
+			err = store.PutTransactions([]types.Transaction{
+				&core.Transaction{To: "addr_b", From: "addr_a", Hash: util.StrToHash("hash1")},
+				&core.Transaction{To: "addr_c", From: "addr_b", Hash: util.StrToHash("hash2")},
+			}, 1)
+			Expect(err).To(BeNil())
+			err = store.PutTransactions([]types.Transaction{
+				&core.Transaction{To: "addr_a", From: "addr_a", Hash: util.StrToHash("hash3")},
+				&core.Transaction{To: "addr_b", From: "addr_c", Hash: util.StrToHash("hash4")},
+			}, 2)
+			Expect(err).To(BeNil())
+		})
+
+		It("should return the transactions of an address in ascending order", func() {
+			pointers, err := store.GetAddressTxPointers("addr_b", nil, 0, true)
+			Expect(err).To(BeNil())
+			Expect(pointers).To(HaveLen(3))
+			Expect(pointers[0]).To(Equal(&types.TxPointer{BlockNumber: 1, Index: 0, Hash: util.StrToHash("hash1")}))
+			Expect(pointers[1]).To(Equal(&types.TxPointer{BlockNumber: 1, Index: 1, Hash: util.StrToHash("hash2")}))
+			Expect(pointers[2]).To(Equal(&types.TxPointer{BlockNumber: 2, Index: 1, Hash: util.StrToHash("hash4")}))
+		})
+
+		It("should return the transactions of an address in descending order", func() {
+			pointers, err := store.GetAddressTxPointers("addr_b", nil, 0, false)
+			Expect(err).To(BeNil())
+			Expect(pointers).To(HaveLen(3))
+			Expect(pointers[0].Hash).To(Equal(util.StrToHash("hash4")))
+			Expect(pointers[2].Hash).To(Equal(util.StrToHash("hash1")))
+		})
+
+		It("should index a transaction sent to its sender once", func() {
+			pointers, err := store.GetAddressTxPointers("addr_a", nil, 0, true)
+			Expect(err).To(BeNil())
+			Expect(pointers).To(HaveLen(2))
+			Expect(pointers[1].Hash).To(Equal(util.StrToHash("hash3")))
+		})
+
+		It("should return at most limit transactions positioned after the cursor", func() {
+			cursor := &types.TxPointer{BlockNumber: 2, Index: 1}
+			pointers, err := store.GetAddressTxPointers("addr_b", cursor, 1, false)
+			Expect(err).To(BeNil())
+			Expect(pointers).To(HaveLen(1))
+			Expect(pointers[0].Hash).To(Equal(util.StrToHash("hash2")))
+
+			pointers, err = store.GetAddressTxPointers("addr_b", pointers[0], 1, false)
+			Expect(err).To(BeNil())
+			Expect(pointers).To(HaveLen(1))
+			Expect(pointers[0].Hash).To(Equal(util.StrToHash("hash1")))
+
+			pointers, err = store.GetAddressTxPointers("addr_b", pointers[0], 1, false)
+			Expect(err).To(BeNil())
+			Expect(pointers).To(BeEmpty())
+		})
+
+		It("should not index transactions when address indexing is disabled", func() {
+			store.SetAddressIndex(false)
+			err = store.PutTransactions([]types.Transaction{
+				&core.Transaction{To: "addr_b", From: "addr_d", Hash: util.StrToHash("hash5")},
+			}, 3)
+			Expect(err).To(BeNil())
+			pointers, err := store.GetAddressTxPointers("addr_d", nil, 0, true)
+			Expect(err).To(BeNil())
+			Expect(pointers).To(BeEmpty())
+		})
+	})
+
+	Describe(".BuildAddressIndex", func() {
+
+		BeforeEach(func() {
+			err = store.PutBlock(&core.Block{
+				Header: &core.Header{Number: 1},
+				Transactions: []*core.Transaction{
+					{To: "addr_b", From: "addr_a", Hash: util.StrToHash("hash1")},
+				},
+			})
+			Expect(err).To(BeNil())
+		})
+
+		It("should index the transactions of blocks stored while address indexing was disabled", func() {
+			pointers, err := store.GetAddressTxPointers("addr_a", nil, 0, true)
+			Expect(err).To(BeNil())
+			Expect(pointers).To(BeEmpty())
+
+			Expect(store.BuildAddressIndex()).To(BeNil())
+			pointers, err = store.GetAddressTxPointers("addr_a", nil, 0, true)
+			Expect(err).To(BeNil())
+			Expect(pointers).To(HaveLen(1))
+			Expect(pointers[0]).To(Equal(&types.TxPointer{BlockNumber: 1, Index: 0, Hash: util.StrToHash("hash1")}))
+		})
+
+		It("should not index the blocks again once the store is marked as indexed", func() {
+			Expect(store.BuildAddressIndex()).To(BeNil())
+			err = store.PutBlock(&core.Block{
+				Header: &core.Header{Number: 2},
+				Transactions: []*core.Transaction{
+					{To: "addr_b", From: "addr_a", Hash: util.StrToHash("hash2")},
+				},
+			})
+			Expect(err).To(BeNil())
+
+			Expect(store.BuildAddressIndex()).To(BeNil())
+			pointers, err := store.GetAddressTxPointers("addr_a", nil, 0, true)
+			Expect(err).To(BeNil())
+			Expect(pointers).To(HaveLen(1))
+		})
+	})
+
+	Describe(".ResetAddressIndex", func() {
+
+		BeforeEach(func() {
+			err = store.PutBlock(&core.Block{
+				Header: &core.Header{Number: 1},
+				Transactions: []*core.Transaction{
+					{To: "addr_b", From: "addr_a", Hash: util.StrToHash("hash1")},
+				},
+			})
+			Expect(err).To(BeNil())
+			Expect(store.BuildAddressIndex()).To(BeNil())
+		})
+
+		It("should delete the index and allow it to be built again", func() {
+			Expect(store.ResetAddressIndex()).To(BeNil())
+			pointers, err := store.GetAddressTxPointers("addr_a", nil, 0, true)
+			Expect(err).To(BeNil())
+			Expect(pointers).To(BeEmpty())
+
+			Expect(store.BuildAddressIndex()).To(BeNil())
+			pointers, err = store.GetAddressTxPointers("addr_a", nil, 0, true)
+			Expect(err).To(BeNil())
+			Expect(pointers).To(HaveLen(1))
+		})
+	})
+
+	Describe(".GetPruneHorizon", func() {
+		It("should return zero if the chain was never pruned", func() {
+			horizon, err := store.GetPruneHorizon()
+			Expect(err).To(BeNil())
+			Expect(horizon).To(Equal(uint64(0)))
+		})
+
+		It("should return the stored prune horizon", func() {
+			Expect(store.SetPruneHorizon(10)).To(BeNil())
+			horizon, err := store.GetPruneHorizon()
+			Expect(err).To(BeNil())
+			Expect(horizon).To(Equal(uint64(10)))
+		})
+	})
+
 	Describe(".GetTransaction", func() {
 
 		var txs = []types.Transaction{
@@ -247,6 +398,44 @@ var _ = Describe("Store", func() {
 		})
 	})
 
+	Describe(".GetAccountVersions", func() {
+
+		var acct, acct2 *core.Account
+
+		BeforeEach(func() {
+			acct = &core.Account{Type: core.AccountTypeBalance, Balance: "0.1", Address: "addr"}
+			err = store.CreateAccount(1, acct)
+			Expect(err).To(BeNil())
+
+			acct2 = &core.Account{Type: core.AccountTypeBalance, Balance: "1.2", Address: "addr"}
+			err = store.CreateAccount(3, acct2)
+			Expect(err).To(BeNil())
+		})
+
+		It("should return an empty result if account does not exist", func() {
+			versions, err := store.GetAccountVersions("addr2")
+			Expect(err).To(BeNil())
+			Expect(versions).To(BeEmpty())
+		})
+
+		It("should return all versions in ascending order of block number", func() {
+			versions, err := store.GetAccountVersions(acct.Address)
+			Expect(err).To(BeNil())
+			Expect(versions).To(HaveLen(2))
+			Expect(versions[0].BlockNumber).To(Equal(uint64(1)))
+			Expect(versions[0].Account).To(Equal(acct))
+			Expect(versions[1].BlockNumber).To(Equal(uint64(3)))
+			Expect(versions[1].Account).To(Equal(acct2))
+		})
+
+		It("should return only versions within the block range option", func() {
+			versions, err := store.GetAccountVersions(acct.Address, &common.OpBlockQueryRange{Min: 2, Max: 3})
+			Expect(err).To(BeNil())
+			Expect(versions).To(HaveLen(1))
+			Expect(versions[0].Account).To(Equal(acct2))
+		})
+	})
+
 	Describe(".GetAccounts", func() {
 		Context("when two accounts are stored", func() {
 
@@ -375,6 +564,47 @@ var _ = Describe("Store", func() {
 
 	})
 
+	Describe(".GetName", func() {
+
+		putName := func(blockNum uint64, record *types.NameRecord) {
+			key := common.MakeKeyName(blockNum, chainID.Bytes(), record.Name.Bytes())
+			Expect(store.put(key, util.ObjectToBytes(record))).To(BeNil())
+		}
+
+		BeforeEach(func() {
+			putName(1, &types.NameRecord{Name: "alice", Address: "addr", ExpiresAt: 10})
+			putName(2, &types.NameRecord{Name: "alice", Address: "addr2", ExpiresAt: 10})
+			putName(2, &types.NameRecord{Name: "alice-2", Address: "addr3", ExpiresAt: 10})
+		})
+
+		It("should return ErrNameNotFound if the name does not exist", func() {
+			_, err := store.GetName("bob")
+			Expect(err).To(Equal(core.ErrNameNotFound))
+		})
+
+		It("should return the record at the highest block", func() {
+			record, err := store.GetName("alice")
+			Expect(err).To(BeNil())
+			Expect(record.Address).To(Equal(util.String("addr2")))
+		})
+
+		It("should return the record at the highest block within the block range", func() {
+			record, err := store.GetName("alice", &common.OpBlockQueryRange{Max: 1})
+			Expect(err).To(BeNil())
+			Expect(record.Address).To(Equal(util.String("addr")))
+		})
+
+		Describe(".GetNames", func() {
+			It("should return the latest record of each name", func() {
+				records, err := store.GetNames()
+				Expect(err).To(BeNil())
+				Expect(records).To(HaveLen(2))
+				Expect(records).To(ContainElement(&types.NameRecord{Name: "alice", Address: "addr2", ExpiresAt: 10}))
+				Expect(records).To(ContainElement(&types.NameRecord{Name: "alice-2", Address: "addr3", ExpiresAt: 10}))
+			})
+		})
+	})
+
 	Describe(".PutBlock", func() {
 
 		var block *core.Block
@@ -0,0 +1,71 @@
+package blockchain
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/ellcrys/elld/params"
+	"github.com/ellcrys/elld/types"
+	"github.com/ellcrys/elld/util"
+)
+
+// medianTimePast returns the median timestamp of the
+// block with the given hash and the blocks before it,
+// up to params.MedianTimeBlocks blocks.
+func medianTimePast(bchain types.Blockchain, hash util.Hash,
+	opts ...types.CallOp) (int64, error) {
+
+	var timestamps []int64
+	for len(timestamps) < params.MedianTimeBlocks {
+		block, err := bchain.GetBlockByHash(hash, opts...)
+		if err != nil {
+			return 0, err
+		}
+
+		timestamps = append(timestamps, block.GetHeader().GetTimestamp())
+		if block.GetNumber() == 1 {
+			break
+		}
+		hash = block.GetHeader().GetParentHash()
+	}
+
+	sort.Slice(timestamps, func(i, j int) bool {
+		return timestamps[i] < timestamps[j]
+	})
+
+	return timestamps[len(timestamps)/2], nil
+}
+
+// checkLockTime checks whether a time-locked
+// transaction can be included in a block with the
+// given number and parent. It returns an error
+// describing the lock if it cannot.
+func checkLockTime(bchain types.Blockchain, tx types.Transaction,
+	blockNumber uint64, parentHash util.Hash, opts ...types.CallOp) error {
+
+	lockTime := tx.GetLockTime()
+	if lockTime == 0 {
+		return nil
+	}
+
+	// The lock time is a block number
+	if lockTime < params.LockTimeThreshold {
+		if blockNumber < lockTime {
+			return fmt.Errorf("transaction is locked until block %d", lockTime)
+		}
+		return nil
+	}
+
+	// The lock time is a timestamp. It is compared
+	// with the median time of the previous blocks
+	medianTime, err := medianTimePast(bchain, parentHash, opts...)
+	if err != nil {
+		return fmt.Errorf("failed to get median time: %s", err)
+	}
+
+	if uint64(medianTime) < lockTime {
+		return fmt.Errorf("transaction is locked until time %d", lockTime)
+	}
+
+	return nil
+}
@@ -0,0 +1,131 @@
+package blockchain
+
+import (
+	"math/big"
+	"os"
+	"time"
+
+	. "github.com/ellcrys/elld/blockchain/testutil"
+	"github.com/ellcrys/elld/blockchain/txpool"
+	"github.com/ellcrys/elld/config"
+	"github.com/ellcrys/elld/crypto"
+	"github.com/ellcrys/elld/elldb"
+	"github.com/ellcrys/elld/testutil"
+	"github.com/ellcrys/elld/types"
+	"github.com/ellcrys/elld/types/core"
+	"github.com/ellcrys/elld/util"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("TimeLock", func() {
+
+	var err error
+	var bc *Blockchain
+	var cfg *config.EngineConfig
+	var db elldb.DB
+	var genesisBlock types.Block
+	var sender, receiver *crypto.Key
+
+	BeforeEach(func() {
+		cfg, err = testutil.SetTestCfg()
+		Expect(err).To(BeNil())
+
+		db = elldb.NewDB(cfg.NetDataDir())
+		err = db.Open(util.RandString(5))
+		Expect(err).To(BeNil())
+
+		sender = crypto.NewKeyFromIntSeed(1)
+		receiver = crypto.NewKeyFromIntSeed(2)
+
+		bc = New(txpool.New(100, nil), nil, newEngine(nil), cfg, log)
+		bc.SetDB(db)
+		bc.SetCoinbase(crypto.NewKeyFromIntSeed(1234))
+
+		genesisBlock, err = LoadBlockFromFile("genesis-test.json")
+		Expect(err).To(BeNil())
+		bc.SetGenesisBlock(genesisBlock)
+		err = bc.Up()
+		Expect(err).To(BeNil())
+	})
+
+	AfterEach(func() {
+		db.Close()
+		err = os.RemoveAll(cfg.DataDir())
+		Expect(err).To(BeNil())
+	})
+
+	makeBlock := func(nonce uint64, timestamp int64, lockTime uint64) types.Block {
+		tx := core.NewTx(core.TxTypeBalance, nonce, receiver.Addr(), sender, "1", "2.5", time.Now().UnixNano())
+		tx.LockTime = lockTime
+		tx.Hash = tx.ComputeHash()
+		tx.Sig, _ = core.TxSign(tx, sender.PrivKey().Base58())
+		return MakeTestBlock(bc, bc.bestChain, &types.GenerateBlockParams{
+			Transactions:      []types.Transaction{tx},
+			Creator:           sender,
+			Nonce:             util.EncodeNonce(1),
+			Difficulty:        new(big.Int).SetInt64(131072),
+			OverrideTimestamp: timestamp,
+		})
+	}
+
+	Describe(".medianTimePast", func() {
+		It("should return the median timestamp of the block and its ancestors", func() {
+			now := time.Now().Unix()
+			for i, ts := range []int64{now - 30, now - 20, now - 10} {
+				_, err = bc.ProcessBlock(makeBlock(uint64(i+1), ts, 0))
+				Expect(err).To(BeNil())
+			}
+
+			tip, err := bc.bestChain.GetBlock(0)
+			Expect(err).To(BeNil())
+
+			median, err := medianTimePast(bc, tip.GetHash())
+			Expect(err).To(BeNil())
+			Expect(median).To(Equal(now - 20))
+		})
+	})
+
+	Describe(".checkLockTime", func() {
+
+		It("should return nil if the transaction is not locked", func() {
+			tx := core.NewTx(core.TxTypeBalance, 1, receiver.Addr(), sender, "1", "2.5", time.Now().Unix())
+			Expect(checkLockTime(bc, tx, 2, genesisBlock.GetHash())).To(BeNil())
+		})
+
+		It("should return error if the block number is lower than the lock time", func() {
+			tx := core.NewTx(core.TxTypeBalance, 1, receiver.Addr(), sender, "1", "2.5", time.Now().Unix())
+			tx.LockTime = 3
+			err := checkLockTime(bc, tx, 2, genesisBlock.GetHash())
+			Expect(err).ToNot(BeNil())
+			Expect(err.Error()).To(Equal("transaction is locked until block 3"))
+			Expect(checkLockTime(bc, tx, 3, genesisBlock.GetHash())).To(BeNil())
+		})
+
+		It("should return error if the median time is lower than the lock time", func() {
+			tx := core.NewTx(core.TxTypeBalance, 1, receiver.Addr(), sender, "1", "2.5", time.Now().Unix())
+			tx.LockTime = uint64(genesisBlock.GetHeader().GetTimestamp() + 1)
+			err := checkLockTime(bc, tx, 2, genesisBlock.GetHash())
+			Expect(err).ToNot(BeNil())
+			Expect(err.Error()).To(ContainSubstring("transaction is locked until time"))
+
+			tx.LockTime = uint64(genesisBlock.GetHeader().GetTimestamp())
+			Expect(checkLockTime(bc, tx, 2, genesisBlock.GetHash())).To(BeNil())
+		})
+	})
+
+	Describe(".ProcessBlock", func() {
+		It("should reject a block that includes a transaction before its lock time", func() {
+			block := makeBlock(1, time.Now().Unix(), 3)
+			_, err = bc.ProcessBlock(block)
+			Expect(err).ToNot(BeNil())
+			Expect(err.Error()).To(ContainSubstring("transaction is locked until block 3"))
+		})
+
+		It("should accept a block that includes a transaction at its lock time", func() {
+			block := makeBlock(1, time.Now().Unix(), 2)
+			_, err = bc.ProcessBlock(block)
+			Expect(err).To(BeNil())
+		})
+	})
+})
@@ -21,6 +21,11 @@ import (
 var KnownTransactionTypes = []int64{
 	core.TxTypeBalance,
 	core.TxTypeAlloc,
+	core.TxTypeMultiSigRegister,
+	core.TxTypeMultiSigSpend,
+	core.TxTypeNameRegister,
+	core.TxTypeNameRenew,
+	core.TxTypeNameTransfer,
 }
 
 // TxsValidator implements a validator for checking
@@ -45,6 +50,14 @@ type TxsValidator struct {
 
 	// nonces caches valid nonces
 	nonces map[string]uint64
+
+	// block is the block the transactions are
+	// included in. It is nil when the transactions
+	// are not validated as part of a block.
+	block types.Block
+
+	// spec contains the consensus parameters of the network
+	spec *params.ChainSpec
 }
 
 func appendErr(dest []error, err error) []error {
@@ -62,6 +75,7 @@ func NewTxsValidator(txs []types.Transaction, txPool types.TxPool,
 		txpool: txPool,
 		bchain: bchain,
 		nonces: make(map[string]uint64),
+		spec:   getChainSpec(bchain),
 	}
 }
 
@@ -74,6 +88,7 @@ func NewTxValidator(tx types.Transaction, txPool types.TxPool,
 		txpool: txPool,
 		bchain: bchain,
 		nonces: make(map[string]uint64),
+		spec:   getChainSpec(bchain),
 	}
 }
 
@@ -139,6 +154,15 @@ func (v *TxsValidator) CheckFields(tx types.Transaction) (errs []error) {
 		}
 	}
 
+	var validRecipientRule = func(err error) func(interface{}) error {
+		return func(val interface{}) error {
+			if canSendToName(tx) && isName(val.(util.String)) {
+				return nil
+			}
+			return validAddrRule(err)(val)
+		}
+	}
+
 	var isDerivedFromPublicKeyRule = func(err error) func(interface{}) error {
 		return func(val interface{}) error {
 			pk, _ := crypto.PubKeyFromBase58(tx.GetSenderPubKey().String())
@@ -195,20 +219,53 @@ func (v *TxsValidator) CheckFields(tx types.Transaction) (errs []error) {
 			"unsupported transaction type"))),
 	))
 
-	// Recipient's address must be set and it must be valid
+	// Recipient's address must be set and it must be valid.
+	// Some transaction types can use a name as the recipient.
 	errs = appendErr(errs, validation.Validate(tx.GetTo(),
 		validation.Required.Error(fieldErrorWithIndex(v.curIndex, "to",
 			"recipient address is required").Error()),
-		validation.By(validAddrRule(fieldErrorWithIndex(v.curIndex, "to",
+		validation.By(validRecipientRule(fieldErrorWithIndex(v.curIndex, "to",
 			"recipient address is not valid"))),
 	))
 
+	// The name is required for name transactions
+	// and must be valid. Other transactions
+	// must not include a name.
+	if isNameTx(tx) {
+		errs = appendErr(errs, validation.Validate(tx.GetName(),
+			validation.Required.Error(fieldErrorWithIndex(v.curIndex, "name",
+				"name is required").Error()),
+			validation.By(func(val interface{}) error {
+				if !core.IsValidName(val.(util.String).String()) {
+					return fieldErrorWithIndex(v.curIndex, "name", "name is not valid")
+				}
+				return nil
+			}),
+		))
+	} else if tx.GetName() != "" {
+		errs = append(errs, fieldErrorWithIndex(v.curIndex, "name",
+			"not allowed for this transaction type"))
+	}
+
 	// Value must be >= 0 and it must be valid number
-	errs = appendErr(errs, validation.Validate(tx.GetValue(),
+	valueErr := validation.Validate(tx.GetValue(),
 		validation.Required.Error(fieldErrorWithIndex(v.curIndex, "value",
 			"value is required").Error()),
 		validation.By(validValueRule("value")),
-	))
+	)
+	errs = appendErr(errs, valueErr)
+
+	// Once ForkNonZeroTransfer is active, balance
+	// transactions must transfer a value
+	if valueErr == nil && tx.GetType() == core.TxTypeBalance &&
+		tx.GetValue().Decimal().IsZero() {
+		active, err := v.isForkActive(params.ForkNonZeroTransfer)
+		errs = appendErr(errs, err)
+		if active {
+			errs = appendErr(errs, fieldErrorWithIndex(v.curIndex, "value",
+				"value must be greater than zero"))
+		}
+	}
 
 	// Timestamp is required.
 	errs = appendErr(errs, validation.Validate(tx.GetTimestamp(),
@@ -216,23 +273,48 @@ func (v *TxsValidator) CheckFields(tx types.Transaction) (errs []error) {
 			"timestamp is required").Error()),
 	))
 
-	// Sender's public key is required and must be a valid base58 encoded key
-	errs = appendErr(errs, validation.Validate(tx.GetSenderPubKey(),
-		validation.Required.Error(fieldErrorWithIndex(v.curIndex, "senderPubKey",
-			"sender public key is required").Error()),
-		validation.By(validPubKeyRule(fieldErrorWithIndex(v.curIndex, "senderPubKey",
-			"sender public key is not valid"))),
-	))
+	// A multi-signature spend transaction has no single
+	// sender public key. Its sender address and signatures
+	// are checked against the multi-signature account
+	// description instead.
+	if tx.GetType() == core.TxTypeMultiSigSpend {
+
+		// Sender's address must be set and must also be valid
+		errs = appendErr(errs, validation.Validate(tx.GetFrom(),
+			validation.Required.Error(fieldErrorWithIndex(v.curIndex, "from",
+				"sender address is required").Error()),
+			validation.By(validAddrRule(fieldErrorWithIndex(v.curIndex, "from",
+				"sender address is not valid"))),
+		))
+
+		// Signatures of the signers must be set
+		errs = appendErr(errs, validation.Validate(tx.GetMultiSigSignatures(),
+			validation.Required.Error(fieldErrorWithIndex(v.curIndex, "multiSigSigs",
+				"signatures are required").Error()),
+		))
+	} else {
 
-	// Sender's address must be set and must also be valid
-	errs = appendErr(errs, validation.Validate(tx.GetFrom(),
-		validation.Required.Error(fieldErrorWithIndex(v.curIndex, "from",
-			"sender address is required").Error()),
-		validation.By(validAddrRule(fieldErrorWithIndex(v.curIndex, "from",
-			"sender address is not valid"))),
-		validation.By(isDerivedFromPublicKeyRule(fieldErrorWithIndex(v.curIndex, "from",
-			"sender address is not derived from the sender public key"))),
-	))
+		// Sender's public key is required and must be a valid base58 encoded key
+		errs = appendErr(errs, validation.Validate(tx.GetSenderPubKey(),
+			validation.Required.Error(fieldErrorWithIndex(v.curIndex, "senderPubKey",
+				"sender public key is required").Error()),
+			validation.By(validPubKeyRule(fieldErrorWithIndex(v.curIndex, "senderPubKey",
+				"sender public key is not valid"))),
+		))
+
+		// Sender's address must be set and must also be valid
+		errs = appendErr(errs, validation.Validate(tx.GetFrom(),
+			validation.Required.Error(fieldErrorWithIndex(v.curIndex, "from",
+				"sender address is required").Error()),
+			validation.By(validAddrRule(fieldErrorWithIndex(v.curIndex, "from",
+				"sender address is not valid"))),
+			validation.By(isDerivedFromPublicKeyRule(fieldErrorWithIndex(v.curIndex, "from",
+				"sender address is not derived from the sender public key"))),
+		))
+	}
+
+	// Check the multi-signature account description
+	errs = append(errs, v.checkMultiSig(tx)...)
 
 	// Hash is required. It must also be correct
 	errs = appendErr(errs, validation.Validate(tx.GetHash(),
@@ -243,10 +325,12 @@ func (v *TxsValidator) CheckFields(tx types.Transaction) (errs []error) {
 	))
 
 	// Signature must be set
-	errs = appendErr(errs, validation.Validate(tx.GetSignature(),
-		validation.Required.Error(fieldErrorWithIndex(v.curIndex, "sig",
-			"signature is required").Error()),
-	))
+	if tx.GetType() != core.TxTypeMultiSigSpend {
+		errs = appendErr(errs, validation.Validate(tx.GetSignature(),
+			validation.Required.Error(fieldErrorWithIndex(v.curIndex, "sig",
+				"signature is required").Error()),
+		))
+	}
 
 	// For non allocations, fee is required.
 	// It must be a number. It must be equal to the
@@ -266,14 +350,21 @@ func (v *TxsValidator) CheckFields(tx types.Transaction) (errs []error) {
 			fee := tx.GetFee().Decimal()
 			txSize := decimal.NewFromFloat(float64(tx.GetSizeNoFee()))
 
-			// Calculate the expected fee
-			expectedMinimumFee := params.FeePerByte.Mul(txSize)
-
-			// Compare the expected fee with the provided fee
-			if expectedMinimumFee.GreaterThan(fee) {
-				errs = appendErr(errs, fieldErrorWithIndex(v.curIndex, "fee",
-					fmt.Sprintf("fee is too low. Minimum fee expected: %s (for %s bytes)",
-						expectedMinimumFee.String(), txSize.String())))
+			// Get the fee per byte that applies to
+			// the block the transaction is included in
+			spec, specErr := v.getBlockSpec()
+			errs = appendErr(errs, specErr)
+			if specErr == nil {
+
+				// Calculate the expected fee
+				expectedMinimumFee := spec.FeePerByte.Mul(txSize)
+
+				// Compare the expected fee with the provided fee
+				if expectedMinimumFee.GreaterThan(fee) {
+					errs = appendErr(errs, fieldErrorWithIndex(v.curIndex, "fee",
+						fmt.Sprintf("fee is too low. Minimum fee expected: %s (for %s bytes)",
+							expectedMinimumFee.String(), txSize.String())))
+				}
 			}
 		}
 	}
@@ -286,10 +377,145 @@ func (v *TxsValidator) CheckFields(tx types.Transaction) (errs []error) {
 	return
 }
 
+// checkMultiSig checks the multi-signature account
+// description of a transaction. Only TxTypeMultiSigRegister
+// and TxTypeMultiSigSpend transactions can include it.
+func (v *TxsValidator) checkMultiSig(tx types.Transaction) (errs []error) {
+
+	multiSig := tx.GetMultiSig()
+
+	if tx.GetType() != core.TxTypeMultiSigRegister &&
+		tx.GetType() != core.TxTypeMultiSigSpend {
+		if multiSig != nil || len(tx.GetMultiSigSignatures()) > 0 {
+			errs = append(errs, fieldErrorWithIndex(v.curIndex, "multiSig",
+				"not allowed for this transaction type"))
+		}
+		return
+	}
+
+	if multiSig == nil {
+		errs = append(errs, fieldErrorWithIndex(v.curIndex, "multiSig",
+			"multi-signature account description is required"))
+		return
+	}
+
+	numPubKeys := len(multiSig.PubKeys)
+	if numPubKeys == 0 {
+		errs = append(errs, fieldErrorWithIndex(v.curIndex, "multiSig.pubKeys",
+			"at least one public key is required"))
+		return
+	}
+
+	if numPubKeys > params.MaxMultiSigPubKeys {
+		errs = append(errs, fieldErrorWithIndex(v.curIndex, "multiSig.pubKeys",
+			fmt.Sprintf("too many public keys. Max: %d", params.MaxMultiSigPubKeys)))
+		return
+	}
+
+	var seen = make(map[util.String]struct{})
+	for i, pk := range multiSig.PubKeys {
+		if _, err := crypto.PubKeyFromBase58(pk.String()); err != nil {
+			errs = append(errs, fieldErrorWithIndex(v.curIndex, "multiSig.pubKeys",
+				fmt.Sprintf("public key at index %d is not valid", i)))
+			return
+		}
+		if _, ok := seen[pk]; ok {
+			errs = append(errs, fieldErrorWithIndex(v.curIndex, "multiSig.pubKeys",
+				fmt.Sprintf("public key at index %d is a duplicate", i)))
+			return
+		}
+		seen[pk] = struct{}{}
+	}
+
+	if multiSig.Threshold == 0 || int(multiSig.Threshold) > numPubKeys {
+		errs = append(errs, fieldErrorWithIndex(v.curIndex, "multiSig.threshold",
+			"threshold must be between 1 and the number of public keys"))
+		return
+	}
+
+	// The registered account must be the recipient
+	// of a TxTypeMultiSigRegister transaction and the
+	// sender of a TxTypeMultiSigSpend transaction
+	if tx.GetType() == core.TxTypeMultiSigRegister &&
+		!tx.GetTo().Equal(multiSig.Address()) {
+		errs = append(errs, fieldErrorWithIndex(v.curIndex, "to",
+			"recipient address is not derived from the multi-signature account"))
+	}
+
+	if tx.GetType() == core.TxTypeMultiSigSpend &&
+		!tx.GetFrom().Equal(multiSig.Address()) {
+		errs = append(errs, fieldErrorWithIndex(v.curIndex, "from",
+			"sender address is not derived from the multi-signature account"))
+	}
+
+	return
+}
+
+// checkMultiSigSignatures checks whether the signatures
+// of a TxTypeMultiSigSpend transaction are valid and
+// whether enough signers have signed it.
+// Expects the transaction to have a valid
+// multi-signature account description.
+func (v *TxsValidator) checkMultiSigSignatures(tx types.Transaction) (errs []error) {
+
+	multiSig := tx.GetMultiSig()
+	if multiSig == nil {
+		return
+	}
+
+	var seen = make(map[util.String]struct{})
+	for i, sig := range tx.GetMultiSigSignatures() {
+
+		if !multiSig.HasPubKey(sig.PubKey) {
+			errs = append(errs, fieldErrorWithIndex(v.curIndex, "multiSigSigs",
+				fmt.Sprintf("signer at index %d is not a member of the account", i)))
+			return
+		}
+
+		if _, ok := seen[sig.PubKey]; ok {
+			errs = append(errs, fieldErrorWithIndex(v.curIndex, "multiSigSigs",
+				fmt.Sprintf("signer at index %d has already signed", i)))
+			return
+		}
+		seen[sig.PubKey] = struct{}{}
+
+		pubKey, err := crypto.PubKeyFromBase58(sig.PubKey.String())
+		if err != nil {
+			errs = append(errs, fieldErrorWithIndex(v.curIndex,
+				"multiSigSigs", err.Error()))
+			return
+		}
+
+		valid, err := pubKey.Verify(tx.GetBytesNoHashAndSig(), sig.Sig)
+		if err != nil {
+			errs = append(errs, fieldErrorWithIndex(v.curIndex,
+				"multiSigSigs", err.Error()))
+			return
+		} else if !valid {
+			errs = append(errs, fieldErrorWithIndex(v.curIndex, "multiSigSigs",
+				fmt.Sprintf("signature at index %d is not valid", i)))
+			return
+		}
+	}
+
+	if len(seen) < int(multiSig.Threshold) {
+		errs = append(errs, fieldErrorWithIndex(v.curIndex, "multiSigSigs",
+			fmt.Sprintf("not enough signatures: has %d, wants %d",
+				len(seen), multiSig.Threshold)))
+	}
+
+	return
+}
+
 // checkSignature checks whether the signature is valid.
 // Expects the transaction to have a valid sender public key
+// unless it is a TxTypeMultiSigSpend transaction.
 func (v *TxsValidator) checkSignature(tx types.Transaction) (errs []error) {
 
+	if tx.GetType() == core.TxTypeMultiSigSpend {
+		return v.checkMultiSigSignatures(tx)
+	}
+
 	pubKey, err := crypto.PubKeyFromBase58(tx.GetSenderPubKey().String())
 	if err != nil {
 		errs = append(errs, fieldErrorWithIndex(v.curIndex,
@@ -329,6 +555,19 @@ func (v *TxsValidator) consistencyCheck(tx types.Transaction, opts ...types.Call
 		return
 	}
 
+	// A time-locked transaction can only be included
+	// in a block once its lock time has been reached.
+	// Transactions not validated as part of a block
+	// (e.g the tx pool) are held until they mature.
+	if v.block != nil {
+		if err := checkLockTime(v.bchain, tx, v.block.GetNumber(),
+			v.block.GetHeader().GetParentHash(), opts...); err != nil {
+			errs = append(errs, fieldErrorWithIndex(v.curIndex,
+				"lockTime", err.Error()))
+			return
+		}
+	}
+
 	// No need performing nonce and balance checks for
 	// transactions inside a block that may be appended
 	// to a branch. This will be performed if the the
@@ -364,6 +603,37 @@ func (v *TxsValidator) consistencyCheck(tx types.Transaction, opts ...types.Call
 		return
 	}
 
+	// The sender of a TxTypeMultiSigSpend transaction
+	// must be a registered multi-signature account
+	if tx.GetType() == core.TxTypeMultiSigSpend &&
+		account.GetType() != core.AccountTypeMultiSig {
+		errs = append(errs, fieldErrorWithIndex(v.curIndex,
+			"from", "sender is not a multi-signature account"))
+		return
+	}
+
+	// A multi-signature account can only be registered once
+	if tx.GetType() == core.TxTypeMultiSigRegister {
+		recipient, err := v.bchain.GetAccount(tx.GetTo(), opts...)
+		if err != nil && err != core.ErrAccountNotFound {
+			errs = append(errs, fmt.Errorf("failed to get account: %s", err))
+			return
+		}
+		if recipient != nil && recipient.GetMultiSig() != nil {
+			errs = append(errs, fieldErrorWithIndex(v.curIndex,
+				"to", "multi-signature account is already registered"))
+			return
+		}
+	}
+
+	// Check the name of a name transaction and
+	// ensure a recipient name is registered
+	if isNameTx(tx) || (canSendToName(tx) && isName(tx.GetTo())) {
+		if nameErrs := v.checkName(tx, opts...); len(nameErrs) > 0 {
+			return append(errs, nameErrs...)
+		}
+	}
+
 	// Check whether the sender has sufficient
 	// balance to cover the value + fee
 	deductable := tx.GetValue().Decimal().Add(tx.GetFee().Decimal())
@@ -405,6 +675,89 @@ func (v *TxsValidator) consistencyCheck(tx types.Transaction, opts ...types.Call
 	return
 }
 
+// getBlockNumber returns the number of the block
+// the transactions will be included in
+func (v *TxsValidator) getBlockNumber(opts ...types.CallOp) (uint64, error) {
+	if v.block != nil {
+		return v.block.GetNumber(), nil
+	}
+	tip, err := v.bchain.ChainReader().Current(opts...)
+	if err != nil {
+		if err == core.ErrBlockNotFound {
+			return 1, nil
+		}
+		return 0, fmt.Errorf("failed to get tip block: %s", err)
+	}
+	return tip.GetNumber() + 1, nil
+}
+
+// getBlockSpec returns the chain specification that
+// applies to the block the transactions will be
+// included in. The base specification is returned if
+// no fork is scheduled or the validator has neither a
+// block nor a blockchain.
+func (v *TxsValidator) getBlockSpec(opts ...types.CallOp) (*params.ChainSpec, error) {
+	if len(v.spec.Forks) == 0 || (v.block == nil && v.bchain == nil) {
+		return v.spec, nil
+	}
+	blockNumber, err := v.getBlockNumber(opts...)
+	if err != nil {
+		return nil, err
+	}
+	return v.spec.At(blockNumber), nil
+}
+
+// isForkActive checks whether a fork is active at the
+// block the transactions will be included in. It returns
+// false if the validator has neither a block nor a blockchain.
+func (v *TxsValidator) isForkActive(fork string, opts ...types.CallOp) (bool, error) {
+	if len(v.spec.Forks) == 0 || (v.block == nil && v.bchain == nil) {
+		return false, nil
+	}
+	blockNumber, err := v.getBlockNumber(opts...)
+	if err != nil {
+		return false, err
+	}
+	return v.spec.IsActive(fork, blockNumber), nil
+}
+
+// checkName checks the name of a name transaction against
+// its current record. For other transactions, it checks
+// whether the recipient name is registered.
+func (v *TxsValidator) checkName(tx types.Transaction, opts ...types.CallOp) (errs []error) {
+
+	blockNumber, err := v.getBlockNumber(opts...)
+	if err != nil {
+		errs = append(errs, err)
+		return
+	}
+
+	if !isNameTx(tx) {
+		record, err := v.bchain.GetName(tx.GetTo(), opts...)
+		if err != nil && err != core.ErrNameNotFound {
+			errs = append(errs, fmt.Errorf("failed to get name: %s", err))
+			return
+		}
+		if record == nil || record.IsExpired(blockNumber) {
+			errs = append(errs, fieldErrorWithIndex(v.curIndex,
+				"to", "recipient name is not registered"))
+		}
+		return
+	}
+
+	record, err := v.bchain.GetName(tx.GetName(), opts...)
+	if err != nil && err != core.ErrNameNotFound {
+		errs = append(errs, fmt.Errorf("failed to get name: %s", err))
+		return
+	}
+
+	if err := checkNameTx(tx, record, blockNumber); err != nil {
+		errs = append(errs, fieldErrorWithIndex(v.curIndex, "name", err.Error()))
+	}
+
+	return
+}
+
 // ValidateTx validates a single transaction coming received
 // by the gossip handler..
 func (v *TxsValidator) ValidateTx(tx types.Transaction, opts ...types.CallOp) []error {
@@ -11,6 +11,7 @@ import (
 	"github.com/ellcrys/elld/config"
 	"github.com/ellcrys/elld/crypto"
 	"github.com/ellcrys/elld/elldb"
+	"github.com/ellcrys/elld/params"
 	"github.com/ellcrys/elld/testutil"
 	"github.com/ellcrys/elld/types"
 	"github.com/ellcrys/elld/types/core"
@@ -41,7 +42,7 @@ var _ = Describe("TransactionValidator", func() {
 		sender = crypto.NewKeyFromIntSeed(1)
 		receiver = crypto.NewKeyFromIntSeed(2)
 
-		bc = New(txpool.New(100), cfg, log)
+		bc = New(txpool.New(100, nil), nil, newEngine(nil), cfg, log)
 		bc.SetDB(db)
 		bc.SetCoinbase(crypto.NewKeyFromIntSeed(1234))
 	})
@@ -83,11 +84,31 @@ var _ = Describe("TransactionValidator", func() {
 				}: fmt.Errorf("index:0, field:to, error:recipient address is required"),
 
 				&core.Transaction{
-					To:    "invalid",
+					To:    "invalid_address",
 					Type:  core.TxTypeBalance,
 					Nonce: 0,
 				}: fmt.Errorf("index:0, field:to, error:recipient address is not valid"),
 
+				&core.Transaction{
+					To:    "alice",
+					Type:  core.TxTypeAlloc,
+					Nonce: 0,
+				}: fmt.Errorf("index:0, field:to, error:recipient address is not valid"),
+
+				&core.Transaction{
+					Type: core.TxTypeNameRegister,
+				}: fmt.Errorf("index:0, field:name, error:name is required"),
+
+				&core.Transaction{
+					Type: core.TxTypeNameRegister,
+					Name: "Not_Valid",
+				}: fmt.Errorf("index:0, field:name, error:name is not valid"),
+
+				&core.Transaction{
+					Type: core.TxTypeBalance,
+					Name: "alice",
+				}: fmt.Errorf("index:0, field:name, error:not allowed for this transaction type"),
+
 				&core.Transaction{
 					From:  "invalid",
 					Type:  core.TxTypeBalance,
@@ -174,7 +195,7 @@ var _ = Describe("TransactionValidator", func() {
 				})
 
 				It("should return duplicate transaction error", func() {
-					txp := txpool.New(1)
+					txp := txpool.New(1, nil)
 					validator = NewTxsValidator(txs, txp, bc)
 					errs := validator.Validate()
 					Expect(errs).To(ContainElement(fmt.Errorf("index:1, error:duplicate transaction")))
@@ -217,7 +238,7 @@ var _ = Describe("TransactionValidator", func() {
 						Expect(err).To(BeNil())
 						tx.Sig = sig
 
-						txp := txpool.New(1)
+						txp := txpool.New(1, nil)
 						validator = NewTxsValidator([]types.Transaction{tx}, txp, bc)
 						validator.addContext(types.ContextBranch)
 						errs := validator.Validate()
@@ -238,7 +259,7 @@ var _ = Describe("TransactionValidator", func() {
 				})
 
 				It("should return error if transaction already exists in the main chain", func() {
-					txp := txpool.New(1)
+					txp := txpool.New(1, nil)
 					validator = NewTxsValidator([]types.Transaction{tx}, txp, bc)
 					errs := validator.Validate()
 					Expect(errs).To(HaveLen(1))
@@ -249,6 +270,66 @@ var _ = Describe("TransactionValidator", func() {
 
 	})
 
+	Describe(".CheckFields: ForkNonZeroTransfer", func() {
+
+		var tx *core.Transaction
+		var valueErr = fmt.Errorf("index:0, field:value, error:value must be greater than zero")
+
+		BeforeEach(func() {
+			tx = core.NewTx(core.TxTypeBalance, 1, util.String(receiver.Addr()), sender, "0", "2.5", time.Now().Unix())
+		})
+
+		It("should allow a balance transaction that transfers no value if the fork is not active", func() {
+			bc.spec.Forks = []params.Fork{{Name: params.ForkNonZeroTransfer, ActivationHeight: 3}}
+			errs := NewTxValidator(tx, bc.txPool, bc).CheckFields(tx)
+			Expect(errs).ToNot(ContainElement(valueErr))
+		})
+
+		It("should reject a balance transaction that transfers no value once the fork is active", func() {
+			bc.spec.Forks = []params.Fork{{Name: params.ForkNonZeroTransfer, ActivationHeight: 2}}
+			errs := NewTxValidator(tx, bc.txPool, bc).CheckFields(tx)
+			Expect(errs).To(ContainElement(valueErr))
+		})
+	})
+
+	Describe(".getBlockSpec", func() {
+
+		var validator *TxsValidator
+		var tx *core.Transaction
+
+		BeforeEach(func() {
+			bc.spec.Forks = []params.Fork{
+				{Name: "fork1", ActivationHeight: 2, Params: []byte(`{"feePerByte":"1"}`)},
+				{Name: "fork2", ActivationHeight: 3, Params: []byte(`{"feePerByte":"2"}`)},
+			}
+			tx = core.NewTx(core.TxTypeBalance, 1, util.String(receiver.Addr()), sender, "1", "2.5", time.Now().Unix())
+			validator = NewTxValidator(tx, bc.txPool, bc)
+		})
+
+		It("should return the parameters of the next block", func() {
+			spec, err := validator.getBlockSpec()
+			Expect(err).To(BeNil())
+			Expect(spec.FeePerByte.String()).To(Equal("1"))
+		})
+
+		When("the transactions are validated as part of a block", func() {
+			It("should return the parameters of the block", func() {
+				block := MakeBlock(bc, genesisChain, sender, receiver)
+				block.GetHeader().SetNumber(3)
+				validator.block = block
+				spec, err := validator.getBlockSpec()
+				Expect(err).To(BeNil())
+				Expect(spec.FeePerByte.String()).To(Equal("2"))
+			})
+		})
+
+		It("should check the fee using the fee per byte of the active fork", func() {
+			errs := validator.CheckFields(tx)
+			Expect(errs).To(HaveLen(1))
+			Expect(errs[0].Error()).To(ContainSubstring("fee is too low"))
+		})
+	})
+
 	Describe(".checkSignature", func() {
 
 		var sender, receiver *crypto.Key
@@ -306,6 +387,100 @@ var _ = Describe("TransactionValidator", func() {
 		})
 	})
 
+	Describe(".checkMultiSig", func() {
+
+		var signer1, signer2 *crypto.Key
+		var multiSig *types.MultiSig
+
+		BeforeEach(func() {
+			signer1, signer2 = crypto.NewKeyFromIntSeed(3), crypto.NewKeyFromIntSeed(4)
+			multiSig = &types.MultiSig{
+				Threshold: 2,
+				PubKeys: []util.String{
+					util.String(signer1.PubKey().Base58()),
+					util.String(signer2.PubKey().Base58()),
+				},
+			}
+		})
+
+		It("should return err if a balance transaction includes a multi-signature account", func() {
+			tx := &core.Transaction{Type: core.TxTypeBalance, MultiSig: multiSig}
+			errs := NewTxsValidator(nil, nil, bc).checkMultiSig(tx)
+			Expect(errs).To(ContainElement(fmt.Errorf("index:0, field:multiSig, error:not allowed for this transaction type")))
+		})
+
+		It("should return err if the multi-signature account is not set", func() {
+			tx := &core.Transaction{Type: core.TxTypeMultiSigRegister}
+			errs := NewTxsValidator(nil, nil, bc).checkMultiSig(tx)
+			Expect(errs).To(ContainElement(fmt.Errorf("index:0, field:multiSig, error:multi-signature account description is required")))
+		})
+
+		It("should return err if a public key is duplicated", func() {
+			multiSig.PubKeys = append(multiSig.PubKeys, multiSig.PubKeys[0])
+			tx := &core.Transaction{Type: core.TxTypeMultiSigRegister, MultiSig: multiSig}
+			errs := NewTxsValidator(nil, nil, bc).checkMultiSig(tx)
+			Expect(errs).To(ContainElement(fmt.Errorf("index:0, field:multiSig.pubKeys, error:public key at index 2 is a duplicate")))
+		})
+
+		It("should return err if threshold is greater than the number of public keys", func() {
+			multiSig.Threshold = 3
+			tx := &core.Transaction{Type: core.TxTypeMultiSigRegister, MultiSig: multiSig}
+			errs := NewTxsValidator(nil, nil, bc).checkMultiSig(tx)
+			Expect(errs).To(ContainElement(fmt.Errorf("index:0, field:multiSig.threshold, error:threshold must be between 1 and the number of public keys")))
+		})
+
+		It("should return err if the recipient of a registration is not the multi-signature account", func() {
+			tx := &core.Transaction{Type: core.TxTypeMultiSigRegister, MultiSig: multiSig, To: receiver.Addr()}
+			errs := NewTxsValidator(nil, nil, bc).checkMultiSig(tx)
+			Expect(errs).To(ContainElement(fmt.Errorf("index:0, field:to, error:recipient address is not derived from the multi-signature account")))
+		})
+
+		It("should return no error if the registration is valid", func() {
+			tx := &core.Transaction{Type: core.TxTypeMultiSigRegister, MultiSig: multiSig, To: multiSig.Address()}
+			errs := NewTxsValidator(nil, nil, bc).checkMultiSig(tx)
+			Expect(errs).To(BeEmpty())
+		})
+
+		Describe(".checkSignature", func() {
+
+			It("should return no error if enough signers have signed", func() {
+				tx := core.NewMultiSigSpendTx(1, receiver.Addr(), multiSig, "1", "0.1",
+					time.Now().Unix(), signer1, signer2)
+				errs := NewTxsValidator(nil, nil, bc).checkSignature(tx)
+				Expect(errs).To(BeEmpty())
+			})
+
+			It("should return err if not enough signers have signed", func() {
+				tx := core.NewMultiSigSpendTx(1, receiver.Addr(), multiSig, "1", "0.1",
+					time.Now().Unix(), signer1)
+				errs := NewTxsValidator(nil, nil, bc).checkSignature(tx)
+				Expect(errs).To(ContainElement(fmt.Errorf("index:0, field:multiSigSigs, error:not enough signatures: has 1, wants 2")))
+			})
+
+			It("should return err if a signer signed more than once", func() {
+				tx := core.NewMultiSigSpendTx(1, receiver.Addr(), multiSig, "1", "0.1",
+					time.Now().Unix(), signer1, signer1)
+				errs := NewTxsValidator(nil, nil, bc).checkSignature(tx)
+				Expect(errs).To(ContainElement(fmt.Errorf("index:0, field:multiSigSigs, error:signer at index 1 has already signed")))
+			})
+
+			It("should return err if a signer is not a member of the account", func() {
+				tx := core.NewMultiSigSpendTx(1, receiver.Addr(), multiSig, "1", "0.1",
+					time.Now().Unix(), signer1, sender)
+				errs := NewTxsValidator(nil, nil, bc).checkSignature(tx)
+				Expect(errs).To(ContainElement(fmt.Errorf("index:0, field:multiSigSigs, error:signer at index 1 is not a member of the account")))
+			})
+
+			It("should return err if a signature is not valid", func() {
+				tx := core.NewMultiSigSpendTx(1, receiver.Addr(), multiSig, "1", "0.1",
+					time.Now().Unix(), signer1, signer2)
+				tx.MultiSigSigs[1].Sig = []byte("invalid")
+				errs := NewTxsValidator(nil, nil, bc).checkSignature(tx)
+				Expect(errs).To(ContainElement(fmt.Errorf("index:0, field:multiSigSigs, error:signature at index 1 is not valid")))
+			})
+		})
+	})
+
 	Describe(".consistencyCheck", func() {
 
 		var tx types.Transaction
@@ -313,7 +488,7 @@ var _ = Describe("TransactionValidator", func() {
 		var txp *txpool.TxPool
 
 		BeforeEach(func() {
-			txp = txpool.New(1)
+			txp = txpool.New(1, nil)
 			sender, receiver = crypto.NewKeyFromIntSeed(1), crypto.NewKeyFromIntSeed(2)
 			tx = &core.Transaction{
 				Type:         core.TxTypeBalance,
@@ -329,7 +504,7 @@ var _ = Describe("TransactionValidator", func() {
 		})
 
 		It("should return error when exact transaction exist in the pool", func() {
-			txp := txpool.New(1)
+			txp := txpool.New(1, nil)
 			Expect(txp.Put(tx)).To(BeNil())
 			validator := NewTxValidator(nil, txp, bc)
 			errs := validator.consistencyCheck(tx)
@@ -402,7 +577,7 @@ var _ = Describe("TransactionValidator", func() {
 			})
 
 			It("should return err='index:0, error:invalid nonce: has 0, wants from 1", func() {
-				txp := txpool.New(1)
+				txp := txpool.New(1, nil)
 
 				validator := NewTxValidator(nil, txp, bc)
 				errs := validator.consistencyCheck(tx2)
@@ -431,7 +606,7 @@ var _ = Describe("TransactionValidator", func() {
 			})
 
 			It("should return nil", func() {
-				txp := txpool.New(1)
+				txp := txpool.New(1, nil)
 				validator := NewTxValidator(nil, txp, bc)
 				errs := validator.consistencyCheck(tx2)
 				Expect(errs).To(BeNil())
@@ -457,7 +632,7 @@ var _ = Describe("TransactionValidator", func() {
 			})
 
 			It("should return err='index:0, error:invalid nonce: has 2, wants 1'", func() {
-				txp := txpool.New(1)
+				txp := txpool.New(1, nil)
 				validator := NewTxValidator(nil, txp, bc)
 				validator.addContext(types.ContextBlock)
 				errs := validator.consistencyCheck(tx2)
@@ -21,6 +21,15 @@ var (
 	// ErrTxAlreadyAdded is an error about a transaction
 	// that is in the pool.
 	ErrTxAlreadyAdded = fmt.Errorf("exact transaction already in the pool")
+
+	// ErrTxUnderpriced is an error about a transaction
+	// that does not pay enough to replace a pooled
+	// transaction with the same sender and nonce.
+	ErrTxUnderpriced = fmt.Errorf("replacement transaction fee rate is too low")
+
+	// ErrSenderQueueFull is an error about a sender
+	// that has too many queued transactions
+	ErrSenderQueueFull = fmt.Errorf("sender has too many queued transactions")
 )
 
 // ContainerItem represents the a container
@@ -37,6 +46,13 @@ func newItem(tx types.Transaction) *ContainerItem {
 	return item
 }
 
+// CalcFeeRate calculates the fee rate of a transaction.
+// formula: tx fee / size
+func CalcFeeRate(tx types.Transaction) util.String {
+	txSizeDec := decimal.NewFromBigInt(new(big.Int).SetInt64(tx.GetSizeNoFee()), 0)
+	return util.String(tx.GetFee().Decimal().Div(txSizeDec).StringFixed(params.Decimals))
+}
+
 // TxContainer represents the internal container
 // used by TxPool. It provides a Put operation
 // with automatic sorting by fee rate and nonce.
@@ -104,11 +120,7 @@ func (q *TxContainer) Add(tx types.Transaction) bool {
 	}
 
 	item := newItem(tx)
-
-	// Calculate the transaction's fee rate
-	// formula: tx fee / size
-	txSizeDec := decimal.NewFromBigInt(new(big.Int).SetInt64(tx.GetSizeNoFee()), 0)
-	item.FeeRate = util.String(tx.GetFee().Decimal().Div(txSizeDec).StringFixed(params.Decimals))
+	item.FeeRate = CalcFeeRate(tx)
 
 	q.gmx.Lock()
 	q.container = append(q.container, item)
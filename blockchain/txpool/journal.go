@@ -0,0 +1,270 @@
+package txpool
+
+import (
+	"os"
+	"sort"
+	"sync"
+
+	"github.com/ellcrys/elld/types"
+	"github.com/ellcrys/elld/types/core"
+	"github.com/ellcrys/elld/util"
+	"github.com/vmihailenco/msgpack"
+)
+
+const (
+	journalOpAdd    = "add"
+	journalOpRemove = "remove"
+)
+
+// journalEntry is a record of the journal
+type journalEntry struct {
+	Op    string            `msgpack:"op"`
+	Tx    *core.Transaction `msgpack:"tx"`
+	Local bool              `msgpack:"local"`
+}
+
+// JournalTx is a transaction restored from the journal
+type JournalTx struct {
+	types.Transaction
+
+	// Local indicates that the transaction
+	// was submitted locally
+	Local bool
+}
+
+// Journal records the transactions added to and
+// removed from the pool in a file so that they
+// can be restored when the node restarts.
+type Journal struct {
+	sync.Mutex
+	path       string
+	file       *os.File
+	hashes     map[util.Hash]struct{}
+	numRemoved int
+}
+
+// NewJournal creates a journal that
+// is stored in the file at path
+func NewJournal(path string) *Journal {
+	return &Journal{
+		path:   path,
+		hashes: make(map[util.Hash]struct{}),
+	}
+}
+
+// Load reads the journal and returns the transactions
+// that were added and not subsequently removed, ordered
+// by nonce. A journal that does not exist is treated as
+// empty. Reading stops at the first corrupt entry (e.g
+// an entry partially written before a crash).
+func (j *Journal) Load() ([]*JournalTx, error) {
+	j.Lock()
+	defer j.Unlock()
+
+	file, err := os.Open(j.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer file.Close()
+
+	var txs []*JournalTx
+	var removed = make(map[util.Hash]struct{})
+	dec := msgpack.NewDecoder(file)
+	for {
+		var entry journalEntry
+		if err := dec.Decode(&entry); err != nil {
+			break
+		}
+		if entry.Tx == nil {
+			continue
+		}
+		switch entry.Op {
+		case journalOpAdd:
+			delete(removed, entry.Tx.GetHash())
+			txs = append(txs, &JournalTx{Transaction: entry.Tx, Local: entry.Local})
+		case journalOpRemove:
+			removed[entry.Tx.GetHash()] = struct{}{}
+		}
+	}
+
+	var result []*JournalTx
+	var seen = make(map[util.Hash]struct{})
+	for _, tx := range txs {
+		if _, ok := removed[tx.GetHash()]; ok {
+			continue
+		}
+		if _, ok := seen[tx.GetHash()]; ok {
+			continue
+		}
+		seen[tx.GetHash()] = struct{}{}
+		result = append(result, tx)
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].GetNonce() < result[j].GetNonce()
+	})
+
+	return result, nil
+}
+
+// Open opens the journal file for appending, creating
+// it if it does not exist. The existing entries are kept
+// until the journal is rotated, so that they are not lost
+// if the node stops before they are restored.
+func (j *Journal) Open() error {
+	j.Lock()
+	defer j.Unlock()
+
+	if j.file != nil {
+		j.file.Close()
+	}
+
+	file, err := os.OpenFile(j.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+
+	j.file = file
+	j.hashes = make(map[util.Hash]struct{})
+	j.numRemoved = 0
+	return nil
+}
+
+// NeedsRotation checks whether the journal records
+// more removed transactions than live ones
+func (j *Journal) NeedsRotation() bool {
+	j.Lock()
+	defer j.Unlock()
+	return j.file != nil && j.numRemoved > len(j.hashes)
+}
+
+// Rotate replaces the journal with one that records
+// only the given transactions. The new journal is
+// written to a temporary file that is then renamed
+// over the journal, so a crash cannot leave the
+// journal incomplete. It does nothing if the
+// journal is not open.
+func (j *Journal) Rotate(txs []*JournalTx) error {
+	j.Lock()
+	defer j.Unlock()
+
+	if j.file == nil {
+		return nil
+	}
+
+	tmpPath := j.path + ".tmp"
+	file, err := os.OpenFile(tmpPath, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+
+	var hashes = make(map[util.Hash]struct{})
+	for _, tx := range txs {
+		coreTx, ok := tx.Transaction.(*core.Transaction)
+		if !ok {
+			continue
+		}
+		entry := journalEntry{Op: journalOpAdd, Tx: coreTx, Local: tx.Local}
+		if _, err := file.Write(util.ObjectToBytes(entry)); err != nil {
+			file.Close()
+			os.Remove(tmpPath)
+			return err
+		}
+		hashes[tx.GetHash()] = struct{}{}
+	}
+
+	if err := file.Sync(); err != nil {
+		file.Close()
+		os.Remove(tmpPath)
+		return err
+	}
+	if err := file.Close(); err != nil {
+		os.Remove(tmpPath)
+		return err
+	}
+	if err := os.Rename(tmpPath, j.path); err != nil {
+		os.Remove(tmpPath)
+		return err
+	}
+
+	j.file.Close()
+	j.file, err = os.OpenFile(j.path, os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		j.file = nil
+		return err
+	}
+
+	j.hashes = hashes
+	j.numRemoved = 0
+	return nil
+}
+
+// write appends an entry to the journal
+// file. (Not thread-safe)
+func (j *Journal) write(op string, tx types.Transaction, local bool) error {
+	if j.file == nil {
+		return nil
+	}
+	coreTx, ok := tx.(*core.Transaction)
+	if !ok {
+		return nil
+	}
+	_, err := j.file.Write(util.ObjectToBytes(journalEntry{Op: op, Tx: coreTx, Local: local}))
+	return err
+}
+
+// Insert records the addition of a transaction.
+// Local indicates whether the transaction was submitted
+// locally. A transaction that is already recorded is ignored.
+func (j *Journal) Insert(tx types.Transaction, local bool) error {
+	j.Lock()
+	defer j.Unlock()
+
+	if _, ok := j.hashes[tx.GetHash()]; ok {
+		return nil
+	}
+
+	if err := j.write(journalOpAdd, tx, local); err != nil {
+		return err
+	}
+
+	j.hashes[tx.GetHash()] = struct{}{}
+	return nil
+}
+
+// Remove records the removal of transactions.
+// Transactions that are not recorded are ignored.
+func (j *Journal) Remove(txs ...types.Transaction) error {
+	j.Lock()
+	defer j.Unlock()
+
+	for _, tx := range txs {
+		if _, ok := j.hashes[tx.GetHash()]; !ok {
+			continue
+		}
+		if err := j.write(journalOpRemove, tx, false); err != nil {
+			return err
+		}
+		delete(j.hashes, tx.GetHash())
+		j.numRemoved++
+	}
+
+	return nil
+}
+
+// Close closes the journal file
+func (j *Journal) Close() error {
+	j.Lock()
+	defer j.Unlock()
+
+	if j.file == nil {
+		return nil
+	}
+
+	err := j.file.Close()
+	j.file = nil
+	return err
+}
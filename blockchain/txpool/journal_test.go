@@ -0,0 +1,188 @@
+package txpool
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/ellcrys/elld/crypto"
+	"github.com/ellcrys/elld/types/core"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Journal", func() {
+
+	var dir string
+	var journal *Journal
+	var key = crypto.NewKeyFromIntSeed(1)
+	var tx, tx2 *core.Transaction
+
+	BeforeEach(func() {
+		var err error
+		dir, err = ioutil.TempDir("", "txpool")
+		Expect(err).To(BeNil())
+		journal = NewJournal(filepath.Join(dir, "txpool.journal"))
+		tx = core.NewTx(core.TxTypeBalance, 2, "a", key, "1", "0.1", time.Now().Unix())
+		tx2 = core.NewTx(core.TxTypeBalance, 1, "a", key, "1", "0.1", time.Now().Unix())
+	})
+
+	AfterEach(func() {
+		journal.Close()
+		os.RemoveAll(dir)
+	})
+
+	Describe(".Load", func() {
+
+		It("should return no transaction if the journal does not exist", func() {
+			txs, err := journal.Load()
+			Expect(err).To(BeNil())
+			Expect(txs).To(BeEmpty())
+		})
+
+		It("should return the recorded transactions ordered by nonce", func() {
+			Expect(journal.Open()).To(BeNil())
+			Expect(journal.Insert(tx, false)).To(BeNil())
+			Expect(journal.Insert(tx2, false)).To(BeNil())
+			Expect(journal.Close()).To(BeNil())
+
+			txs, err := journal.Load()
+			Expect(err).To(BeNil())
+			Expect(txs).To(HaveLen(2))
+			Expect(txs[0].GetHash()).To(Equal(tx2.GetHash()))
+			Expect(txs[1].GetHash()).To(Equal(tx.GetHash()))
+		})
+
+		It("should not return removed transactions", func() {
+			Expect(journal.Open()).To(BeNil())
+			Expect(journal.Insert(tx, false)).To(BeNil())
+			Expect(journal.Insert(tx2, false)).To(BeNil())
+			Expect(journal.Remove(tx)).To(BeNil())
+			Expect(journal.Close()).To(BeNil())
+
+			txs, err := journal.Load()
+			Expect(err).To(BeNil())
+			Expect(txs).To(HaveLen(1))
+			Expect(txs[0].GetHash()).To(Equal(tx2.GetHash()))
+		})
+
+		It("should ignore a partially written entry", func() {
+			Expect(journal.Open()).To(BeNil())
+			Expect(journal.Insert(tx, false)).To(BeNil())
+			_, err := journal.file.Write([]byte{0x82, 0xa2})
+			Expect(err).To(BeNil())
+			Expect(journal.Close()).To(BeNil())
+
+			txs, err := journal.Load()
+			Expect(err).To(BeNil())
+			Expect(txs).To(HaveLen(1))
+			Expect(txs[0].GetHash()).To(Equal(tx.GetHash()))
+		})
+	})
+
+	Describe(".Open", func() {
+		It("should keep the existing entries", func() {
+			Expect(journal.Open()).To(BeNil())
+			Expect(journal.Insert(tx, false)).To(BeNil())
+			Expect(journal.Open()).To(BeNil())
+			Expect(journal.Insert(tx2, false)).To(BeNil())
+			Expect(journal.Close()).To(BeNil())
+
+			txs, err := journal.Load()
+			Expect(err).To(BeNil())
+			Expect(txs).To(HaveLen(2))
+		})
+	})
+
+	Describe(".Rotate", func() {
+		It("should replace the existing entries with the given transactions", func() {
+			Expect(journal.Open()).To(BeNil())
+			Expect(journal.Insert(tx, false)).To(BeNil())
+			Expect(journal.Rotate([]*JournalTx{{Transaction: tx2, Local: true}})).To(BeNil())
+			Expect(journal.Remove(tx)).To(BeNil())
+			Expect(journal.Close()).To(BeNil())
+
+			txs, err := journal.Load()
+			Expect(err).To(BeNil())
+			Expect(txs).To(HaveLen(1))
+			Expect(txs[0].GetHash()).To(Equal(tx2.GetHash()))
+			Expect(txs[0].Local).To(BeTrue())
+
+			_, err = os.Stat(journal.path + ".tmp")
+			Expect(os.IsNotExist(err)).To(BeTrue())
+		})
+
+		It("should append new entries to the rotated journal", func() {
+			Expect(journal.Open()).To(BeNil())
+			Expect(journal.Rotate([]*JournalTx{{Transaction: tx2}})).To(BeNil())
+			Expect(journal.Insert(tx, false)).To(BeNil())
+			Expect(journal.Close()).To(BeNil())
+
+			txs, err := journal.Load()
+			Expect(err).To(BeNil())
+			Expect(txs).To(HaveLen(2))
+		})
+	})
+
+	Describe(".NeedsRotation", func() {
+		It("should return true when removed transactions outnumber live ones", func() {
+			Expect(journal.Open()).To(BeNil())
+			Expect(journal.Insert(tx, false)).To(BeNil())
+			Expect(journal.Insert(tx2, false)).To(BeNil())
+			Expect(journal.Remove(tx)).To(BeNil())
+			Expect(journal.NeedsRotation()).To(BeFalse())
+			Expect(journal.Remove(tx2)).To(BeNil())
+			Expect(journal.NeedsRotation()).To(BeTrue())
+		})
+	})
+
+	Context("when set on a pool", func() {
+
+		var tp *TxPool
+
+		BeforeEach(func() {
+			tp = New(10, nil)
+			Expect(journal.Open()).To(BeNil())
+			tp.SetJournal(journal)
+		})
+
+		It("should record transactions added to and removed from the pool", func() {
+			Expect(tp.Put(tx)).To(BeNil())
+			Expect(tp.Put(tx2)).To(BeNil())
+			tp.Remove(tx2)
+			Expect(journal.Close()).To(BeNil())
+
+			txs, err := journal.Load()
+			Expect(err).To(BeNil())
+			Expect(txs).To(HaveLen(1))
+			Expect(txs[0].GetHash()).To(Equal(tx.GetHash()))
+			Expect(txs[0].Local).To(BeFalse())
+		})
+
+		It("should rewrite the journal from the pool when removed transactions outnumber pooled ones", func() {
+			Expect(tp.Put(tx)).To(BeNil())
+			Expect(tp.Put(tx2)).To(BeNil())
+			tp.Remove(tx, tx2)
+			Expect(journal.NeedsRotation()).To(BeFalse())
+			Expect(journal.Close()).To(BeNil())
+
+			info, err := os.Stat(journal.path)
+			Expect(err).To(BeNil())
+			Expect(info.Size()).To(Equal(int64(0)))
+		})
+
+		It("should record whether a transaction was submitted locally", func() {
+			Expect(tp.PutLocal(tx2)).To(BeNil())
+			Expect(tp.Put(tx)).To(BeNil())
+			Expect(journal.Close()).To(BeNil())
+
+			txs, err := journal.Load()
+			Expect(err).To(BeNil())
+			Expect(txs).To(HaveLen(2))
+			Expect(txs[0].GetHash()).To(Equal(tx2.GetHash()))
+			Expect(txs[0].Local).To(BeTrue())
+			Expect(txs[1].Local).To(BeFalse())
+		})
+	})
+})
@@ -1,37 +1,178 @@
 package txpool
 
 import (
+	"sort"
 	"sync"
 	"time"
 
 	"github.com/ellcrys/elld/util"
+	"github.com/shopspring/decimal"
 
 	"github.com/ellcrys/elld/params"
 	"github.com/ellcrys/elld/types"
 	"github.com/ellcrys/elld/types/core"
 )
 
+// NonceGetter gets the current nonce of an account
+type NonceGetter interface {
+	GetAccountNonce(address util.String, opts ...types.CallOp) (uint64, error)
+}
+
 // TxPool stores transactions.
+//
+// Transactions are split into two sets. Pending
+// transactions can be included in the next block.
+// Queued transactions have a nonce that is ahead of
+// the next nonce of their sender. A queued transaction
+// is promoted to the pending set once the transactions
+// filling the nonce gap are added to the pool or
+// included in a block. Without a NonceGetter, all
+// transactions are pending.
+//
+// When the pool is full, the lowest fee rate transactions
+// are evicted to make room for higher paying ones.
+// Transactions submitted locally are never evicted.
 type TxPool struct {
-	sync.RWMutex              // general mutex
-	container    *TxContainer // transaction queue
+	sync.RWMutex                         // general mutex
+	container          *TxContainer      // pending transactions
+	queue              *TxContainer      // queued transactions
+	cap                int64             // max. number of pending and queued transactions
+	replaceFeeBump     int64             // min. fee rate increase (%) of a replacement
+	maxQueuedPerSender int               // max. number of queued transactions of a sender
+	nonceGetter        NonceGetter       // gets the current nonce of senders
+	journal            *Journal          // records pool changes for restarts
+	maxByteSize        int64             // max. total byte size of all transactions
+	spec               *params.ChainSpec // consensus parameters of the network
+
+	// locals contains the hashes of
+	// locally submitted transactions
+	locals map[string]struct{}
 }
 
 // New creates a new instance of TxPool.
 // Cap size is the max amount of transactions
-// that can be maintained in the pool.
-func New(cap int64) *TxPool {
+// that can be maintained in the pool. The main
+// network's chain specification is used if
+// spec is nil.
+func New(cap int64, spec *params.ChainSpec) *TxPool {
 	tp := new(TxPool)
+	tp.spec = spec
+	if tp.spec == nil {
+		tp.spec = params.DefaultChainSpec()
+	}
+	tp.cap = cap
 	tp.container = newTxContainer(cap)
+	tp.queue = newTxContainer(cap)
+	tp.replaceFeeBump = params.PoolReplaceFeeBump
+	tp.maxQueuedPerSender = params.PoolMaxQueuedPerSender
+	tp.maxByteSize = params.PoolMaxByteSize
+	tp.locals = make(map[string]struct{})
 	return tp
 }
 
-// Remove removes transactions
+// SetNonceGetter sets the object used to get the
+// current nonce of senders. It is required to
+// separate queued transactions from pending ones.
+func (tp *TxPool) SetNonceGetter(nonceGetter NonceGetter) {
+	tp.Lock()
+	defer tp.Unlock()
+	tp.nonceGetter = nonceGetter
+}
+
+// SetReplaceFeeBump sets the minimum percentage by
+// which the fee rate of a transaction must exceed the
+// fee rate of a pooled transaction with the same
+// sender and nonce in order to replace it.
+func (tp *TxPool) SetReplaceFeeBump(percent int64) {
+	tp.Lock()
+	defer tp.Unlock()
+	tp.replaceFeeBump = percent
+}
+
+// Remove removes transactions. Queued transactions
+// of the senders of the removed transactions are
+// promoted if their nonce gap has been filled.
 func (tp *TxPool) Remove(txs ...types.Transaction) {
 	tp.Lock()
 	defer tp.Unlock()
 	tp.container.Remove(txs...)
+	tp.queue.Remove(txs...)
+	tp.untrack(txs...)
 	tp.clean()
+
+	var senders = make(map[util.String]struct{})
+	for _, tx := range txs {
+		if _, ok := senders[tx.GetFrom()]; !ok {
+			senders[tx.GetFrom()] = struct{}{}
+			tp.promote(tx.GetFrom())
+		}
+	}
+
+	tp.compactJournal()
+}
+
+// SetJournal sets the journal where transactions
+// added to and removed from the pool are recorded
+func (tp *TxPool) SetJournal(journal *Journal) {
+	tp.Lock()
+	defer tp.Unlock()
+	tp.journal = journal
+}
+
+// journalInsert records the addition of a transaction
+// in the journal. Failing to record a transaction does
+// not prevent it from being pooled. (Not thread-safe)
+func (tp *TxPool) journalInsert(tx types.Transaction, local bool) {
+	if tp.journal != nil {
+		_ = tp.journal.Insert(tx, local)
+	}
+}
+
+// RotateJournal rewrites the journal so that it
+// records only the transactions currently pooled
+func (tp *TxPool) RotateJournal() error {
+	tp.Lock()
+	defer tp.Unlock()
+	return tp.rotateJournal()
+}
+
+// rotateJournal rewrites the journal from the
+// pooled transactions. (Not thread-safe)
+func (tp *TxPool) rotateJournal() error {
+	if tp.journal == nil {
+		return nil
+	}
+	var txs []*JournalTx
+	for _, container := range []*TxContainer{tp.container, tp.queue} {
+		container.IFind(func(tx types.Transaction) bool {
+			_, local := tp.locals[tx.GetHash().HexStr()]
+			txs = append(txs, &JournalTx{Transaction: tx, Local: local})
+			return false
+		})
+	}
+	return tp.journal.Rotate(txs)
+}
+
+// compactJournal rotates the journal when it records
+// more removed transactions than pooled ones. Failing
+// to rotate the journal does not affect the pool.
+// (Not thread-safe)
+func (tp *TxPool) compactJournal() {
+	if tp.journal != nil && tp.journal.NeedsRotation() {
+		_ = tp.rotateJournal()
+	}
+}
+
+// untrack records the removal of transactions in
+// the journal and forgets whether they were submitted
+// locally. (Not thread-safe)
+func (tp *TxPool) untrack(txs ...types.Transaction) {
+	for _, tx := range txs {
+		delete(tp.locals, tx.GetHash().HexStr())
+	}
+	if tp.journal != nil {
+		_ = tp.journal.Remove(txs...)
+	}
 }
 
 // Put adds a transaction
@@ -43,25 +184,66 @@ func (tp *TxPool) Put(tx types.Transaction) error {
 		return err
 	}
 
+	tp.journalInsert(tx, false)
 	tp.clean()
+	tp.compactJournal()
 
 	return nil
 }
 
-// isExpired checks whether a transaction has expired
+// PutLocal is like Put but marks the transaction
+// as locally submitted (e.g via RPC). Local
+// transactions are not evicted when the pool
+// is full.
+func (tp *TxPool) PutLocal(tx types.Transaction) error {
+	tp.Lock()
+	defer tp.Unlock()
+
+	if err := tp.addTx(tx); err != nil {
+		return err
+	}
+
+	tp.locals[tx.GetHash().HexStr()] = struct{}{}
+	tp.journalInsert(tx, true)
+	tp.clean()
+	tp.compactJournal()
+
+	return nil
+}
+
+// IsLocal checks whether a transaction
+// was submitted locally
+func (tp *TxPool) IsLocal(tx types.Transaction) bool {
+	tp.RLock()
+	defer tp.RUnlock()
+	_, ok := tp.locals[tx.GetHash().HexStr()]
+	return ok
+}
+
+// isExpired checks whether a transaction has expired.
+// The lifetime of a transaction locked until a unix
+// time starts at the lock time.
 func (tp *TxPool) isExpired(tx types.Transaction) bool {
-	expTime := time.Unix(tx.GetTimestamp(), 0).UTC().AddDate(0, 0, params.TxTTL)
+	start := tx.GetTimestamp()
+	if lockTime := tx.GetLockTime(); lockTime >= params.LockTimeThreshold &&
+		int64(lockTime) > start {
+		start = int64(lockTime)
+	}
+	expTime := time.Unix(start, 0).UTC().AddDate(0, 0, tp.spec.TxTTL)
 	return time.Now().UTC().After(expTime)
 }
 
 // clean removes old transactions
 func (tp *TxPool) clean() {
-	tp.container.IFind(func(tx types.Transaction) bool {
-		if tp.isExpired(tx) {
-			tp.container.remove(tx)
-		}
-		return false
-	})
+	for _, container := range []*TxContainer{tp.container, tp.queue} {
+		container.IFind(func(tx types.Transaction) bool {
+			if tp.isExpired(tx) {
+				container.remove(tx)
+				tp.untrack(tx)
+			}
+			return false
+		})
+	}
 }
 
 // addTx adds a transaction to the queue.
@@ -69,69 +251,348 @@ func (tp *TxPool) clean() {
 func (tp *TxPool) addTx(tx types.Transaction) error {
 
 	switch tx.GetType() {
-	case core.TxTypeBalance:
+	case core.TxTypeBalance, core.TxTypeMultiSigRegister, core.TxTypeMultiSigSpend,
+		core.TxTypeNameRegister, core.TxTypeNameRenew, core.TxTypeNameTransfer:
 	default:
 		return core.ErrTxTypeUnknown
 	}
 
 	// Ensure the transaction does not
 	// already exist in the queue
-	if tp.container.Has(tx) {
+	if tp.container.Has(tx) || tp.queue.Has(tx) {
 		return ErrTxAlreadyAdded
 	}
 
+	// A transaction with the same sender and nonce
+	// as a pooled transaction replaces it if it
+	// pays a sufficiently higher fee rate. The
+	// replacement takes the place of the existing
+	// transaction in the pending or queued set.
+	if existing, container := tp.findBySenderNonce(tx); existing != nil {
+		if !tp.canReplace(existing, tx) {
+			return ErrTxUnderpriced
+		}
+
+		// Make room for the replacement before the existing
+		// transaction is removed so that the existing
+		// transaction is kept if the replacement cannot fit
+		if !tp.makeRoom(tx, existing) {
+			return ErrContainerFull
+		}
+		container.Remove(existing)
+		if !container.Add(tx) {
+			container.Add(existing)
+			return ErrContainerFull
+		}
+		tp.untrack(existing)
+		return nil
+	}
+
+	pending, err := tp.isPending(tx)
+	if err != nil {
+		return err
+	}
+
+	// Add a transaction with a nonce gap
+	// to the queued transactions
+	if !pending {
+		if tp.countQueued(tx.GetFrom()) >= tp.maxQueuedPerSender {
+			return ErrSenderQueueFull
+		}
+		if !tp.makeRoom(tx, nil) || !tp.queue.Add(tx) {
+			return ErrContainerFull
+		}
+		return nil
+	}
+
 	// Append the the transaction to the
 	// the queue. This will cause the pool
 	// to be re-sorted
-	if !tp.container.Add(tx) {
+	if !tp.makeRoom(tx, nil) || !tp.container.Add(tx) {
 		return ErrContainerFull
 	}
 
+	// The transaction may have filled the
+	// nonce gap of queued transactions
+	tp.promote(tx.GetFrom())
+
 	return nil
 }
 
+// makeRoom evicts the lowest fee rate transactions of the
+// pool until tx can be added without exceeding the capacity
+// or the max. byte size of the pool. The capacity is shared
+// by the pending and queued transactions. Only transactions
+// paying a lower fee rate than tx are evicted. Local
+// transactions and transactions of the sender of tx are
+// never evicted. If tx replaces a pooled transaction, the
+// room taken by the replaced transaction is counted as free.
+// It returns false and evicts nothing if enough room cannot
+// be made. (Not thread-safe)
+func (tp *TxPool) makeRoom(tx, replaced types.Transaction) bool {
+
+	countNeeded := tp.Size() - tp.cap + 1
+	bytesNeeded := tp.ByteSize() + tx.GetSizeNoFee() - tp.maxByteSize
+	if replaced != nil {
+		countNeeded--
+		bytesNeeded -= replaced.GetSizeNoFee()
+	}
+	if countNeeded <= 0 && bytesNeeded <= 0 {
+		return true
+	}
+
+	type candidate struct {
+		tx        types.Transaction
+		feeRate   decimal.Decimal
+		container *TxContainer
+	}
+
+	// Collect the transactions that may be evicted
+	var candidates []candidate
+	var feeRate = CalcFeeRate(tx).Decimal()
+	for _, c := range []*TxContainer{tp.container, tp.queue} {
+		c.IFind(func(t types.Transaction) bool {
+			if _, ok := tp.locals[t.GetHash().HexStr()]; ok {
+				return false
+			}
+			if t.GetFrom().Equal(tx.GetFrom()) {
+				return false
+			}
+			if rate := CalcFeeRate(t).Decimal(); rate.LessThan(feeRate) {
+				candidates = append(candidates, candidate{t, rate, c})
+			}
+			return false
+		})
+	}
+
+	// Select the lowest paying candidates
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].feeRate.LessThan(candidates[j].feeRate)
+	})
+	var evict []candidate
+	for _, c := range candidates {
+		if countNeeded <= 0 && bytesNeeded <= 0 {
+			break
+		}
+		countNeeded--
+		bytesNeeded -= c.tx.GetSizeNoFee()
+		evict = append(evict, c)
+	}
+
+	if countNeeded > 0 || bytesNeeded > 0 {
+		return false
+	}
+
+	// An evicted pending transaction may have been
+	// demoted by the eviction of an earlier one
+	for _, c := range evict {
+		tp.container.Remove(c.tx)
+		tp.queue.Remove(c.tx)
+		tp.untrack(c.tx)
+		if c.container == tp.container {
+			tp.demote(c.tx.GetFrom(), c.tx.GetNonce())
+		}
+	}
+
+	return true
+}
+
+// demote moves the pending transactions of a sender with
+// a nonce greater than the given nonce to the queued
+// transactions. It is called when a pending transaction
+// is evicted, leaving a nonce gap. Transactions that do
+// not fit in the queue are dropped. (Not thread-safe)
+func (tp *TxPool) demote(sender util.String, nonce uint64) {
+	if tp.nonceGetter == nil {
+		return
+	}
+
+	var txs []types.Transaction
+	tp.container.IFind(func(tx types.Transaction) bool {
+		if tx.GetFrom().Equal(sender) && tx.GetNonce() > nonce {
+			txs = append(txs, tx)
+		}
+		return false
+	})
+
+	for _, tx := range txs {
+		tp.container.Remove(tx)
+		if tp.countQueued(sender) >= tp.maxQueuedPerSender || !tp.queue.Add(tx) {
+			tp.untrack(tx)
+		}
+	}
+}
+
+// nextNonce returns the nonce of the next transaction of
+// a sender that can be added to the pending transactions.
+// It is the nonce after the sender account's nonce and the
+// nonces of its consecutive pending transactions.
+// (Not thread-safe)
+func (tp *TxPool) nextNonce(sender util.String) (uint64, error) {
+
+	nonce, err := tp.nonceGetter.GetAccountNonce(sender)
+	if err != nil && err != core.ErrAccountNotFound {
+		return 0, err
+	}
+
+	var nonces = make(map[uint64]struct{})
+	tp.container.IFind(func(tx types.Transaction) bool {
+		if tx.GetFrom().Equal(sender) {
+			nonces[tx.GetNonce()] = struct{}{}
+		}
+		return false
+	})
+
+	next := nonce + 1
+	for {
+		if _, ok := nonces[next]; !ok {
+			return next, nil
+		}
+		next++
+	}
+}
+
+// isPending checks whether a transaction belongs to
+// the pending transactions. Transactions with a nonce
+// after the next nonce of the sender are queued.
+// (Not thread-safe)
+func (tp *TxPool) isPending(tx types.Transaction) (bool, error) {
+	if tp.nonceGetter == nil {
+		return true, nil
+	}
+
+	next, err := tp.nextNonce(tx.GetFrom())
+	if err != nil {
+		return false, err
+	}
+
+	return tx.GetNonce() <= next, nil
+}
+
+// promote moves queued transactions of a sender whose
+// nonce gap has been filled to the pending transactions.
+// (Not thread-safe)
+func (tp *TxPool) promote(sender util.String) {
+	if tp.nonceGetter == nil {
+		return
+	}
+
+	for {
+		next, err := tp.nextNonce(sender)
+		if err != nil {
+			return
+		}
+
+		tx := tp.queue.IFind(func(tx types.Transaction) bool {
+			return tx.GetFrom().Equal(sender) && tx.GetNonce() == next
+		})
+		if tx == nil {
+			return
+		}
+
+		tp.queue.Remove(tx)
+		if !tp.container.Add(tx) {
+			tp.queue.Add(tx)
+			return
+		}
+	}
+}
+
+// countQueued counts the queued transactions of a sender
+func (tp *TxPool) countQueued(sender util.String) int {
+	var count int
+	tp.queue.IFind(func(tx types.Transaction) bool {
+		if tx.GetFrom().Equal(sender) {
+			count++
+		}
+		return false
+	})
+	return count
+}
+
+// findBySenderNonce finds a pooled transaction with the
+// same sender and nonce as tx and the container it is
+// in. Transactions without a sender address are not
+// matched.
+func (tp *TxPool) findBySenderNonce(tx types.Transaction) (types.Transaction, *TxContainer) {
+	if tx.GetFrom() == "" {
+		return nil, nil
+	}
+	for _, container := range []*TxContainer{tp.container, tp.queue} {
+		existing := container.IFind(func(t types.Transaction) bool {
+			return t.GetFrom().Equal(tx.GetFrom()) && t.GetNonce() == tx.GetNonce()
+		})
+		if existing != nil {
+			return existing, container
+		}
+	}
+	return nil, nil
+}
+
+// canReplace checks whether the fee rate of
+// replacement exceeds the fee rate of existing
+// by at least the replace fee bump percentage
+func (tp *TxPool) canReplace(existing, replacement types.Transaction) bool {
+	oldRate := CalcFeeRate(existing).Decimal()
+	newRate := CalcFeeRate(replacement).Decimal()
+	bump := decimal.New(100+tp.replaceFeeBump, -2)
+	return newRate.GreaterThan(oldRate) &&
+		!newRate.LessThan(oldRate.Mul(bump))
+}
+
 // Has checks whether a transaction is in the pool
 func (tp *TxPool) Has(tx types.Transaction) bool {
-	return tp.container.Has(tx)
+	return tp.container.Has(tx) || tp.queue.Has(tx)
 }
 
 // HasByHash is like Has but accepts a hash
 func (tp *TxPool) HasByHash(hash string) bool {
-	return tp.container.HasByHash(hash)
+	return tp.container.HasByHash(hash) || tp.queue.HasByHash(hash)
 }
 
-// Container gets the underlying
-// transaction container
+// Container gets the underlying container
+// of the pending transactions
 func (tp *TxPool) Container() types.TxContainer {
 	return tp.container
 }
 
+// QueueContainer gets the underlying
+// container of the queued transactions
+func (tp *TxPool) QueueContainer() types.TxContainer {
+	return tp.queue
+}
+
 // ByteSize gets the total byte size of
 // all transactions in the pool
 func (tp *TxPool) ByteSize() int64 {
-	return tp.container.ByteSize()
+	return tp.container.ByteSize() + tp.queue.ByteSize()
 }
 
 // Size gets the total number of transactions
 // in the pool
 func (tp *TxPool) Size() int64 {
-	return tp.container.Size()
+	return tp.container.Size() + tp.queue.Size()
 }
 
 // GetByHash gets a transaction from the pool using its hash
 func (tp *TxPool) GetByHash(hash string) types.Transaction {
-	return tp.container.GetByHash(hash)
+	if tx := tp.container.GetByHash(hash); tx != nil {
+		return tx
+	}
+	return tp.queue.GetByHash(hash)
 }
 
 // GetByFrom fetches transactions where the sender
 // or `from` field match the given address
 func (tp *TxPool) GetByFrom(address util.String) []types.Transaction {
 	var txs []types.Transaction
-	tp.container.IFind(func(tx types.Transaction) bool {
-		if tx.GetFrom().Equal(address) {
-			txs = append(txs, tx)
-		}
-		return false
-	})
+	for _, container := range []*TxContainer{tp.container, tp.queue} {
+		container.IFind(func(tx types.Transaction) bool {
+			if tx.GetFrom().Equal(address) {
+				txs = append(txs, tx)
+			}
+			return false
+		})
+	}
 	return txs
 }
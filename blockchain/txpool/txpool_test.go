@@ -13,11 +13,19 @@ import (
 	. "github.com/onsi/gomega"
 )
 
+type testNonceGetter struct {
+	nonce uint64
+}
+
+func (g *testNonceGetter) GetAccountNonce(address util.String, opts ...types.CallOp) (uint64, error) {
+	return g.nonce, nil
+}
+
 var _ = Describe("TxPool", func() {
 
 	Describe(".Put", func() {
 		It("should return err = 'capacity reached' when txpool capacity is reached", func() {
-			tp := New(0)
+			tp := New(0, nil)
 			a, _ := crypto.NewKey(nil)
 			tx := core.NewTransaction(core.TxTypeBalance, 1, "something", util.String(a.PubKey().Base58()), "0", "0", time.Now().Unix())
 			err := tp.Put(tx)
@@ -26,7 +34,7 @@ var _ = Describe("TxPool", func() {
 		})
 
 		It("should return err = 'exact transaction already in the pool' when transaction has already been added", func() {
-			tp := New(10)
+			tp := New(10, nil)
 			a, _ := crypto.NewKey(nil)
 			tx := core.NewTransaction(core.TxTypeBalance, 1, "something", util.String(a.PubKey().Base58()), "0", "0", time.Now().Unix())
 			sig, _ := core.TxSign(tx, a.PrivKey().Base58())
@@ -38,7 +46,7 @@ var _ = Describe("TxPool", func() {
 		})
 
 		It("should return err = 'unknown transaction type' when tx type is unknown", func() {
-			tp := New(1)
+			tp := New(1, nil)
 			a, _ := crypto.NewKey(nil)
 			tx := core.NewTransaction(10200, 1, "something", util.String(a.PubKey().Base58()), "0", "0", time.Now().Unix())
 			sig, _ := core.TxSign(tx, a.PrivKey().Base58())
@@ -49,7 +57,7 @@ var _ = Describe("TxPool", func() {
 		})
 
 		It("should return nil and added to queue", func() {
-			tp := New(1)
+			tp := New(1, nil)
 			a, _ := crypto.NewKey(nil)
 			tx := core.NewTransaction(core.TxTypeBalance, 1, "something", util.String(a.PubKey().Base58()), "0", "0", time.Now().Unix())
 			sig, _ := core.TxSign(tx, a.PrivKey().Base58())
@@ -60,12 +68,183 @@ var _ = Describe("TxPool", func() {
 		})
 	})
 
+	Describe(".Put (replacement)", func() {
+
+		var tp *TxPool
+		var key = crypto.NewKeyFromIntSeed(1)
+		var tx types.Transaction
+
+		BeforeEach(func() {
+			tp = New(1, nil)
+			tx = core.NewTx(core.TxTypeBalance, 1, "a", key, "1", "0.1", time.Now().Unix())
+			Expect(tp.Put(tx)).To(BeNil())
+		})
+
+		It("should replace a transaction with the same sender and nonce when the fee rate is sufficiently higher", func() {
+			tx2 := core.NewTx(core.TxTypeBalance, 1, "a", key, "1", "0.2", time.Now().Unix())
+			Expect(tp.Put(tx2)).To(BeNil())
+			Expect(tp.Size()).To(Equal(int64(1)))
+			Expect(tp.Has(tx)).To(BeFalse())
+			Expect(tp.Has(tx2)).To(BeTrue())
+		})
+
+		It("should return ErrTxUnderpriced when the fee rate is not sufficiently higher", func() {
+			tx2 := core.NewTx(core.TxTypeBalance, 1, "a", key, "1", "0.105", time.Now().Unix())
+			Expect(tp.Put(tx2)).To(Equal(ErrTxUnderpriced))
+			Expect(tp.Has(tx)).To(BeTrue())
+		})
+
+		It("should use the configured replace fee bump", func() {
+			tp.SetReplaceFeeBump(0)
+			tx2 := core.NewTx(core.TxTypeBalance, 1, "a", key, "1", "0.105", time.Now().Unix())
+			Expect(tp.Put(tx2)).To(BeNil())
+			Expect(tp.Has(tx2)).To(BeTrue())
+		})
+
+		It("should keep the existing transaction when the replacement exceeds the max. byte size", func() {
+			tp.maxByteSize = tp.ByteSize()
+			tx2 := core.NewTx(core.TxTypeBalance, 1, "a_longer_recipient", key, "1", "0.2", time.Now().Unix())
+			Expect(tx2.GetSizeNoFee()).To(BeNumerically(">", tx.GetSizeNoFee()))
+			Expect(tp.Put(tx2)).To(Equal(ErrContainerFull))
+			Expect(tp.Has(tx)).To(BeTrue())
+			Expect(tp.Has(tx2)).To(BeFalse())
+		})
+	})
+
+	Describe(".Put (queue)", func() {
+
+		var tp *TxPool
+		var key = crypto.NewKeyFromIntSeed(1)
+
+		BeforeEach(func() {
+			tp = New(100, nil)
+			tp.SetNonceGetter(&testNonceGetter{nonce: 0})
+		})
+
+		It("should add a transaction with the next nonce to the pending transactions", func() {
+			tx := core.NewTx(core.TxTypeBalance, 1, "a", key, "1", "0.1", time.Now().Unix())
+			Expect(tp.Put(tx)).To(BeNil())
+			Expect(tp.container.Has(tx)).To(BeTrue())
+			Expect(tp.queue.Size()).To(Equal(int64(0)))
+		})
+
+		It("should add a transaction with a nonce gap to the queued transactions", func() {
+			tx := core.NewTx(core.TxTypeBalance, 3, "a", key, "1", "0.1", time.Now().Unix())
+			Expect(tp.Put(tx)).To(BeNil())
+			Expect(tp.queue.Has(tx)).To(BeTrue())
+			Expect(tp.container.Size()).To(Equal(int64(0)))
+			Expect(tp.Size()).To(Equal(int64(1)))
+			Expect(tp.Has(tx)).To(BeTrue())
+		})
+
+		It("should promote queued transactions when the nonce gap is filled", func() {
+			tx3 := core.NewTx(core.TxTypeBalance, 3, "a", key, "1", "0.1", time.Now().Unix())
+			tx2 := core.NewTx(core.TxTypeBalance, 2, "a", key, "1", "0.1", time.Now().Unix())
+			tx1 := core.NewTx(core.TxTypeBalance, 1, "a", key, "1", "0.1", time.Now().Unix())
+			Expect(tp.Put(tx3)).To(BeNil())
+			Expect(tp.Put(tx2)).To(BeNil())
+			Expect(tp.queue.Size()).To(Equal(int64(2)))
+			Expect(tp.Put(tx1)).To(BeNil())
+			Expect(tp.queue.Size()).To(Equal(int64(0)))
+			Expect(tp.container.Size()).To(Equal(int64(3)))
+		})
+
+		It("should return error when the sender has too many queued transactions", func() {
+			tp.maxQueuedPerSender = 1
+			tx := core.NewTx(core.TxTypeBalance, 3, "a", key, "1", "0.1", time.Now().Unix())
+			Expect(tp.Put(tx)).To(BeNil())
+			tx2 := core.NewTx(core.TxTypeBalance, 4, "a", key, "1", "0.1", time.Now().Unix())
+			Expect(tp.Put(tx2)).To(Equal(ErrSenderQueueFull))
+		})
+
+		It("should promote queued transactions when the account nonce catches up", func() {
+			tx := core.NewTx(core.TxTypeBalance, 2, "a", key, "1", "0.1", time.Now().Unix())
+			Expect(tp.Put(tx)).To(BeNil())
+			Expect(tp.queue.Has(tx)).To(BeTrue())
+
+			tx1 := core.NewTx(core.TxTypeBalance, 1, "a", key, "1", "0.1", time.Now().Unix())
+			tp.nonceGetter = &testNonceGetter{nonce: 1}
+			tp.Remove(tx1)
+			Expect(tp.container.Has(tx)).To(BeTrue())
+			Expect(tp.queue.Size()).To(Equal(int64(0)))
+		})
+	})
+
+	Describe(".Put (eviction)", func() {
+
+		var tp *TxPool
+		var key = crypto.NewKeyFromIntSeed(1)
+		var key2 = crypto.NewKeyFromIntSeed(2)
+		var key3 = crypto.NewKeyFromIntSeed(3)
+		var tx types.Transaction
+
+		BeforeEach(func() {
+			tp = New(1, nil)
+			tx = core.NewTx(core.TxTypeBalance, 1, "a", key, "1", "0.1", time.Now().Unix())
+		})
+
+		It("should evict a lower fee rate transaction when the pool is full", func() {
+			Expect(tp.Put(tx)).To(BeNil())
+			tx2 := core.NewTx(core.TxTypeBalance, 1, "a", key2, "1", "0.2", time.Now().Unix())
+			Expect(tp.Put(tx2)).To(BeNil())
+			Expect(tp.Has(tx)).To(BeFalse())
+			Expect(tp.Has(tx2)).To(BeTrue())
+		})
+
+		It("should return error if the pooled transactions pay a higher fee rate", func() {
+			Expect(tp.Put(tx)).To(BeNil())
+			tx2 := core.NewTx(core.TxTypeBalance, 1, "a", key2, "1", "0.05", time.Now().Unix())
+			Expect(tp.Put(tx2)).To(Equal(ErrContainerFull))
+			Expect(tp.Has(tx)).To(BeTrue())
+		})
+
+		It("should not evict a local transaction", func() {
+			Expect(tp.PutLocal(tx)).To(BeNil())
+			Expect(tp.IsLocal(tx)).To(BeTrue())
+			tx2 := core.NewTx(core.TxTypeBalance, 1, "a", key2, "1", "0.2", time.Now().Unix())
+			Expect(tp.Put(tx2)).To(Equal(ErrContainerFull))
+			Expect(tp.Has(tx)).To(BeTrue())
+		})
+
+		It("should share the capacity between pending and queued transactions", func() {
+			tp.SetNonceGetter(&testNonceGetter{nonce: 0})
+			queued := core.NewTx(core.TxTypeBalance, 3, "a", key2, "1", "0.2", time.Now().Unix())
+			Expect(tp.Put(queued)).To(BeNil())
+			Expect(tp.queue.Has(queued)).To(BeTrue())
+			Expect(tp.Put(tx)).To(Equal(ErrContainerFull))
+			Expect(tp.Size()).To(Equal(int64(1)))
+		})
+
+		It("should evict a queued transaction to make room for a higher paying pending transaction", func() {
+			tp.SetNonceGetter(&testNonceGetter{nonce: 0})
+			queued := core.NewTx(core.TxTypeBalance, 3, "a", key2, "1", "0.05", time.Now().Unix())
+			Expect(tp.Put(queued)).To(BeNil())
+			Expect(tp.Put(tx)).To(BeNil())
+			Expect(tp.Has(queued)).To(BeFalse())
+			Expect(tp.container.Has(tx)).To(BeTrue())
+		})
+
+		It("should evict the lowest fee rate transactions when the max. byte size is exceeded", func() {
+			tp = New(10, nil)
+			tx2 := core.NewTx(core.TxTypeBalance, 1, "a", key2, "1", "0.2", time.Now().Unix())
+			Expect(tp.Put(tx)).To(BeNil())
+			Expect(tp.Put(tx2)).To(BeNil())
+			tp.maxByteSize = tp.ByteSize()
+
+			tx3 := core.NewTx(core.TxTypeBalance, 1, "a", key3, "1", "0.3", time.Now().Unix())
+			Expect(tp.Put(tx3)).To(BeNil())
+			Expect(tp.Has(tx)).To(BeFalse())
+			Expect(tp.Has(tx2)).To(BeTrue())
+			Expect(tp.Has(tx3)).To(BeTrue())
+		})
+	})
+
 	Describe(".Has", func() {
 
 		var tp *TxPool
 
 		BeforeEach(func() {
-			tp = New(1)
+			tp = New(1, nil)
 		})
 
 		It("should return true when tx exist", func() {
@@ -88,7 +267,7 @@ var _ = Describe("TxPool", func() {
 		var tx, tx2, tx3 types.Transaction
 
 		BeforeEach(func() {
-			tp = New(3)
+			tp = New(3, nil)
 			tx = core.NewTx(core.TxTypeBalance, 1, "a", key1, "12.2", "0.2", time.Now().Unix())
 			tx2 = core.NewTx(core.TxTypeBalance, 2, "a", key1, "12.3", "0.2", time.Now().Unix())
 			tx3 = core.NewTx(core.TxTypeBalance, 2, "a", key2, "12.3", "0.2", time.Now().Unix())
@@ -111,7 +290,7 @@ var _ = Describe("TxPool", func() {
 		var tp *TxPool
 
 		BeforeEach(func() {
-			tp = New(1)
+			tp = New(1, nil)
 			Expect(tp.Size()).To(Equal(int64(0)))
 		})
 
@@ -128,7 +307,7 @@ var _ = Describe("TxPool", func() {
 		var tp *TxPool
 
 		BeforeEach(func() {
-			tp = New(2)
+			tp = New(2, nil)
 		})
 
 		BeforeEach(func() {
@@ -176,8 +355,9 @@ var _ = Describe("TxPool", func() {
 		Context("when TxTTL is 1 day", func() {
 
 			BeforeEach(func() {
-				params.TxTTL = 1
-				tp = New(2)
+				spec := params.DefaultChainSpec()
+				spec.TxTTL = 1
+				tp = New(2, spec)
 
 				tx = core.NewTransaction(core.TxTypeBalance, 100, "something", util.String("abc"), "0", "0", time.Now().Unix())
 				tx.SetHash(util.StrToHash("hash1"))
@@ -198,6 +378,12 @@ var _ = Describe("TxPool", func() {
 				Expect(tp.Has(tx2)).To(BeTrue())
 				Expect(tp.Has(tx)).To(BeFalse())
 			})
+
+			It("should not remove an old transaction locked until a recent time", func() {
+				tx.(*core.Transaction).LockTime = uint64(time.Now().Unix())
+				tp.clean()
+				Expect(tp.Size()).To(Equal(int64(2)))
+			})
 		})
 	})
 
@@ -207,7 +393,7 @@ var _ = Describe("TxPool", func() {
 		var tx, tx2, tx3 types.Transaction
 
 		BeforeEach(func() {
-			tp = New(100)
+			tp = New(100, nil)
 
 			tx = core.NewTransaction(core.TxTypeBalance, 100, "something", util.String("abc"), "0", "0", time.Now().Unix())
 			tx.SetHash(tx.ComputeHash())
@@ -236,7 +422,7 @@ var _ = Describe("TxPool", func() {
 		var tx, tx2 types.Transaction
 
 		BeforeEach(func() {
-			tp = New(100)
+			tp = New(100, nil)
 
 			tx = core.NewTransaction(core.TxTypeBalance, 100, "something", util.String("abc"), "0", "0", time.Now().Unix())
 			tx.SetHash(tx.ComputeHash())
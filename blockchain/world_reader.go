@@ -105,3 +105,63 @@ func (r *WorldReader) GetAccount(chain types.Chainer, address util.String,
 
 	return result, nil
 }
+
+// GetName gets a name record by the given
+// name in the chain provided.
+func (r *WorldReader) GetName(chain types.Chainer, name util.String,
+	opts ...types.CallOp) (*types.NameRecord, error) {
+	r.bchain.chainLock.RLock()
+	defer r.bchain.chainLock.RUnlock()
+
+	// If a start chain is not given,
+	// We will use whatever the main chain
+	if chain == nil {
+		if r.bchain.bestChain == nil {
+			return nil, core.ErrBestChainUnknown
+		}
+		return r.bchain.bestChain.GetName(name, opts...)
+	}
+
+	var result *types.NameRecord
+	var err error
+
+	// maxChainHeight is the maximum block number of
+	// the name records to consider in the current chain.
+	// See GetAccount.
+	maxChainHeight := uint64(0)
+
+	// Transverse the chain and its ancestors.
+	err = r.bchain.NewChainTraverser().Start(chain).Query(func(ch types.Chainer) (bool, error) {
+		optsCopy := append([]types.CallOp{}, opts...)
+		if maxChainHeight > 0 {
+			optsCopy = append(optsCopy, &common.OpBlockQueryRange{Max: maxChainHeight})
+		}
+
+		result, err = ch.GetName(name, optsCopy...)
+		if err != nil {
+			if err != core.ErrNameNotFound {
+				return false, err
+			}
+		}
+
+		if result == nil {
+			chInfo := ch.GetInfo()
+			if chInfo.GetParentBlockNumber() > 0 {
+				maxChainHeight = chInfo.GetParentBlockNumber()
+			}
+			return false, nil
+		}
+
+		return true, nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	if result == nil {
+		return nil, core.ErrNameNotFound
+	}
+
+	return result, nil
+}
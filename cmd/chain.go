@@ -0,0 +1,177 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path"
+
+	"github.com/ellcrys/elld/blockchain"
+	"github.com/ellcrys/elld/blockchain/txpool"
+	"github.com/ellcrys/elld/crypto"
+	"github.com/ellcrys/elld/elldb"
+	"github.com/ellcrys/elld/miner/consensus"
+	"github.com/ellcrys/elld/params"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+// loadChainSpec reads the chain specification
+// stored in the network data directory
+func loadChainSpec() (*params.ChainSpec, error) {
+	return params.LoadChainSpec(path.Join(cfg.NetDataDir(), params.ChainSpecFileName))
+}
+
+// loadBlockchain opens the local database and
+// loads the blockchain manager without starting
+// the node. The caller must close the database.
+func loadBlockchain() (*blockchain.Blockchain, elldb.DB, error) {
+
+	spec, err := loadChainSpec()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to load chain specification: %s", err)
+	}
+
+	engine, err := consensus.New(spec, cfg, log)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create consensus engine: %s", err)
+	}
+
+	db := elldb.NewDB(cfg.NetDataDir())
+	if err := db.Open(""); err != nil {
+		return nil, nil, fmt.Errorf("failed to open local database: %s", err)
+	}
+
+	bChain := blockchain.New(txpool.New(params.PoolCapacity, spec), spec, engine, cfg, log)
+	bChain.SetDB(db)
+	bChain.SetCoinbase(crypto.NewKeyFromIntSeed(0))
+	if err := bChain.Up(); err != nil {
+		db.Close()
+		return nil, nil, fmt.Errorf("failed to load blockchain manager: %s", err)
+	}
+
+	return bChain, db, nil
+}
+
+// chainCmd represents the chain command
+var chainCmd = &cobra.Command{
+	Use:   "chain command [flags]",
+	Short: "Export and import the blockchain",
+	Long: `Description:
+  This command provides the ability to export blocks of the main chain
+  to a file and to import blocks from a file. It can be used to bootstrap
+  a node without synchronizing with peers.
+
+  The node must not be running while these commands are executed.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return cmd.Help()
+	},
+}
+
+// exportBlocks writes blocks of the main chain
+// within the given range to the file at path.
+// It returns the number of exported blocks.
+func exportBlocks(path string, from, to uint64) (int, error) {
+
+	bChain, db, err := loadBlockchain()
+	if err != nil {
+		return 0, err
+	}
+	defer db.Close()
+
+	file, err := os.Create(path)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create file: %s", err)
+	}
+	defer file.Close()
+
+	n, err := bChain.Export(file, from, to)
+	if err != nil {
+		return n, fmt.Errorf("failed to export blocks: %s", err)
+	}
+
+	return n, nil
+}
+
+// importBlocks processes the blocks in the file
+// at path. It returns the number of imported blocks.
+func importBlocks(path string) (int, error) {
+
+	bChain, db, err := loadBlockchain()
+	if err != nil {
+		return 0, err
+	}
+	defer db.Close()
+
+	file, err := os.Open(path)
+	if err != nil {
+		return 0, fmt.Errorf("failed to open file: %s", err)
+	}
+	defer file.Close()
+
+	n, err := bChain.Import(file)
+	if err != nil {
+		return n, fmt.Errorf("failed to import blocks: %s", err)
+	}
+
+	return n, nil
+}
+
+var chainExportCmd = &cobra.Command{
+	Use:   "export [flags] <file>",
+	Short: "Export blocks of the main chain to a file",
+	Long: `Description:
+  This command writes blocks of the main chain to a file. Use '--from' and
+  '--to' to set the range of blocks to export. All blocks up to the tip of
+  the main chain are exported if '--to' is not provided.
+`,
+	Run: func(cmd *cobra.Command, args []string) {
+
+		if len(args) == 0 {
+			log.Fatal("File path is required")
+		}
+
+		from, _ := cmd.Flags().GetUint64("from")
+		to, _ := cmd.Flags().GetUint64("to")
+
+		// The database is closed before
+		// exiting on error
+		n, err := exportBlocks(args[0], from, to)
+		if err != nil {
+			log.Fatal(err.Error())
+		}
+
+		fmt.Println("Exported", color.CyanString("%d", n), "block(s)")
+	},
+}
+
+var chainImportCmd = &cobra.Command{
+	Use:   "import [flags] <file>",
+	Short: "Import blocks from a file",
+	Long: `Description:
+  This command reads blocks from a file created by the 'export' command
+  and processes them. Blocks that are already known are skipped.
+`,
+	Run: func(cmd *cobra.Command, args []string) {
+
+		if len(args) == 0 {
+			log.Fatal("File path is required")
+		}
+
+		// The database is closed before
+		// exiting on error
+		n, err := importBlocks(args[0])
+		if err != nil {
+			log.Fatal(err.Error(), "NumImported", n)
+		}
+
+		fmt.Println("Imported", color.CyanString("%d", n), "block(s)")
+	},
+}
+
+func init() {
+	chainCmd.AddCommand(chainExportCmd)
+	chainCmd.AddCommand(chainImportCmd)
+	chainExportCmd.Flags().Uint64("from", 1, "The number of the first block to export")
+	chainExportCmd.Flags().Uint64("to", 0, "The number of the last block to export (Default: tip block)")
+	rootCmd.AddCommand(chainCmd)
+}
@@ -69,6 +69,7 @@ var consoleCmd = &cobra.Command{
 				miner.Stop()
 			}
 			rpcServer.Stop()
+			stopServices()
 			node.Stop()
 		})
 
@@ -91,4 +92,6 @@ func init() {
 	consoleCmd.Flags().Int("miners", 0, "The number of miner threads to use. (Default: Number of CPU)")
 	consoleCmd.Flags().Bool("no-net", false, "Closes the network host and prevents (in/out) connections")
 	consoleCmd.Flags().Bool("sync-disabled", false, "Disable block and transaction synchronization")
+	consoleCmd.Flags().Bool("pruned", false, "Periodically remove old account versions (default: archive mode)")
+	consoleCmd.Flags().Bool("index-address-txs", false, "Index transactions by sender and recipient address")
 }
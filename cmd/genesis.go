@@ -0,0 +1,103 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	"github.com/ellcrys/elld/blockchain"
+	"github.com/ellcrys/elld/elldb"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+// genesisCmd represents the genesis command
+var genesisCmd = &cobra.Command{
+	Use:   "genesis command [flags]",
+	Short: "Create genesis blocks for custom networks",
+	Long: `Description:
+  This command provides the ability to create the genesis block of
+  a custom network (e.g a private consortium network).`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return cmd.Help()
+	},
+}
+
+var genesisNewCmd = &cobra.Command{
+	Use:   "new [flags] <spec file>",
+	Short: "Create a signed genesis block from a spec file",
+	Long: `Description:
+  This command reads a JSON spec describing a genesis block and writes the
+  signed genesis block to a file in the format of the default genesis block.
+
+  Example spec:
+  {
+      "creatorKey": "<base58 encoded private key>",
+      "difficulty": 50000000,
+      "timestamp": 1547485409,
+      "allocs": [
+          { "address": "eGFi9YA6CGbMJMw5kMntfbetSntTFTUCxp", "balance": "5000000" }
+      ]
+  }
+
+  The difficulty defaults to the genesis difficulty of the network's chain
+  specification and the timestamp defaults to the current time.
+`,
+	Run: func(cmd *cobra.Command, args []string) {
+
+		if len(args) == 0 {
+			log.Fatal("Spec file path is required")
+		}
+
+		out, _ := cmd.Flags().GetString("out")
+
+		data, err := ioutil.ReadFile(args[0])
+		if err != nil {
+			log.Fatal("Failed to read spec file", "Err", err.Error())
+		}
+
+		var spec blockchain.GenesisSpec
+		if err := json.Unmarshal(data, &spec); err != nil {
+			log.Fatal("Failed to decode spec file", "Err", err.Error())
+		}
+
+		chainSpec, err := loadChainSpec()
+		if err != nil {
+			log.Fatal("Failed to load chain specification", "Err", err.Error())
+		}
+
+		// The allocations are executed against
+		// a temporary database
+		dbDir, err := ioutil.TempDir("", "elld_genesis")
+		if err != nil {
+			log.Fatal("Failed to create temporary directory", "Err", err.Error())
+		}
+		defer os.RemoveAll(dbDir)
+
+		db := elldb.NewDB(dbDir)
+		if err := db.Open(""); err != nil {
+			log.Fatal("Failed to open temporary database", "Err", err.Error())
+		}
+		defer db.Close()
+
+		block, err := blockchain.GenerateGenesisBlock(&spec, chainSpec, db, cfg, log)
+		if err != nil {
+			log.Fatal("Failed to create genesis block", "Err", err.Error())
+		}
+
+		bs, _ := json.MarshalIndent(block, "", "    ")
+		if err := ioutil.WriteFile(out, bs, 0644); err != nil {
+			log.Fatal("Failed to write genesis file", "Err", err.Error())
+		}
+
+		fmt.Println("Created genesis block", color.CyanString(block.GetHashAsHex()))
+		fmt.Println("Written to", color.CyanString(out))
+	},
+}
+
+func init() {
+	genesisCmd.AddCommand(genesisNewCmd)
+	rootCmd.AddCommand(genesisCmd)
+	genesisNewCmd.Flags().StringP("out", "o", "genesis.json", "The file to write the genesis block to")
+}
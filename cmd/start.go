@@ -4,8 +4,10 @@ import (
 	"fmt"
 	"io/ioutil"
 	"os"
+	"path"
 	"strconv"
 	"strings"
+	"sync"
 
 	"github.com/spf13/viper"
 
@@ -17,6 +19,8 @@ import (
 
 	"github.com/ellcrys/elld/blockchain"
 	"github.com/ellcrys/elld/miner"
+	"github.com/ellcrys/elld/miner/consensus"
+	"github.com/ellcrys/elld/miner/instantseal"
 	"github.com/ellcrys/elld/rpc"
 
 	"gopkg.in/asaskevich/govalidator.v4"
@@ -32,6 +36,32 @@ import (
 	"github.com/spf13/cobra"
 )
 
+var (
+	// stopFuncs are called to stop the services that
+	// were started along with the node (e.g the pool
+	// journal) when the node is shutting down
+	stopFuncs    []func()
+	stopFuncsMtx = &sync.Mutex{}
+)
+
+// addStopFunc registers a function to be
+// called when the node is shutting down
+func addStopFunc(f func()) {
+	stopFuncsMtx.Lock()
+	defer stopFuncsMtx.Unlock()
+	stopFuncs = append(stopFuncs, f)
+}
+
+// stopServices calls the registered stop functions
+func stopServices() {
+	stopFuncsMtx.Lock()
+	defer stopFuncsMtx.Unlock()
+	for _, f := range stopFuncs {
+		f()
+	}
+	stopFuncs = nil
+}
+
 // getKey unlocks an account and returns the corresponding key.
 func getKey(accountID, password string, seed int64) (*crypto.Key, error) {
 
@@ -145,6 +175,8 @@ func start(cmd *cobra.Command, args []string, startConsole bool) (*node.Node, *r
 	viper.BindPFlag("miner.numMiners", cmd.Flags().Lookup("miners"))
 	viper.BindPFlag("node.noNet", cmd.Flags().Lookup("no-net"))
 	viper.BindPFlag("node.syncDisabled", cmd.Flags().Lookup("sync-disabled"))
+	viper.BindPFlag("chain.pruned", cmd.Flags().Lookup("pruned"))
+	viper.BindPFlag("chain.indexAddressTxs", cmd.Flags().Lookup("index-address-txs"))
 	account := viper.GetString("node.account")
 	password := viper.GetString("node.password")
 	listeningAddr := viper.GetString("node.address")
@@ -212,8 +244,23 @@ func start(cmd *cobra.Command, args []string, startConsole bool) (*node.Node, *r
 		n.DisableNetwork()
 	}
 
+	// Load the chain specification of the network
+	spec, err := loadChainSpec()
+	if err != nil {
+		log.Fatal("Failed to load chain specification", "Err", err.Error())
+	}
+
+	// Create the consensus engine selected by the chain specification
+	engine, err := consensus.New(spec, cfg, log)
+	if err != nil {
+		log.Fatal("Failed to create consensus engine", "Err", err.Error())
+	}
+
 	// Configure transactions pool and assign to node
-	pool := txpool.New(params.PoolCapacity)
+	pool := txpool.New(params.PoolCapacity, spec)
+	if cfg.TxPool != nil {
+		pool.SetReplaceFeeBump(cfg.TxPool.ReplaceFeeBump)
+	}
 	n.SetTxsPool(pool)
 
 	if !noNet {
@@ -237,10 +284,18 @@ func start(cmd *cobra.Command, args []string, startConsole bool) (*node.Node, *r
 
 	// Initialize and set the blockchain manager's db,
 	// event emitter and pass it to the engine
-	bChain := blockchain.New(n.GetTxPool(), cfg, log)
+	bChain := blockchain.New(n.GetTxPool(), spec, engine, cfg, log)
 	bChain.SetDB(n.DB())
 	bChain.SetEventEmitter(event)
 	bChain.SetCoinbase(coinbase)
+	pool.SetNonceGetter(bChain)
+
+	// Networks using the instant-seal engine create
+	// a block as soon as a transaction is pooled
+	var sealer *miner.InstantSealer
+	if spec.ConsensusEngine == instantseal.Name {
+		sealer = miner.NewInstantSealer(coinbase, bChain, event, log)
+	}
 
 	// Initialize the miner, rpc server
 	miner := miner.NewMiner(coinbase, bChain, event, cfg, log)
@@ -266,12 +321,72 @@ func start(cmd *cobra.Command, args []string, startConsole bool) (*node.Node, *r
 		log.Fatal("failed to load blockchain manager", "Err", err.Error())
 	}
 
+	// Restore the transactions recorded in the pool
+	// journal. Each transaction is revalidated against
+	// the current chain before it is added back.
+	journal := txpool.NewJournal(path.Join(cfg.NetDataDir(), params.PoolJournalFileName))
+	journalTxs, err := journal.Load()
+	if err != nil {
+		log.Fatal("failed to read transaction pool journal", "Err", err.Error())
+	}
+	if err := journal.Open(); err != nil {
+		log.Fatal("failed to open transaction pool journal", "Err", err.Error())
+	}
+	pool.SetJournal(journal)
+	addStopFunc(func() {
+		if err := journal.Close(); err != nil {
+			log.Error("Failed to close transaction pool journal", "Err", err.Error())
+		}
+	})
+	var numRestored int
+	for _, jtx := range journalTxs {
+		addTx := tm.AddTx
+		if jtx.Local {
+			addTx = tm.AddLocalTx
+		}
+		if addTx(jtx.Transaction) == nil {
+			numRestored++
+		}
+	}
+	if len(journalTxs) > 0 {
+		log.Info("Restored pool transactions", "NumTxs", numRestored,
+			"NumDropped", len(journalTxs)-numRestored)
+	}
+
+	// Replace the journal with one that records only
+	// the restored transactions
+	if err := pool.RotateJournal(); err != nil {
+		log.Error("Failed to rotate transaction pool journal", "Err", err.Error())
+	}
+
+	// Start removing old account versions
+	// if the node runs in pruned mode
+	if cfg.Chain != nil && cfg.Chain.Pruned {
+		pruner, err := blockchain.NewPruner(bChain, log)
+		if err != nil {
+			log.Fatal("failed to start pruner", "Err", err.Error())
+		}
+		pruner.Manage()
+		addStopFunc(pruner.Stop)
+		log.Info("Pruned mode enabled")
+	}
+
+	// Start removing stale side chains
+	collector := blockchain.NewSideChainCollector(bChain, log)
+	collector.Manage()
+	addStopFunc(collector.Stop)
+
 	// Start the block manager and the node
 	n.Start()
 
-	// Initialized and start the miner if enabled via the cli flag.
+	// Start the instant sealer if the network uses the
+	// instant-seal engine. Otherwise, initialize and start
+	// the miner if enabled via the cli flag.
 	miner.SetNumThreads(numMiners)
-	if mine {
+	if sealer != nil {
+		go sealer.Begin()
+		log.Info("Instant-seal mode enabled")
+	} else if mine {
 		go miner.Begin()
 	}
 
@@ -283,6 +398,9 @@ func start(cmd *cobra.Command, args []string, startConsole bool) (*node.Node, *r
 		bChain.APIs(),
 		rpcServer.APIs(),
 	)
+	if sealer != nil {
+		rpcServer.AddAPI(sealer.APIs())
+	}
 
 	if startRPC {
 		go rpcServer.Serve()
@@ -363,6 +481,7 @@ var startCmd = &cobra.Command{
 			if rpcServer != nil {
 				rpcServer.Stop()
 			}
+			stopServices()
 			if n != nil {
 				n.Stop()
 			}
@@ -387,4 +506,6 @@ func init() {
 	startCmd.Flags().Int("miners", 0, "The number of miner threads to use. (Default: Number of CPU)")
 	startCmd.Flags().Bool("no-net", false, "Closes the network host and prevents (in/out) connections")
 	startCmd.Flags().Bool("sync-disabled", false, "Disable block and transaction synchronization")
+	startCmd.Flags().Bool("pruned", false, "Periodically remove old account versions (default: archive mode)")
+	startCmd.Flags().Bool("index-address-txs", false, "Index transactions by sender and recipient address")
 }
@@ -0,0 +1,12 @@
+package config
+
+// Checkpoints contains the built-in checkpoints of
+// each network version. A checkpoint maps a block
+// number to the hash of the block expected at that
+// height. A side chain that conflicts with a
+// checkpoint is never accepted as the main chain.
+var Checkpoints = map[string]map[uint64]string{
+	DefaultNetVersion: {
+		1: "0x780285d348412ab9320249d7a47c59801eedc034ebc7e588404a6f5453672bf3",
+	},
+}
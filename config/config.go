@@ -42,11 +42,21 @@ func setDefaultConfig() {
 	viper.SetDefault("node.conEstInt", 10)
 	viper.SetDefault("node.messageTimeout", 30)
 	viper.SetDefault("txPool.capacity", 10000)
+	viper.SetDefault("txPool.replaceFeeBump", 10)
 	viper.SetDefault("miner.mode", 0)
 	viper.SetDefault("rpc.username", "admin")
 	viper.SetDefault("rpc.password", "admin")
 	viper.SetDefault("rpc.sessionSecretKey", util.RandString(32))
 	viper.SetDefault("rpc.disableAuth", false)
+	viper.SetDefault("chain.pruned", false)
+	viper.SetDefault("chain.pruneKeepVersions", 16)
+	viper.SetDefault("chain.pruneKeepBlocks", 1000)
+	viper.SetDefault("chain.pruneInt", 600)
+	viper.SetDefault("chain.maxReOrgDepth", 0)
+	viper.SetDefault("chain.sideChainMaxLag", 1000)
+	viper.SetDefault("chain.sideChainMaxAge", 86400)
+	viper.SetDefault("chain.sideChainGCInt", 3600)
+	viper.SetDefault("chain.indexAddressTxs", false)
 }
 
 func setDevDefaultConfig() {
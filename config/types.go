@@ -88,6 +88,12 @@ type TxPoolConfig struct {
 
 	// Capacity is the maximum amount of item the transaction pool can hold
 	Capacity int64 `json:"capacity" mapstructure:"capacity"`
+
+	// ReplaceFeeBump is the minimum percentage by which
+	// the fee rate of a transaction must exceed that of a
+	// pooled transaction with the same sender and nonce
+	// to replace it.
+	ReplaceFeeBump int64 `json:"replaceFeeBump" mapstructure:"replaceFeeBump"`
 }
 
 // MinerConfig defines configuration for mining
@@ -97,6 +103,58 @@ type MinerConfig struct {
 	Mode uint `json:"-" mapstructure:"-"`
 }
 
+// ChainConfig defines configuration for the blockchain
+type ChainConfig struct {
+
+	// Pruned enables pruned mode. In pruned mode, old
+	// versions of accounts are periodically removed.
+	// Archive mode (no pruning) is the default. Pruned
+	// mode requires MaxReOrgDepth to be greater than zero.
+	Pruned bool `json:"pruned" mapstructure:"pruned"`
+
+	// PruneKeepVersions is the number of most
+	// recent versions of an account to keep
+	PruneKeepVersions int64 `json:"pruneKeepVersions" mapstructure:"pruneKeepVersions"`
+
+	// PruneKeepBlocks is the number of most recent
+	// blocks whose account versions are never pruned.
+	// It is raised to MaxReOrgDepth if lower.
+	PruneKeepBlocks int64 `json:"pruneKeepBlocks" mapstructure:"pruneKeepBlocks"`
+
+	// PruneInterval is the interval (in seconds)
+	// between pruning runs
+	PruneInterval int64 `json:"pruneInt" mapstructure:"pruneInt"`
+
+	// MaxReOrgDepth is the maximum number of main chain
+	// blocks a reorganization can roll back. Deeper
+	// reorganizations are rejected. Zero means no limit.
+	MaxReOrgDepth int64 `json:"maxReOrgDepth" mapstructure:"maxReOrgDepth"`
+
+	// SideChainMaxLag is the number of blocks the tip of
+	// a side chain must be behind the tip of the main
+	// chain for the side chain to be considered stale
+	SideChainMaxLag int64 `json:"sideChainMaxLag" mapstructure:"sideChainMaxLag"`
+
+	// SideChainMaxAge is the age (in seconds) the tip
+	// block of a side chain must exceed for the side
+	// chain to be considered stale
+	SideChainMaxAge int64 `json:"sideChainMaxAge" mapstructure:"sideChainMaxAge"`
+
+	// SideChainGCInterval is the interval (in seconds)
+	// between stale side chain collection runs.
+	// Zero disables collection.
+	SideChainGCInterval int64 `json:"sideChainGCInt" mapstructure:"sideChainGCInt"`
+
+	// IndexAddressTxs enables indexing of the
+	// transactions sent or received by an address
+	IndexAddressTxs bool `json:"indexAddressTxs" mapstructure:"indexAddressTxs"`
+
+	// Checkpoints are additional block number to block
+	// hash pairs. They extend the built-in checkpoints
+	// of the current network version.
+	Checkpoints map[uint64]string `json:"checkpoints" mapstructure:"checkpoints"`
+}
+
 // VersionInfo describes the clients
 // components and runtime version information
 type VersionInfo struct {
@@ -121,6 +179,9 @@ type EngineConfig struct {
 	// RPC holds rpc configurations
 	RPC *RPCConfig `json:"rpc" mapstructure:"rpc"`
 
+	// Chain holds blockchain configurations
+	Chain *ChainConfig `json:"chain" mapstructure:"chain"`
+
 	// dataDir is where the node's config and network data is stored
 	dataDir string
 
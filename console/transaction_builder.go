@@ -7,6 +7,7 @@ import (
 
 	"github.com/btcsuite/btcutil/base58"
 
+	"github.com/ellcrys/elld/params"
 	"github.com/ellcrys/elld/types/core"
 	"github.com/ellcrys/elld/util"
 )
@@ -106,6 +107,12 @@ sign:
 	// Set the timestamp
 	o.data["timestamp"] = time.Now().Unix()
 
+	// If the fee has not been set, we must
+	// ask the node for a fee estimate
+	if o.data["fee"] == nil {
+		o.data["fee"] = o.estimateFee()
+	}
+
 	// marshal into core.Transaction
 	var tx core.Transaction
 	_ = util.MapDecode(o.data, &tx)
@@ -124,6 +131,38 @@ sign:
 	return o.data
 }
 
+// estimateFee gets the fee the transaction should pay
+// from the node's fee estimator. The size of the
+// transaction is measured with its hash and signature
+// set, since the fee is not part of the size.
+func (o *TxBalanceBuilder) estimateFee() string {
+
+	var tx core.Transaction
+	_ = util.MapDecode(o.data, &tx)
+	tx.Hash = tx.ComputeHash()
+	sig, err := core.TxSign(&tx, o.e.coinbase.PrivKey().Base58())
+	if err != nil {
+		err = fmt.Errorf("failed to sign tx: %s", err)
+		panic(o.e.vm.MakeCustomError("BuilderError", err.Error()))
+	}
+	tx.Sig = sig
+
+	result, err := o.e.callRPCMethod("ell_estimateFee", map[string]interface{}{
+		"txSize":       tx.GetSizeNoFee(),
+		"targetBlocks": params.DefaultFeeEstimateTargetBlocks,
+	})
+	if err != nil {
+		panic(o.e.vm.MakeCustomError("BuilderError", err.Error()))
+	}
+
+	if result["error"] != nil {
+		errMsg := result["error"].(map[string]interface{})["message"].(string)
+		panic(o.e.vm.MakeCustomError("BuilderError", errMsg))
+	}
+
+	return result["result"].(map[string]interface{})["fee"].(string)
+}
+
 // Packed returns a base58check encode
 // equivalent of the signed payload.
 func (o *TxBalanceBuilder) Packed() string {
@@ -152,9 +152,14 @@ func (p *PubKey) Verify(data, sig []byte) (bool, error) {
 // Addr computes an address from the public key
 func (p *PubKey) Addr() util.String {
 	pk, _ := p.Bytes()
-	pubSha256 := sha3.Sum256(pk)
+	return MakeAddr(pk)
+}
+
+// MakeAddr computes an address from arbitrary data
+func MakeAddr(data []byte) util.String {
+	dataSha256 := sha3.Sum256(data)
 	r := ripemd160.New()
-	r.Write(pubSha256[:])
+	r.Write(dataSha256[:])
 	addr := r.Sum(nil)
 	return util.String(base58.CheckEncode(addr, AddressVersion))
 }
@@ -5,6 +5,7 @@ import (
 	"github.com/ellcrys/elld/rpc"
 	"github.com/ellcrys/elld/rpc/jsonrpc"
 	"github.com/ellcrys/elld/types"
+	"github.com/ellcrys/elld/util"
 )
 
 func (m *Miner) apiSetThreads(args interface{}) *jsonrpc.Response {
@@ -79,3 +80,23 @@ func (m *Miner) APIs() jsonrpc.APISet {
 		},
 	}
 }
+
+// APIs returns all API handlers
+func (s *InstantSealer) APIs() jsonrpc.APISet {
+	return map[string]jsonrpc.APIInfo{
+
+		// namespace: "miner"
+		"seal": {
+			Namespace:   types.NamespaceMiner,
+			Description: "Seal a block containing the pooled transactions",
+			Private:     true,
+			Func: func(arg interface{}) *jsonrpc.Response {
+				block, err := s.Seal()
+				if err != nil {
+					return jsonrpc.Error(types.ErrCodeBlockSealFailed, err.Error(), nil)
+				}
+				return jsonrpc.Success(util.EncodeForJS(block))
+			},
+		},
+	}
+}
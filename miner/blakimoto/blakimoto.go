@@ -10,6 +10,8 @@ import (
 	"sync"
 	"time"
 
+	"github.com/ellcrys/elld/metrics/tick"
+	"github.com/ellcrys/elld/params"
 	"github.com/ellcrys/elld/util"
 	"github.com/ellcrys/elld/util/logger"
 )
@@ -17,6 +19,20 @@ import (
 var (
 	// maxUint256 is a big integer representing 2^256-1
 	maxUint256 = new(big.Int).Exp(big.NewInt(2), big.NewInt(256), big.NewInt(0))
+
+	// big2 is a big integer representing 2
+	big2 = big.NewInt(2)
+)
+
+const (
+	// Name is the name of the engine
+	// in a chain specification
+	Name = "blakimoto"
+
+	// HashrateMAWindow is the moving average window
+	// within which ticks are collected to calculate
+	// the average hashrate
+	HashrateMAWindow = 5 * time.Second
 )
 
 // Mode defines the type and amount of PoW verification an blakimoto engine makes.
@@ -32,6 +48,11 @@ const (
 // Config are the configuration parameters of the blakimoto.
 type Config struct {
 	PowMode Mode
+
+	// ChainSpec contains the consensus parameters
+	// of the network. The main network's parameters
+	// are used if not set.
+	ChainSpec *params.ChainSpec
 }
 
 // Blakimoto is a consensus engine based on proof-of-work implementing the blakimoto
@@ -42,8 +63,9 @@ type Blakimoto struct {
 	log logger.Logger
 
 	// Mining related fields
-	rand   *rand.Rand    // Properly seeded random source for nonces
-	update chan struct{} // Notification channel to update mining parameters
+	rand     *rand.Rand          // Properly seeded random source for nonces
+	update   chan struct{}       // Notification channel to update mining parameters
+	hashrate *tick.MovingAverage // Tracks the average hashrate while sealing
 
 	// The fields below are hooks for testing
 	fakeDelay time.Duration // Time delay to sleep for before returning from verify
@@ -53,18 +75,23 @@ type Blakimoto struct {
 
 // New creates a full sized blakimoto PoW scheme.
 func New(config Config, log logger.Logger) *Blakimoto {
+	if config.ChainSpec == nil {
+		config.ChainSpec = params.DefaultChainSpec()
+	}
 	return &Blakimoto{
-		config: config,
-		update: make(chan struct{}),
-		log:    log,
+		config:   config,
+		update:   make(chan struct{}),
+		log:      log,
+		hashrate: tick.NewMovingAverage(HashrateMAWindow),
 	}
 }
 
 // ConfiguredBlakimoto creates an Blakimoto instance pre-configured
-// using the engine configuration.
-func ConfiguredBlakimoto(mode Mode, log logger.Logger) *Blakimoto {
+// using the engine configuration and the chain specification.
+func ConfiguredBlakimoto(mode Mode, spec *params.ChainSpec, log logger.Logger) *Blakimoto {
 	return New(Config{
-		PowMode: mode,
+		PowMode:   mode,
+		ChainSpec: spec,
 	}, log)
 }
 
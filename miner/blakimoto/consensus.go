@@ -17,14 +17,18 @@
 package blakimoto
 
 import (
+	crand "crypto/rand"
 	"errors"
 	"fmt"
+	"math"
 	"math/big"
+	"math/rand"
 	"time"
 
 	"github.com/ellcrys/elld/params"
 	"github.com/ellcrys/elld/types"
 	"github.com/ellcrys/elld/types/core"
+	"github.com/ellcrys/elld/util"
 )
 
 // Various error messages to mark blocks invalid. These should be private to
@@ -43,14 +47,15 @@ func (b *Blakimoto) VerifyHeader(header, parent types.Header, seal bool) error {
 
 	// Ensure that the header's extra-data
 	// section is of a reasonable size
-	if uint64(len(header.GetExtra())) > params.MaximumExtraDataSize {
+	spec := b.config.ChainSpec.At(header.GetNumber())
+	if uint64(len(header.GetExtra())) > spec.MaximumExtraDataSize {
 		return fmt.Errorf("extra-data too long: %d > %d", len(header.GetExtra()),
-			params.MaximumExtraDataSize)
+			spec.MaximumExtraDataSize)
 	}
 
 	// Verify the header's timestamp
 	if time.Unix(header.GetTimestamp(), 0).After(time.Now().
-		Add(params.AllowedFutureBlockTime)) {
+		Add(spec.GetAllowedFutureBlockTime())) {
 		return ErrFutureBlock
 	}
 
@@ -97,22 +102,32 @@ func (b *Blakimoto) VerifyHeader(header, parent types.Header, seal bool) error {
 // new block should have when created at time
 // given the parent block's time and difficulty.
 func (b *Blakimoto) CalcDifficulty(blockHeader types.Header, parent types.Header) *big.Int {
-	return CalcDifficulty(blockHeader, parent)
+	return CalcDifficulty(b.config.ChainSpec, blockHeader, parent)
 }
 
 // CalcDifficulty is the difficulty adjustment
 // algorithm. It returns the difficulty that a new
 // block should have when created at time
 // given the parent block's time and difficulty.
-func CalcDifficulty(blockHeader types.Header, parent types.Header) *big.Int {
-	return calcDifficultyInception(uint64(blockHeader.GetTimestamp()),
-		parent)
+// The parameters of the chain specification that
+// apply to the block's number are used.
+func CalcDifficulty(spec *params.ChainSpec, blockHeader types.Header,
+	parent types.Header) *big.Int {
+	number := blockHeader.GetNumber()
+	return calcDifficultyInception(spec.At(number),
+		uint64(blockHeader.GetTimestamp()), parent,
+		spec.IsActive(params.ForkFastDifficultyDrop, number))
 }
 
-func calcDifficultyInception(time uint64, parent types.Header) *big.Int {
+// calcDifficultyInception calculates the difficulty of a
+// block created at time. If fastDrop is true, the difficulty
+// is reduced by twice the adjustment when the time since the
+// parent block is at least twice the duration limit.
+func calcDifficultyInception(spec *params.ChainSpec, time uint64,
+	parent types.Header, fastDrop bool) *big.Int {
 
 	diff := new(big.Int)
-	adjust := new(big.Int).Div(parent.GetDifficulty(), params.DifficultyBoundDivisor)
+	adjust := new(big.Int).Div(parent.GetDifficulty(), spec.DifficultyBoundDivisor)
 	bigTime := new(big.Int)
 	bigParentTime := new(big.Int)
 
@@ -125,8 +140,12 @@ func calcDifficultyInception(time uint64, parent types.Header) *big.Int {
 
 	// Increase difficulty when timespan is lower than
 	// the expected time span between blocks.
-	if timespan.Cmp(params.DurationLimit) < 0 {
+	if timespan.Cmp(spec.DurationLimit) < 0 {
 		diff.Add(parent.GetDifficulty(), adjust)
+	} else if fastDrop && timespan.Cmp(new(big.Int).Mul(spec.DurationLimit, big2)) >= 0 {
+		// Reduce difficulty faster when the timespan is at
+		// least twice the expected time span between blocks
+		diff.Sub(parent.GetDifficulty(), new(big.Int).Mul(adjust, big2))
 	} else {
 		// Reduce difficulty when timespan is greater than
 		// the expected time span between blocks
@@ -135,8 +154,8 @@ func calcDifficultyInception(time uint64, parent types.Header) *big.Int {
 
 	// Normalize to the minimum difficulty if
 	// the calculated difficulty is below the minimum.
-	if diff.Cmp(params.MinimumDifficulty) < 0 {
-		diff.Set(params.MinimumDifficulty)
+	if diff.Cmp(spec.MinimumDifficulty) < 0 {
+		diff.Set(spec.MinimumDifficulty)
 	}
 
 	return diff
@@ -173,6 +192,53 @@ func (b *Blakimoto) VerifySeal(header types.Header) error {
 	return nil
 }
 
+// Seal searches for a nonce that is a proof-of-work
+// solution for the header of the block, starting from
+// a random nonce. It returns the block with the nonce
+// set, or nil if stop is closed before a nonce is found.
+func (b *Blakimoto) Seal(block types.Block, stop <-chan struct{}) (types.Block, error) {
+
+	b.lock.Lock()
+	if b.rand == nil {
+		seed, err := crand.Int(crand.Reader, big.NewInt(math.MaxInt64))
+		if err != nil {
+			b.lock.Unlock()
+			return nil, err
+		}
+		b.rand = rand.New(rand.NewSource(seed.Int64()))
+	}
+	nonce := uint64(b.rand.Int63())
+	b.lock.Unlock()
+
+	header := block.GetHeader().Copy()
+	hash := header.GetHashNoNonce().Bytes()
+	target := new(big.Int).Div(maxUint256, header.GetDifficulty())
+
+	for {
+		select {
+		case <-stop:
+			return nil, nil
+		default:
+		}
+
+		b.hashrate.Tick()
+
+		result := BlakeHash(hash, nonce)
+		if new(big.Int).SetBytes(result).Cmp(target) <= 0 {
+			header.SetNonce(util.EncodeNonce(nonce))
+			return block.ReplaceHeader(header), nil
+		}
+		nonce++
+	}
+}
+
+// Hashrate returns the moving average rate
+// of hashing per second while sealing blocks
+func (b *Blakimoto) Hashrate() float64 {
+	rate := b.hashrate.Average(1 * time.Minute)
+	return rate / 60
+}
+
 // Prepare initializes the difficulty and
 // total difficulty fields of a header to
 // conform to the protocol
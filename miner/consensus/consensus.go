@@ -0,0 +1,34 @@
+// Package consensus provides the consensus
+// engines a network can be configured to use
+package consensus
+
+import (
+	"fmt"
+
+	"github.com/ellcrys/elld/config"
+	"github.com/ellcrys/elld/miner/blakimoto"
+	"github.com/ellcrys/elld/miner/instantseal"
+	"github.com/ellcrys/elld/params"
+	"github.com/ellcrys/elld/types"
+	"github.com/ellcrys/elld/util/logger"
+)
+
+// New creates the consensus engine
+// selected by the chain specification.
+// The blakimoto engine uses the miner
+// mode set in cfg.
+func New(spec *params.ChainSpec, cfg *config.EngineConfig,
+	log logger.Logger) (types.ConsensusEngine, error) {
+	switch spec.ConsensusEngine {
+	case blakimoto.Name:
+		mode := blakimoto.ModeNormal
+		if cfg != nil && cfg.Miner != nil {
+			mode = blakimoto.Mode(cfg.Miner.Mode)
+		}
+		return blakimoto.ConfiguredBlakimoto(mode, spec, log), nil
+	case instantseal.Name:
+		return instantseal.New(spec), nil
+	default:
+		return nil, fmt.Errorf("unknown consensus engine: %s", spec.ConsensusEngine)
+	}
+}
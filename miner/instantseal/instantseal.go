@@ -0,0 +1,137 @@
+// Package instantseal provides a consensus engine for
+// development networks. Blocks require no proof-of-work
+// and have a fixed difficulty, so they can be sealed
+// as soon as they are created.
+package instantseal
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/ellcrys/elld/params"
+	"github.com/ellcrys/elld/types"
+	"github.com/ellcrys/elld/types/core"
+)
+
+// Name is the name of the engine
+// in a chain specification
+const Name = "instantseal"
+
+var (
+	// Difficulty is the difficulty of every block
+	Difficulty = big.NewInt(1)
+
+	// ErrUnknownParent indicates an unknown parent of a block
+	ErrUnknownParent = errors.New("block's parent is unknown")
+
+	// ErrFutureBlock is returned when a block's timestamp
+	// is in the future according to the current node.
+	ErrFutureBlock = errors.New("block in the future")
+
+	// ErrInvalidNumber is returned if a block's number
+	// doesn't equal it's parent's plus one.
+	ErrInvalidNumber = errors.New("invalid block number")
+
+	errOlderBlockTime = errors.New("timestamp older than parent's")
+)
+
+// InstantSeal is a consensus engine that
+// seals blocks without proof-of-work
+type InstantSeal struct {
+	spec *params.ChainSpec
+}
+
+// New creates an InstantSeal engine. The main network's
+// chain specification is used if spec is nil.
+func New(spec *params.ChainSpec) *InstantSeal {
+	if spec == nil {
+		spec = params.DefaultChainSpec()
+	}
+	return &InstantSeal{
+		spec: spec,
+	}
+}
+
+// VerifyHeader checks whether a header
+// conforms to the consensus rules
+func (e *InstantSeal) VerifyHeader(header, parent types.Header, seal bool) error {
+
+	// Ensure that the header's extra-data
+	// section is of a reasonable size
+	spec := e.spec.At(header.GetNumber())
+	if uint64(len(header.GetExtra())) > spec.MaximumExtraDataSize {
+		return fmt.Errorf("extra-data too long: %d > %d", len(header.GetExtra()),
+			spec.MaximumExtraDataSize)
+	}
+
+	// Verify the header's timestamp. Blocks can be
+	// sealed within the same second as their parent.
+	if time.Unix(header.GetTimestamp(), 0).After(time.Now().
+		Add(spec.GetAllowedFutureBlockTime())) {
+		return ErrFutureBlock
+	}
+
+	if header.GetTimestamp() < parent.GetTimestamp() {
+		return errOlderBlockTime
+	}
+
+	if header.GetDifficulty().Cmp(Difficulty) != 0 {
+		return fmt.Errorf("invalid difficulty: have %v, want %v",
+			header.GetDifficulty(), Difficulty)
+	}
+
+	// Verify that the total difficulty is
+	// parent total difficulty + header total
+	// difficulty
+	expectedTd := new(big.Int).Add(parent.GetTotalDifficulty(), header.GetDifficulty())
+	if headerTd := header.GetTotalDifficulty(); headerTd.Cmp(expectedTd) != 0 {
+		return fmt.Errorf("invalid total difficulty: have %v, want %v",
+			headerTd, expectedTd)
+	}
+
+	// Verify that the block number is
+	// parent's +1
+	if diff := header.GetNumber() - parent.GetNumber(); diff != 1 {
+		return ErrInvalidNumber
+	}
+
+	return nil
+}
+
+// VerifySeal accepts every seal
+// since no work is required
+func (e *InstantSeal) VerifySeal(header types.Header) error {
+	return nil
+}
+
+// CalcDifficulty returns the fixed difficulty
+func (e *InstantSeal) CalcDifficulty(header, parent types.Header) *big.Int {
+	return new(big.Int).Set(Difficulty)
+}
+
+// Prepare initializes the difficulty and
+// total difficulty fields of a header
+func (e *InstantSeal) Prepare(chain types.ChainReaderFactory, header types.Header) error {
+
+	// Get the header of the block's parent.
+	parent, err := chain.GetHeaderByHash(header.GetParentHash())
+	if err != nil {
+		if err != core.ErrBlockNotFound {
+			return err
+		}
+		return ErrUnknownParent
+	}
+
+	header.SetDifficulty(e.CalcDifficulty(header, parent))
+	header.SetTotalDifficulty(new(big.Int).Add(parent.GetTotalDifficulty(),
+		header.GetDifficulty()))
+	return nil
+}
+
+// Seal returns the block unchanged
+// since no work is required
+func (e *InstantSeal) Seal(block types.Block, stop <-chan struct{}) (types.Block, error) {
+	return block, nil
+}
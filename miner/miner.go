@@ -8,11 +8,8 @@ import (
 	"sync"
 	"time"
 
-	"github.com/ellcrys/elld/metrics/tick"
-
 	"github.com/ellcrys/elld/config"
 	"github.com/ellcrys/elld/crypto"
-	"github.com/ellcrys/elld/miner/blakimoto"
 	"github.com/ellcrys/elld/types"
 	"github.com/ellcrys/elld/types/core"
 	"github.com/ellcrys/elld/util"
@@ -24,17 +21,13 @@ import (
 const (
 	// EventWorkerFoundBlock indicates that a worker found a block
 	EventWorkerFoundBlock = "event.workerFoundBlock"
-
-	// HashrateMAWindow is the moving average window
-	// within which ticks are collected to calculate
-	// the average hashrate
-	HashrateMAWindow = 5 * time.Second
 )
 
-var (
-	// maxUint256 is a big integer representing 2^256-1
-	maxUint256 = new(big.Int).Exp(big.NewInt(2), big.NewInt(256), big.NewInt(0))
-)
+// hashrateEngine is implemented by consensus
+// engines that track their hashrate
+type hashrateEngine interface {
+	Hashrate() float64
+}
 
 // Miner provides proof-of-work computation,
 // difficulty calculation and prepares a
@@ -60,8 +53,9 @@ type Miner struct {
 	// log is the logger for the miner
 	log logger.Logger
 
-	// blakimoto instance
-	blakimoto *blakimoto.Blakimoto
+	// engine is the consensus engine
+	// that prepares and seals blocks
+	engine types.ConsensusEngine
 
 	// Event emitter
 	event *emitter.Emitter
@@ -75,9 +69,6 @@ type Miner struct {
 	// blockMaker provides functions for creating a block
 	blockMaker types.BlockMaker
 
-	// hashrate for tracking average hashrate
-	hashrate *tick.MovingAverage
-
 	// processing indicates that a block is being
 	// processed for inclusion in a branch
 	processing bool
@@ -97,18 +88,20 @@ func NewMiner(mineKey *crypto.Key, blockMaker types.BlockMaker,
 		blockMaker: blockMaker,
 		iEvent:     &emitter.Emitter{},
 		minerKey:   mineKey,
-		blakimoto:  blakimoto.ConfiguredBlakimoto(blakimoto.Mode(cfg.Miner.Mode), log),
-		hashrate:   tick.NewMovingAverage(HashrateMAWindow),
+		engine:     blockMaker.GetConsensusEngine(),
 		done:       make(chan bool),
 		processMtx: &sync.Mutex{},
 	}
 }
 
-// getHashrate returns the moving average
-// rate of hashing per second
+// getHashrate returns the moving average rate of
+// hashing per second of the consensus engine. It is
+// zero if the engine does not track its hashrate.
 func (m *Miner) getHashrate() float64 {
-	rate := m.hashrate.Average(1 * time.Minute)
-	return rate / 60
+	if engine, ok := m.engine.(hashrateEngine); ok {
+		return engine.Hashrate()
+	}
+	return 0
 }
 
 // Begin starts proof-of-work computation
@@ -154,7 +147,7 @@ func (m *Miner) startWorkers() error {
 	}
 
 	// Prepare the proposed block.
-	m.blakimoto.Prepare(m.blockMaker.ChainReader(), proposed.GetHeader())
+	m.engine.Prepare(m.blockMaker.ChainReader(), proposed.GetHeader())
 
 	m.Lock()
 	m.workers = []*Worker{}
@@ -167,8 +160,8 @@ func (m *Miner) startWorkers() error {
 			id:         i,
 			log:        m.log,
 			blockMaker: m.blockMaker,
-			blakimoto:  m.blakimoto,
-			hashrate:   m.hashrate,
+			engine:     m.engine,
+			stop:       make(chan struct{}),
 		}
 		m.workers = append(m.workers, w)
 		go w.mine(proposed)
@@ -208,11 +201,6 @@ func (m *Miner) RestartWorkers() error {
 // attempts to append the block to a branch.
 func (m *Miner) processBlock(fb *FoundBlock) error {
 
-	// Update the block header with the found nonce
-	header := fb.Block.GetHeader().Copy()
-	header.SetNonce(util.EncodeNonce(fb.Nonce))
-	fb.Block = fb.Block.ReplaceHeader(header)
-
 	// Compute and set block hash and signature
 	fb.Block.SetHash(fb.Block.ComputeHash())
 	blockSig, _ := core.BlockSign(fb.Block, m.minerKey.PrivKey().Base58())
@@ -329,7 +317,6 @@ func (m *Miner) Stop() {
 	close(m.done)
 	m.mining = false
 	m.stopped = true
-	m.hashrate = tick.NewMovingAverage(HashrateMAWindow)
 	m.Unlock()
 
 	m.stopWorkers()
@@ -0,0 +1,156 @@
+package miner
+
+import (
+	"fmt"
+	"math/big"
+	"sync"
+
+	"github.com/ellcrys/elld/crypto"
+	"github.com/ellcrys/elld/types"
+	"github.com/ellcrys/elld/types/core"
+	"github.com/ellcrys/elld/util"
+	"github.com/ellcrys/elld/util/logger"
+	"github.com/fatih/color"
+	"github.com/olebedev/emitter"
+)
+
+// InstantSealer creates a block as soon as a
+// transaction enters the pool. It is meant for
+// development networks whose consensus engine
+// requires no work to seal a block (e.g instantseal).
+type InstantSealer struct {
+	sync.Mutex
+
+	// minerKey is the key associated with
+	// the loaded account (a.k.a coinbase)
+	minerKey *crypto.Key
+
+	// blockMaker provides functions for creating a block
+	blockMaker types.BlockMaker
+
+	// event is the event emitter
+	event *emitter.Emitter
+
+	// log is the logger for the sealer
+	log logger.Logger
+
+	// pooled receives an event for
+	// each transaction added to the pool
+	pooled <-chan emitter.Event
+}
+
+// NewInstantSealer creates an InstantSealer instance
+func NewInstantSealer(minerKey *crypto.Key, blockMaker types.BlockMaker,
+	event *emitter.Emitter, log logger.Logger) *InstantSealer {
+	return &InstantSealer{
+		minerKey:   minerKey,
+		blockMaker: blockMaker,
+		event:      event,
+		log:        log,
+	}
+}
+
+// Begin seals a block each time a transaction
+// is added to the pool until Stop is called.
+func (s *InstantSealer) Begin() {
+
+	s.Lock()
+	if s.pooled != nil {
+		s.Unlock()
+		return
+	}
+	pooled := s.event.On(core.EventTransactionPooled)
+	s.pooled = pooled
+	s.Unlock()
+
+	for range pooled {
+		// Several transactions may be pooled before a block
+		// is sealed. The block includes all of them, so
+		// there is nothing to seal for the others.
+		if _, err := s.seal(true); err != nil {
+			s.log.Error("Failed to seal block", "Err", err.Error())
+		}
+	}
+}
+
+// Stop stops sealing blocks when
+// transactions are added to the pool
+func (s *InstantSealer) Stop() {
+	s.Lock()
+	pooled := s.pooled
+	s.pooled = nil
+	s.Unlock()
+	if pooled != nil {
+		s.event.Off(core.EventTransactionPooled, pooled)
+	}
+}
+
+// Seal creates a block containing the transactions of
+// the pool, seals it and appends it to the main chain.
+// The block is empty if the pool has no transaction.
+func (s *InstantSealer) Seal() (types.Block, error) {
+	return s.seal(false)
+}
+
+// seal creates, seals and processes a block. When
+// onPooled is true, the seal was triggered by a pooled
+// transaction and nothing is sealed if the sealer has
+// been stopped or the block has no transaction.
+func (s *InstantSealer) seal(onPooled bool) (types.Block, error) {
+	s.Lock()
+	defer s.Unlock()
+
+	if onPooled && s.pooled == nil {
+		return nil, nil
+	}
+
+	block, err := s.blockMaker.Generate(&types.GenerateBlockParams{
+		Creator:     s.minerKey,
+		Nonce:       util.EncodeNonce(1),
+		Difficulty:  new(big.Int).SetInt64(1),
+		AddFeeAlloc: true,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if onPooled && len(block.GetTransactions()) == 0 {
+		return nil, nil
+	}
+
+	// Let the consensus engine set the
+	// difficulty and seal the block
+	engine := s.blockMaker.GetConsensusEngine()
+	header := block.GetHeader().Copy()
+	if err := engine.Prepare(s.blockMaker.ChainReader(), header); err != nil {
+		return nil, fmt.Errorf("failed to prepare block: %s", err)
+	}
+
+	block, err = engine.Seal(block.ReplaceHeader(header), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to seal block: %s", err)
+	}
+
+	// Compute and set block hash and signature
+	block.SetHash(block.ComputeHash())
+	blockSig, err := core.BlockSign(block, s.minerKey.PrivKey().Base58())
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign block: %s", err)
+	}
+	block.SetSignature(blockSig)
+
+	if _, err := s.blockMaker.ProcessBlock(block); err != nil {
+		return nil, err
+	}
+
+	// Remove the transactions of the block from the pool
+	// now, so that the next block does not select them
+	// before the block manager removes them.
+	s.blockMaker.GetTxPool().Remove(block.GetTransactions()...)
+
+	s.log.Info(color.GreenString("New block sealed"),
+		"Number", block.GetNumber(),
+		"NumTxs", len(block.GetTransactions()))
+
+	return block, nil
+}
@@ -0,0 +1,120 @@
+package miner
+
+import (
+	"math/big"
+	"os"
+	"time"
+
+	"github.com/ellcrys/elld/blockchain"
+	"github.com/ellcrys/elld/blockchain/txpool"
+	"github.com/ellcrys/elld/config"
+	"github.com/ellcrys/elld/crypto"
+	"github.com/ellcrys/elld/elldb"
+	"github.com/ellcrys/elld/miner/instantseal"
+	"github.com/ellcrys/elld/params"
+	"github.com/ellcrys/elld/testutil"
+	"github.com/ellcrys/elld/types/core"
+	"github.com/ellcrys/elld/util"
+	"github.com/ellcrys/elld/util/logger"
+	"github.com/olebedev/emitter"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("InstantSealer", func() {
+
+	var err error
+	var cfg *config.EngineConfig
+	var db elldb.DB
+	var bc *blockchain.Blockchain
+	var pool *txpool.TxPool
+	var event *emitter.Emitter
+	var sealer *InstantSealer
+	var sender, receiver *crypto.Key
+	var log = logger.NewLogrusNoOp()
+
+	BeforeEach(func() {
+		cfg, err = testutil.SetTestCfg()
+		Expect(err).To(BeNil())
+		cfg.Node.Mode = config.ModeDev
+
+		db = elldb.NewDB(cfg.NetDataDir())
+		err = db.Open(util.RandString(5))
+		Expect(err).To(BeNil())
+
+		sender = crypto.NewKeyFromIntSeed(1)
+		receiver = crypto.NewKeyFromIntSeed(2)
+
+		spec := params.DefaultChainSpec()
+		spec.ConsensusEngine = instantseal.Name
+
+		event = &emitter.Emitter{}
+		pool = txpool.New(100, spec)
+		bc = blockchain.New(pool, spec, instantseal.New(spec), cfg, log)
+		bc.SetDB(db)
+		bc.SetEventEmitter(event)
+
+		genesisBlock, err := blockchain.LoadBlockFromFile("genesis-test.json")
+		Expect(err).To(BeNil())
+		bc.SetGenesisBlock(genesisBlock)
+		Expect(bc.Up()).To(BeNil())
+
+		sealer = NewInstantSealer(crypto.NewKeyFromIntSeed(3), bc, event, log)
+	})
+
+	AfterEach(func() {
+		sealer.Stop()
+		db.Close()
+		err = os.RemoveAll(cfg.DataDir())
+		Expect(err).To(BeNil())
+	})
+
+	Describe(".Seal", func() {
+
+		It("should append an empty block when the pool is empty", func() {
+			block, err := sealer.Seal()
+			Expect(err).To(BeNil())
+			Expect(block.GetNumber()).To(Equal(uint64(2)))
+			Expect(block.GetTransactions()).To(BeEmpty())
+			Expect(block.GetHeader().GetDifficulty()).To(Equal(big.NewInt(1)))
+
+			tip, err := bc.ChainReader().Current()
+			Expect(err).To(BeNil())
+			Expect(tip.GetHash()).To(Equal(block.GetHash()))
+		})
+
+		It("should include the pooled transactions", func() {
+			tx := core.NewTx(core.TxTypeBalance, 1, receiver.Addr(), sender, "1", "2.5", time.Now().Unix())
+			Expect(pool.Put(tx)).To(BeNil())
+
+			block, err := sealer.Seal()
+			Expect(err).To(BeNil())
+			Expect(block.GetTransactions()).To(HaveLen(2))
+			Expect(block.GetTransactions()[0].GetHash()).To(Equal(tx.GetHash()))
+			Expect(pool.Size()).To(Equal(int64(0)))
+		})
+
+		It("should seal consecutive blocks within the same second", func() {
+			_, err := sealer.Seal()
+			Expect(err).To(BeNil())
+			block, err := sealer.Seal()
+			Expect(err).To(BeNil())
+			Expect(block.GetNumber()).To(Equal(uint64(3)))
+		})
+	})
+
+	Describe(".Begin", func() {
+		It("should seal a block when a transaction is pooled", func() {
+			go sealer.Begin()
+
+			tx := core.NewTx(core.TxTypeBalance, 1, receiver.Addr(), sender, "1", "2.5", time.Now().Unix())
+			Expect(pool.Put(tx)).To(BeNil())
+
+			Eventually(func() bool {
+				go event.Emit(core.EventTransactionPooled, tx)
+				_, err := bc.GetTransaction(tx.GetHash())
+				return err == nil
+			}, 5*time.Second).Should(BeTrue())
+		})
+	})
+})
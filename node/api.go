@@ -7,7 +7,9 @@ import (
 	"strings"
 
 	"github.com/btcsuite/btcutil/base58"
+	"github.com/ellcrys/elld/blockchain"
 	"github.com/ellcrys/elld/config"
+	"github.com/ellcrys/elld/params"
 
 	"github.com/ellcrys/elld/rpc"
 	"github.com/ellcrys/elld/rpc/jsonrpc"
@@ -299,8 +301,9 @@ func (n *Node) apiGetSyncStat(arg interface{}) *jsonrpc.Response {
 // of the transaction pool
 func (n *Node) apiTxPoolSizeInfo(arg interface{}) *jsonrpc.Response {
 	return jsonrpc.Success(map[string]int64{
-		"byteSize": n.GetBlockchain().GetTxPool().ByteSize(),
-		"numTxs":   n.GetTxPool().Size(),
+		"byteSize":  n.GetBlockchain().GetTxPool().ByteSize(),
+		"numTxs":    n.GetTxPool().Size(),
+		"numQueued": n.GetTxPool().QueueContainer().Size(),
 	})
 }
 
@@ -346,7 +349,7 @@ func (n *Node) processTx(txData map[string]interface{}) *jsonrpc.Response {
 	}
 
 	// Attempt to add the transaction to the pool
-	if err := n.txManager.AddTx(&tx); err != nil {
+	if err := n.txManager.AddLocalTx(&tx); err != nil {
 		return jsonrpc.Error(types.ErrCodeTxFailed, err.Error(), nil)
 	}
 
@@ -401,14 +404,59 @@ func (n *Node) apiSend(arg interface{}) *jsonrpc.Response {
 	return n.processTx(txData)
 }
 
-// apiFetchPool fetches transactions currently in the pool
+// apiEstimateFee estimates the fee a transaction of a
+// given size should pay to be included in a block
+// within a number of blocks
+func (n *Node) apiEstimateFee(arg interface{}) *jsonrpc.Response {
+
+	mArgs, ok := arg.(map[string]interface{})
+	if !ok {
+		return jsonrpc.Error(types.ErrCodeUnexpectedArgType,
+			rpc.ErrMethodArgType("Map").Error(), nil)
+	}
+
+	txSize, ok := mArgs["txSize"].(float64)
+	if !ok {
+		return jsonrpc.Error(types.ErrCodeQueryParamError,
+			"txSize is required", nil)
+	}
+
+	var targetBlocks = params.DefaultFeeEstimateTargetBlocks
+	if val, ok := mArgs["targetBlocks"]; ok {
+		target, ok := val.(float64)
+		if !ok {
+			return jsonrpc.Error(types.ErrCodeQueryParamError,
+				"targetBlocks must be a number", nil)
+		}
+		targetBlocks = int(target)
+	}
+
+	estimate, err := blockchain.NewFeeEstimator(n.bChain, n.GetTxPool()).
+		Estimate(int64(txSize), targetBlocks)
+	if err != nil {
+		return jsonrpc.Error(types.ErrCodeQueryFailed, err.Error(), nil)
+	}
+
+	return jsonrpc.Success(estimate)
+}
+
+// apiFetchPool fetches transactions currently in the pool.
+// Pending transactions can be included in the next block
+// while queued transactions wait for a nonce gap to be filled.
 func (n *Node) apiFetchPool(arg interface{}) *jsonrpc.Response {
-	var txs = []types.Transaction{}
+	var pending, queued = []types.Transaction{}, []types.Transaction{}
 	n.GetTxPool().Container().IFind(func(tx types.Transaction) bool {
-		txs = append(txs, tx)
+		pending = append(pending, tx)
 		return false
 	})
-	return jsonrpc.Success(txs)
+	n.GetTxPool().QueueContainer().IFind(func(tx types.Transaction) bool {
+		queued = append(queued, tx)
+		return false
+	})
+	return jsonrpc.Success(map[string][]types.Transaction{
+		"pending": pending,
+		"queued":  queued,
+	})
 }
 
 func (n *Node) apiBroadcastPeers(arg interface{}) *jsonrpc.Response {
@@ -514,6 +562,11 @@ func (n *Node) APIs() jsonrpc.APISet {
 			Description: "Send a base58 encoded balance transaction",
 			Func:        n.apiSendRaw,
 		},
+		"estimateFee": {
+			Namespace:   types.NamespaceEll,
+			Description: "Estimate the fee of a transaction",
+			Func:        n.apiEstimateFee,
+		},
 
 		// namespace: "net"
 		"join": {
@@ -255,6 +255,13 @@ func (bm *BlockManager) handleUnprocessedBlocks() error {
 
 // handleOrphan sends a RequestBlock message to
 // the originator of an orphaned block.
+//
+// If the parent of the orphan is also an orphan, the
+// orphan ancestors are followed and the first missing
+// block of the parent chain is requested instead.
+// Each requested block that turns out to be an orphan
+// triggers another request until the parent chain
+// connects to a known chain.
 func (bm *BlockManager) handleOrphan(b *core.Block) {
 
 	// When the block has no broadcaster, it is likely
@@ -265,7 +272,16 @@ func (bm *BlockManager) handleOrphan(b *core.Block) {
 		return
 	}
 
+	orphans := bm.bChain.OrphanBlocks()
 	parentHash := b.GetHeader().GetParentHash()
+	for i := 0; i < orphans.Len(); i++ {
+		orphan, ok := orphans.Get(parentHash.HexStr()).(types.Block)
+		if !ok {
+			break
+		}
+		parentHash = orphan.GetHeader().GetParentHash()
+	}
+
 	bm.log.Debug("Requesting orphan parent block from broadcaster",
 		"BlockNo", b.GetNumber(),
 		"ParentBlockHash", parentHash.SS())
@@ -401,6 +417,18 @@ func (bm *BlockManager) sync() error {
 		var block core.Block
 		copier.Copy(&block, bb)
 
+		// Stop synchronizing with the candidate if
+		// it sends a block that conflicts with a
+		// checkpoint. Its chain can never be accepted.
+		if err := bm.bChain.CheckCheckpoint(block.GetNumber(),
+			block.GetHash()); err != nil {
+			bm.log.Debug("Sync candidate sent a block that conflicts with a checkpoint",
+				"PeerID", bm.bestSyncCandidate.PeerID,
+				"BlockNo", block.GetNumber())
+			delete(bm.syncCandidate, bm.bestSyncCandidate.PeerID)
+			goto resync
+		}
+
 		// Set the broadcaster
 		block.SetBroadcaster(peer)
 		bm.bestSyncCandidate.LastBlockSent = block.GetHash()
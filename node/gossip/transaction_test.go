@@ -130,7 +130,7 @@ var _ = Describe("Transaction", func() {
 
 			var eventArgs emitter.Event
 			BeforeEach(func() {
-				rp.SetTxsPool(txpool.New(0))
+				rp.SetTxsPool(txpool.New(0, nil))
 				err := lp.Gossip().BroadcastTx(tx, []core.Engine{rp})
 				Expect(err).To(BeNil())
 
@@ -10,6 +10,7 @@ import (
 	"github.com/ellcrys/elld/blockchain/txpool"
 	"github.com/ellcrys/elld/crypto"
 	"github.com/ellcrys/elld/elldb"
+	"github.com/ellcrys/elld/miner/blakimoto"
 	"github.com/ellcrys/elld/params"
 	"github.com/ellcrys/elld/testutil"
 	"github.com/ellcrys/elld/util"
@@ -60,9 +61,10 @@ func makeTestNodeWith(port int, seed int) *Node {
 	}
 
 	evtEmitter := &emitter.Emitter{}
-	txp := txpool.New(100)
+	txp := txpool.New(100, nil)
 
-	bc := blockchain.New(txp, cfg, log)
+	engine := blakimoto.ConfiguredBlakimoto(blakimoto.ModeNormal, nil, log)
+	bc := blockchain.New(txp, nil, engine, cfg, log)
 	bc.SetEventEmitter(evtEmitter)
 	bc.SetDB(db)
 	genesisBlock, err := blockchain.LoadBlockFromFile("genesis-test.json")
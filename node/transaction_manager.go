@@ -67,6 +67,18 @@ func (tm *TxManager) Manage() {
 
 // AddTx adds a transaction to the pool
 func (tm *TxManager) AddTx(tx types.Transaction) error {
+	return tm.addTx(tx, false)
+}
+
+// AddLocalTx is like AddTx but for transactions
+// submitted locally (e.g via RPC). Local transactions
+// are not evicted when the pool is full.
+func (tm *TxManager) AddLocalTx(tx types.Transaction) error {
+	return tm.addTx(tx, true)
+}
+
+// addTx validates a transaction and adds it to the pool
+func (tm *TxManager) addTx(tx types.Transaction, local bool) error {
 
 	// TxTypeAlloc transactions are not allowed
 	if tx.GetType() == core.TxTypeAlloc {
@@ -85,7 +97,13 @@ func (tm *TxManager) AddTx(tx types.Transaction) error {
 
 	// Next we attempt to add the transaction
 	// to the transactions pool.
-	if err := tm.engine.GetTxPool().Put(tx); err != nil {
+	var err error
+	if local {
+		err = tm.engine.GetTxPool().PutLocal(tx)
+	} else {
+		err = tm.engine.GetTxPool().Put(tx)
+	}
+	if err != nil {
 		go tm.evt.Emit(core.EventTransactionInvalid, tx, err)
 		return err
 	}
@@ -0,0 +1,278 @@
+package params
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// ChainSpecFileName is the name of the file, in the
+// network data directory, that contains the chain
+// specification of the network
+var ChainSpecFileName = "chainspec.json"
+
+// Fork is a protocol upgrade. The rules introduced
+// by a fork apply to blocks at or above its
+// activation height.
+type Fork struct {
+	Name             string `json:"name"`
+	ActivationHeight uint64 `json:"activationHeight"`
+
+	// Params contains the consensus parameters
+	// changed by the fork. Parameters not set
+	// keep their value.
+	Params json.RawMessage `json:"params,omitempty"`
+}
+
+// ChainSpec contains the consensus parameters of a
+// network. Networks with different parameters (e.g
+// private networks with a shorter block time) are
+// described by different chain specifications.
+type ChainSpec struct {
+
+	// ConsensusEngine is the name of the consensus
+	// engine that produces and verifies blocks
+	ConsensusEngine string `json:"consensusEngine"`
+
+	// GenesisDifficulty is the difficulty of the Genesis block.
+	GenesisDifficulty *big.Int `json:"genesisDifficulty"`
+
+	// MinimumDifficulty is the minimum that the difficulty may ever be.
+	MinimumDifficulty *big.Int `json:"minimumDifficulty"`
+
+	// DifficultyBoundDivisor is the bound divisor of the difficulty,
+	// used in the update calculations.
+	DifficultyBoundDivisor *big.Int `json:"difficultyBoundDivisor"`
+
+	// DurationLimit is the decision boundary on the blocktime duration used to
+	// determine whether difficulty should go up or not.
+	DurationLimit *big.Int `json:"durationLimit"`
+
+	// AllowedFutureBlockTime is the number of seconds
+	// a block's timestamp can have beyond the current timestamp
+	AllowedFutureBlockTime int64 `json:"allowedFutureBlockTime"`
+
+	// MaxBlockNonTxsSize is the maximum size
+	// of the non-transactional data a block
+	// can have (headers, signature, hash).
+	MaxBlockNonTxsSize int64 `json:"maxBlockNonTxsSize"`
+
+	// MaxBlockTxsSize is the maximum size of
+	// transactions that can fit in a block
+	MaxBlockTxsSize int64 `json:"maxBlockTxsSize"`
+
+	// MaximumExtraDataSize is the size of extra data a block can contain.
+	MaximumExtraDataSize uint64 `json:"maximumExtraDataSize"`
+
+	// FeePerByte is the amount to be paid
+	// as fee for a single byte.
+	FeePerByte decimal.Decimal `json:"feePerByte"`
+
+	// TxTTL is the number of days a transaction
+	// can last for in the pool
+	TxTTL int `json:"txTTL"`
+
+	// Forks is the schedule of protocol upgrades.
+	// Rules introduced by an upgrade must only be
+	// enforced on blocks for which IsActive is true.
+	// Use At to get the parameters of a block.
+	Forks []Fork `json:"forks"`
+
+	// atCache stores the results of At by the
+	// forks whose parameters were applied
+	atCache *sync.Map
+}
+
+// DefaultChainSpec returns the chain
+// specification of the main network
+func DefaultChainSpec() *ChainSpec {
+	return &ChainSpec{
+		ConsensusEngine:        ConsensusEngine,
+		GenesisDifficulty:      new(big.Int).Set(GenesisDifficulty),
+		MinimumDifficulty:      new(big.Int).Set(MinimumDifficulty),
+		DifficultyBoundDivisor: new(big.Int).Set(DifficultyBoundDivisor),
+		DurationLimit:          new(big.Int).Set(DurationLimit),
+		AllowedFutureBlockTime: int64(AllowedFutureBlockTime / time.Second),
+		MaxBlockNonTxsSize:     MaxBlockNonTxsSize,
+		MaxBlockTxsSize:        MaxBlockTxsSize,
+		MaximumExtraDataSize:   MaximumExtraDataSize,
+		FeePerByte:             FeePerByte,
+		TxTTL:                  TxTTL,
+		Forks:                  append([]Fork{}, Forks...),
+		atCache:                &sync.Map{},
+	}
+}
+
+// GetAllowedFutureBlockTime returns AllowedFutureBlockTime
+// as a duration
+func (s *ChainSpec) GetAllowedFutureBlockTime() time.Duration {
+	return time.Duration(s.AllowedFutureBlockTime) * time.Second
+}
+
+// IsActive checks whether the named fork is
+// scheduled and active at the given block number
+func (s *ChainSpec) IsActive(fork string, blockNumber uint64) bool {
+	for _, f := range s.Forks {
+		if f.Name == fork {
+			return blockNumber >= f.ActivationHeight
+		}
+	}
+	return false
+}
+
+// At returns the chain specification that applies to
+// the block with the given number. The parameters changed
+// by the forks active at that number are applied in
+// order of activation height.
+func (s *ChainSpec) At(blockNumber uint64) *ChainSpec {
+
+	var active []Fork
+	for _, f := range s.Forks {
+		if len(f.Params) > 0 && s.IsActive(f.Name, blockNumber) {
+			active = append(active, f)
+		}
+	}
+
+	if len(active) == 0 {
+		return s
+	}
+
+	sort.SliceStable(active, func(i, j int) bool {
+		return active[i].ActivationHeight < active[j].ActivationHeight
+	})
+
+	// The result only changes when another fork is
+	// activated, so it is cached by the active forks
+	var key string
+	for _, f := range active {
+		key += fmt.Sprintf("%s:%d;", f.Name, f.ActivationHeight)
+	}
+	if s.atCache != nil {
+		if spec, ok := s.atCache.Load(key); ok {
+			return spec.(*ChainSpec)
+		}
+	}
+
+	spec := s.copy()
+	for _, f := range active {
+		// The params of the forks are checked by
+		// Validate, so decoding cannot fail
+		json.Unmarshal(f.Params, spec)
+	}
+	spec.Forks = s.Forks
+
+	if s.atCache != nil {
+		s.atCache.Store(key, spec)
+	}
+
+	return spec
+}
+
+// copy returns a deep copy of the specification
+func (s *ChainSpec) copy() *ChainSpec {
+	spec := *s
+	spec.GenesisDifficulty = new(big.Int).Set(s.GenesisDifficulty)
+	spec.MinimumDifficulty = new(big.Int).Set(s.MinimumDifficulty)
+	spec.DifficultyBoundDivisor = new(big.Int).Set(s.DifficultyBoundDivisor)
+	spec.DurationLimit = new(big.Int).Set(s.DurationLimit)
+	spec.Forks = append([]Fork{}, s.Forks...)
+	spec.atCache = nil
+	return &spec
+}
+
+// Validate checks the parameters of the specification
+// and the parameters it has after each fork
+func (s *ChainSpec) Validate() error {
+
+	if err := s.validateParams(); err != nil {
+		return err
+	}
+
+	seen := make(map[string]struct{})
+	for i, f := range s.Forks {
+		if f.Name == "" {
+			return fmt.Errorf("fork %d: name is required", i)
+		}
+		if _, ok := seen[f.Name]; ok {
+			return fmt.Errorf("fork %d: name '%s' is already scheduled", i, f.Name)
+		}
+		if f.ActivationHeight == 0 {
+			return fmt.Errorf("fork %d: activation height must be greater than zero", i)
+		}
+		if len(f.Params) > 0 {
+			if err := json.Unmarshal(f.Params, s.copy()); err != nil {
+				return fmt.Errorf("fork %d: failed to decode params: %s", i, err)
+			}
+			if err := s.At(f.ActivationHeight).validateParams(); err != nil {
+				return fmt.Errorf("fork %d: %s", i, err)
+			}
+		}
+		seen[f.Name] = struct{}{}
+	}
+
+	return nil
+}
+
+// validateParams checks the consensus parameters
+func (s *ChainSpec) validateParams() error {
+	if s.ConsensusEngine == "" {
+		return fmt.Errorf("consensusEngine is required")
+	}
+	for _, p := range []struct {
+		name  string
+		value *big.Int
+	}{
+		{"genesisDifficulty", s.GenesisDifficulty},
+		{"minimumDifficulty", s.MinimumDifficulty},
+		{"difficultyBoundDivisor", s.DifficultyBoundDivisor},
+		{"durationLimit", s.DurationLimit},
+	} {
+		if p.value == nil || p.value.Sign() <= 0 {
+			return fmt.Errorf("%s must be greater than zero", p.name)
+		}
+	}
+	if s.MaxBlockTxsSize <= 0 {
+		return fmt.Errorf("maxBlockTxsSize must be greater than zero")
+	}
+	if s.FeePerByte.Sign() < 0 {
+		return fmt.Errorf("feePerByte must not be negative")
+	}
+	if s.TxTTL <= 0 {
+		return fmt.Errorf("txTTL must be greater than zero")
+	}
+	return nil
+}
+
+// LoadChainSpec reads a chain specification from a JSON
+// file. Parameters not set in the file take the values
+// of the main network. The main network specification is
+// returned if the file does not exist.
+func LoadChainSpec(path string) (*ChainSpec, error) {
+
+	spec := DefaultChainSpec()
+
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return spec, nil
+		}
+		return nil, err
+	}
+
+	if err := json.Unmarshal(data, spec); err != nil {
+		return nil, fmt.Errorf("failed to decode chain spec: %s", err)
+	}
+
+	if err := spec.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid chain spec: %s", err)
+	}
+
+	return spec, nil
+}
@@ -50,6 +50,10 @@ var (
 	// MinimumDifficulty is the minimum that the difficulty may ever be.
 	MinimumDifficulty = big.NewInt(100000)
 
+	// ConsensusEngine is the name of the
+	// consensus engine of the main network
+	ConsensusEngine = "blakimoto"
+
 	// MinimumDurationIncrease is the minimum percent increase
 	// a block's time can be when compared to its parent's
 	MinimumDurationIncrease = big.NewFloat(2)
@@ -66,6 +70,34 @@ var (
 	// that can be added to the transaction pool at
 	// any given time.
 	PoolCapacity = int64(10000)
+
+	// PoolMaxByteSize is the max. total byte size of
+	// the transactions in the transaction pool
+	PoolMaxByteSize = int64(10 * 1024 * 1024)
+
+	// PoolReplaceFeeBump is the minimum percentage by
+	// which the fee rate of a transaction must exceed the
+	// fee rate of a pooled transaction with the same
+	// sender and nonce in order to replace it.
+	PoolReplaceFeeBump = int64(10)
+
+	// PoolMaxQueuedPerSender is the max. number of
+	// transactions of a sender that can wait in the
+	// pool for a nonce gap to be filled.
+	PoolMaxQueuedPerSender = 16
+
+	// PoolJournalFileName is the name of the file, in the
+	// network data directory, where the transactions of
+	// the pool are recorded to survive restarts.
+	PoolJournalFileName = "txpool.journal"
+
+	// DefaultTxsByAddressLimit is the default number of
+	// transactions returned per page of an address history
+	DefaultTxsByAddressLimit = 20
+
+	// MaxTxsByAddressLimit is the max. number of transactions
+	// returned per page of an address history
+	MaxTxsByAddressLimit = 100
 )
 
 // Engine parameters
@@ -75,3 +107,34 @@ var (
 	// the block manager
 	QueueProcessorInterval = 1 * time.Second
 )
+
+// Fork parameters
+var (
+	// Forks is the schedule of the protocol
+	// upgrades of the main network
+	Forks = []Fork{}
+)
+
+// Fork names. The rule introduced by a fork only
+// applies to blocks at or above the activation
+// height the chain specification schedules it at.
+const (
+	// ForkFutureBlockTime makes header validation use the
+	// allowed future block time of the chain specification
+	// instead of a fixed 15 seconds
+	ForkFutureBlockTime = "futureBlockTime"
+
+	// ForkNonZeroTransfer requires balance transactions
+	// to transfer a value greater than zero
+	ForkNonZeroTransfer = "nonZeroTransfer"
+
+	// ForkFastDifficultyDrop reduces the difficulty by twice
+	// the usual adjustment when the time between a block and
+	// its parent is at least twice the duration limit
+	ForkFastDifficultyDrop = "fastDifficultyDrop"
+
+	// ForkNoEmptyAccounts prevents a transaction that
+	// transfers no value from creating the account
+	// of its recipient
+	ForkNoEmptyAccounts = "noEmptyAccounts"
+)
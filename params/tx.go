@@ -12,4 +12,32 @@ var (
 	// TxTTL is the number of days a transaction
 	// can last for in the pool
 	TxTTL = 7
+
+	// MaxMultiSigPubKeys is the max. number of
+	// signers of a multi-signature account
+	MaxMultiSigPubKeys = 20
+
+	// LockTimeThreshold is the lock time value below
+	// which a transaction's lock time is interpreted as
+	// a block number. Values at or above it are unix
+	// timestamps.
+	LockTimeThreshold = uint64(500000000)
+
+	// MedianTimeBlocks is the number of previous blocks
+	// whose timestamps are used to compute the median
+	// time a timestamp lock time is compared with.
+	MedianTimeBlocks = 11
+
+	// NameRegistrationPeriod is the number of blocks
+	// a name registration or renewal lasts for
+	NameRegistrationPeriod = uint64(525600)
+
+	// FeeEstimateBlocks is the number of recent blocks
+	// whose transactions are used to estimate fees
+	FeeEstimateBlocks = 20
+
+	// DefaultFeeEstimateTargetBlocks is the number of blocks
+	// within which a transaction is expected to be included
+	// when no target is given for a fee estimate
+	DefaultFeeEstimateTargetBlocks = 2
 )
@@ -1,6 +1,7 @@
 package core
 
 import (
+	"github.com/ellcrys/elld/types"
 	"github.com/ellcrys/elld/util"
 )
 
@@ -10,10 +11,14 @@ const (
 
 	// AccountTypeRepo represents a repo account
 	AccountTypeRepo
+
+	// AccountTypeMultiSig represents a multi-signature account
+	AccountTypeMultiSig
 )
 
 // AccountInfo represents the data specific to a regular account
 type AccountInfo struct {
+	MultiSig *types.MultiSig `json:"multiSig,omitempty" msgpack:"multiSig"`
 }
 
 // Account represents an entity on the network.
@@ -49,3 +54,28 @@ func (a *Account) GetNonce() uint64 {
 func (a *Account) IncrNonce() {
 	a.Nonce++
 }
+
+// GetType gets the account type
+func (a *Account) GetType() int32 {
+	return a.Type
+}
+
+// GetMultiSig gets the description of a
+// multi-signature account. It returns nil if
+// the account is not a multi-signature account.
+func (a *Account) GetMultiSig() *types.MultiSig {
+	if a.AccountInfo == nil {
+		return nil
+	}
+	return a.AccountInfo.MultiSig
+}
+
+// SetMultiSig turns the account into a
+// multi-signature account
+func (a *Account) SetMultiSig(multiSig *types.MultiSig) {
+	a.Type = AccountTypeMultiSig
+	if a.AccountInfo == nil {
+		a.AccountInfo = &AccountInfo{}
+	}
+	a.AccountInfo.MultiSig = multiSig
+}
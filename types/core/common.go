@@ -2,10 +2,21 @@ package core
 
 import (
 	"fmt"
+	"regexp"
 
 	"github.com/vmihailenco/msgpack"
 )
 
+// validNameRegexp matches names that can be registered.
+// Names are between 3 and 32 characters long and can
+// only include lowercase letters, digits and hyphens.
+var validNameRegexp = regexp.MustCompile("^[a-z0-9][a-z0-9-]{2,31}$")
+
+// IsValidName checks whether a name can be registered
+func IsValidName(name string) bool {
+	return validNameRegexp.MatchString(name)
+}
+
 func fieldError(field, err string) error {
 	return fmt.Errorf(fmt.Sprintf("field:%s, error:%s", field, err))
 }
@@ -42,9 +42,16 @@ var (
 	// ErrBestChainUnknown means the best/main chain is yet to be determined
 	ErrBestChainUnknown = fmt.Errorf("best chain unknown")
 
+	// ErrStatePruned means the state as at a block is
+	// unavailable because the block is below the prune horizon
+	ErrStatePruned = fmt.Errorf("state pruned: block is below the prune horizon")
+
 	// ErrTxNotFound means a transaction was not found
 	ErrTxNotFound = fmt.Errorf("transaction not found")
 
+	// ErrNameNotFound means a name is not registered
+	ErrNameNotFound = fmt.Errorf("name not found")
+
 	// ErrDecodeFailed means an attempt to decode data failed
 	ErrDecodeFailed = func(msg string) error {
 		if msg != "" {
@@ -59,6 +66,14 @@ var (
 	// ErrChainParentBlockNotFound means a chain's parent block was not found
 	ErrChainParentBlockNotFound = fmt.Errorf("chain parent block not found")
 
+	// ErrCheckpointMismatch means a block at a checkpoint
+	// height does not match the checkpoint hash
+	ErrCheckpointMismatch = fmt.Errorf("block does not match checkpoint")
+
+	// ErrCheckpointConflict means a block belongs to a branch
+	// that forks off the main chain before a checkpoint
+	ErrCheckpointConflict = fmt.Errorf("branch conflicts with a checkpoint")
+
 	// ErrAbortedDueToSyncDisablement means an operation
 	// was aborted due to block synchronization being disabled
 	ErrAbortedDueToSyncDisablement = fmt.Errorf("aborted. Synchronization has been disabled")
@@ -38,4 +38,9 @@ const (
 	// EventBlockProcessed describes an event about
 	// a processed block
 	EventBlockProcessed = "event.blockProcessed"
+
+	// EventReOrgRejected describes an event about a
+	// reorganization that was rejected because it would
+	// roll back more blocks than the maximum reorg depth
+	EventReOrgRejected = "event.reOrgRejected"
 )
@@ -6,6 +6,7 @@ import (
 
 	"github.com/btcsuite/btcutil/base58"
 	"github.com/ellcrys/elld/crypto"
+	"github.com/ellcrys/elld/types"
 	"github.com/ellcrys/elld/util"
 )
 
@@ -29,6 +30,27 @@ var (
 
 	// TxTypeAlloc represents a transaction to alloc coins to an account
 	TxTypeAlloc int64 = 0x2
+
+	// TxTypeMultiSigRegister represents a transaction that registers
+	// a multi-signature account and sends value to it
+	TxTypeMultiSigRegister int64 = 0x3
+
+	// TxTypeMultiSigSpend represents a transaction from a
+	// multi-signature account to another account
+	TxTypeMultiSigSpend int64 = 0x4
+
+	// TxTypeNameRegister represents a transaction that
+	// registers a name that resolves to the recipient
+	TxTypeNameRegister int64 = 0x5
+
+	// TxTypeNameRenew represents a transaction that extends
+	// the registration of a name and makes it resolve
+	// to the recipient
+	TxTypeNameRenew int64 = 0x6
+
+	// TxTypeNameTransfer represents a transaction that
+	// transfers the ownership of a name to the recipient
+	TxTypeNameTransfer int64 = 0x7
 )
 
 // Base58CheckVersionTxPayload is the base58 encode version adopted
@@ -54,6 +76,24 @@ type Transaction struct {
 	InvokeArgs   *InvokeArgs `json:"invokeArgs,omitempty" msgpack:"invokeArgs"`
 	Sig          []byte      `json:"sig" msgpack:"sig"`
 	Hash         util.Hash   `json:"hash" msgpack:"hash"`
+
+	// LockTime is the block number or unix time
+	// before which the transaction cannot be included
+	// in a block. See params.LockTimeThreshold.
+	LockTime uint64 `json:"lockTime,omitempty" msgpack:"lockTime"`
+
+	// Name is the name registered, renewed or
+	// transferred by a name transaction
+	Name util.String `json:"name,omitempty" msgpack:"name"`
+
+	// MultiSig describes the multi-signature account
+	// registered by a TxTypeMultiSigRegister transaction
+	// or spent from by a TxTypeMultiSigSpend transaction
+	MultiSig *types.MultiSig `json:"multiSig,omitempty" msgpack:"multiSig"`
+
+	// MultiSigSigs are the signatures of the signers
+	// of a TxTypeMultiSigSpend transaction
+	MultiSigSigs []*types.MultiSigSignature `json:"multiSigSigs,omitempty" msgpack:"multiSigSigs"`
 }
 
 // NewTransaction creates a new transaction
@@ -71,6 +111,30 @@ func NewTransaction(txType int64, nonce uint64, to util.String,
 	return
 }
 
+// NewMultiSigSpendTx creates a new TxTypeMultiSigSpend
+// transaction signed by the given signers
+func NewMultiSigSpendTx(nonce uint64, to util.String, multiSig *types.MultiSig,
+	value util.String, fee util.String, timestamp int64,
+	signers ...*crypto.Key) (tx *Transaction) {
+	tx = new(Transaction)
+	tx.Type = TxTypeMultiSigSpend
+	tx.Nonce = nonce
+	tx.To = to
+	tx.From = multiSig.Address()
+	tx.MultiSig = multiSig
+	tx.Value = value
+	tx.Timestamp = timestamp
+	tx.Fee = fee
+	tx.Hash = tx.ComputeHash()
+
+	for _, signer := range signers {
+		if err := tx.AddMultiSigSignature(signer); err != nil {
+			panic(err)
+		}
+	}
+	return
+}
+
 // NewTx creates a new, signed transaction
 func NewTx(txType int64, nonce uint64, to util.String, senderKey *crypto.Key, value util.String, fee util.String, timestamp int64) (tx *Transaction) {
 	tx = new(Transaction)
@@ -92,6 +156,42 @@ func NewTx(txType int64, nonce uint64, to util.String, senderKey *crypto.Key, va
 	return
 }
 
+// AddMultiSigSignature signs the transaction with
+// the key of a signer of a multi-signature account
+// and adds the signature to the transaction
+func (tx *Transaction) AddMultiSigSignature(signer *crypto.Key) error {
+	sig, err := TxSign(tx, signer.PrivKey().Base58())
+	if err != nil {
+		return err
+	}
+	tx.MultiSigSigs = append(tx.MultiSigSigs, &types.MultiSigSignature{
+		PubKey: util.String(signer.PubKey().Base58()),
+		Sig:    sig,
+	})
+	return nil
+}
+
+// GetName gets the name of a name transaction
+func (tx *Transaction) GetName() util.String {
+	return tx.Name
+}
+
+// GetLockTime gets the lock time
+func (tx *Transaction) GetLockTime() uint64 {
+	return tx.LockTime
+}
+
+// GetMultiSig gets the multi-signature account description
+func (tx *Transaction) GetMultiSig() *types.MultiSig {
+	return tx.MultiSig
+}
+
+// GetMultiSigSignatures gets the signatures
+// of the signers of a multi-signature account
+func (tx *Transaction) GetMultiSigSignatures() []*types.MultiSigSignature {
+	return tx.MultiSigSigs
+}
+
 // SetFrom sets the sender
 func (tx *Transaction) SetFrom(from util.String) {
 	tx.From = from
@@ -200,6 +300,21 @@ func (tx *Transaction) GetBytesNoHashAndSig() []byte {
 		tx.Value,
 	}
 
+	// Multi-signature fields, the lock time and the name
+	// are only included when set so that the bytes of
+	// other transactions remain unchanged
+	if tx.MultiSig != nil {
+		data = append(data, tx.MultiSig.Bytes())
+	}
+
+	if tx.LockTime > 0 {
+		data = append(data, tx.LockTime)
+	}
+
+	if tx.Name != "" {
+		data = append(data, tx.Name)
+	}
+
 	return getBytes(data)
 }
 
@@ -226,6 +341,18 @@ func (tx *Transaction) Bytes() []byte {
 		tx.Value,
 	}
 
+	if tx.MultiSig != nil {
+		data = append(data, tx.MultiSig.Bytes(), tx.MultiSigSigs)
+	}
+
+	if tx.LockTime > 0 {
+		data = append(data, tx.LockTime)
+	}
+
+	if tx.Name != "" {
+		data = append(data, tx.Name)
+	}
+
 	return getBytes(data)
 }
 
@@ -256,6 +383,18 @@ func (tx *Transaction) GetSizeNoFee() int64 {
 		tx.Value,
 	}
 
+	if tx.MultiSig != nil {
+		data = append(data, tx.MultiSig.Bytes(), tx.MultiSigSigs)
+	}
+
+	if tx.LockTime > 0 {
+		data = append(data, tx.LockTime)
+	}
+
+	if tx.Name != "" {
+		data = append(data, tx.Name)
+	}
+
 	return int64(len(getBytes(data)))
 }
 
@@ -0,0 +1,114 @@
+package core
+
+import (
+	"crypto/sha256"
+	"fmt"
+
+	"github.com/ellcrys/elld/types"
+	"github.com/ellcrys/elld/util"
+)
+
+// TxProof is a merkle proof of the inclusion
+// of a transaction in a block.
+type TxProof struct {
+
+	// BlockHash is the hash of the block
+	// that includes the transaction
+	BlockHash util.Hash `json:"blockHash" msgpack:"blockHash"`
+
+	// Index is the position of the transaction
+	// in the block's list of transactions
+	Index int `json:"index" msgpack:"index"`
+
+	// Branch are the hashes adjacent to the path of the
+	// transaction. They are ordered from the leaf up to
+	// the level just below the transactions root.
+	Branch []util.Hash `json:"branch" msgpack:"branch"`
+}
+
+// txLeaves returns the leaves of the
+// transaction tree of a block.
+// The last leaf is duplicated when the
+// number of leaves is odd.
+func txLeaves(txs []types.Transaction) []util.Hash {
+	leaves := make([]util.Hash, 0, len(txs)+1)
+	for _, tx := range txs {
+		leaves = append(leaves, util.BytesToHash(util.Blake2b256(tx.GetHash().Bytes())))
+	}
+	if len(leaves)%2 == 1 {
+		leaves = append(leaves, leaves[len(leaves)-1])
+	}
+	return leaves
+}
+
+// hashTxNode returns the hash of an internal node
+// of the transaction tree. Unlike the leaves, internal
+// nodes are hashed with sha256.
+func hashTxNode(left, right util.Hash) util.Hash {
+	sum := sha256.Sum256(append(left.Bytes(), right.Bytes()...))
+	return util.BytesToHash(sum[:])
+}
+
+// NewTxProof creates a proof of the inclusion of
+// the transaction at the given index in a block.
+func NewTxProof(block types.Block, index int) (*TxProof, error) {
+
+	txs := block.GetTransactions()
+	if index < 0 || index >= len(txs) {
+		return nil, fmt.Errorf("transaction index out of range")
+	}
+
+	proof := &TxProof{
+		BlockHash: block.GetHash(),
+		Index:     index,
+		Branch:    []util.Hash{},
+	}
+
+	// Walk up the tree, recording the sibling of the
+	// node on the path of the transaction at every
+	// level. A node without a sibling is hashed with
+	// itself.
+	nodes := txLeaves(txs)
+	for idx := index; len(nodes) > 1; idx /= 2 {
+		sibling := idx ^ 1
+		if sibling >= len(nodes) {
+			sibling = idx
+		}
+		proof.Branch = append(proof.Branch, nodes[sibling])
+
+		var parents []util.Hash
+		for i := 0; i < len(nodes); i += 2 {
+			right := i + 1
+			if right >= len(nodes) {
+				right = i
+			}
+			parents = append(parents, hashTxNode(nodes[i], nodes[right]))
+		}
+		nodes = parents
+	}
+
+	return proof, nil
+}
+
+// VerifyTxProof checks whether a proof shows that a
+// transaction is included in the block with the given
+// header.
+func VerifyTxProof(header types.Header, tx types.Transaction, proof *TxProof) bool {
+
+	if proof == nil || proof.Index < 0 || len(proof.Branch) > 64 {
+		return false
+	}
+
+	node := util.BytesToHash(util.Blake2b256(tx.GetHash().Bytes()))
+	idx := proof.Index
+	for _, sibling := range proof.Branch {
+		if idx%2 == 0 {
+			node = hashTxNode(node, sibling)
+		} else {
+			node = hashTxNode(sibling, node)
+		}
+		idx /= 2
+	}
+
+	return idx == 0 && node.Equal(header.GetTransactionsRoot())
+}
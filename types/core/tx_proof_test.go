@@ -0,0 +1,89 @@
+package core
+
+import (
+	"github.com/ellcrys/elld/util"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("TxProof", func() {
+
+	var makeBlock = func(numTxs int, txRoot util.Hash) *Block {
+		block := &Block{
+			Header: &Header{TransactionsRoot: txRoot},
+			Hash:   util.StrToHash("block_hash"),
+		}
+		for i := 1; i <= numTxs; i++ {
+			block.Transactions = append(block.Transactions, &Transaction{Hash: util.Hash{byte(i)}})
+		}
+		return block
+	}
+
+	Describe(".NewTxProof", func() {
+		It("should return error if index is out of range", func() {
+			block := makeBlock(3, util.EmptyHash)
+			_, err := NewTxProof(block, 3)
+			Expect(err).ToNot(BeNil())
+			Expect(err.Error()).To(Equal("transaction index out of range"))
+			_, err = NewTxProof(block, -1)
+			Expect(err).ToNot(BeNil())
+		})
+
+		It("should return a proof with the block hash and index", func() {
+			block := makeBlock(3, util.EmptyHash)
+			proof, err := NewTxProof(block, 2)
+			Expect(err).To(BeNil())
+			Expect(proof.BlockHash).To(Equal(block.GetHash()))
+			Expect(proof.Index).To(Equal(2))
+			Expect(proof.Branch).To(HaveLen(2))
+		})
+	})
+
+	Describe(".VerifyTxProof", func() {
+
+		Context("with one transaction", func() {
+			var block *Block
+
+			BeforeEach(func() {
+				block = makeBlock(1, util.Hash{156, 154, 9, 74, 31, 223, 70, 252, 11, 23, 186, 105, 109, 15, 117, 209, 152, 67, 192, 248, 216, 246, 196, 246, 146, 24, 82, 66, 214, 92, 113, 11})
+			})
+
+			It("should return true", func() {
+				proof, err := NewTxProof(block, 0)
+				Expect(err).To(BeNil())
+				Expect(VerifyTxProof(block.GetHeader(), block.Transactions[0], proof)).To(BeTrue())
+			})
+		})
+
+		Context("with three transactions", func() {
+			var block *Block
+
+			BeforeEach(func() {
+				block = makeBlock(3, util.Hash{179, 119, 234, 153, 129, 18, 120, 244, 3, 215, 158, 67, 72, 75, 144, 35, 35, 189, 223, 225, 10, 31, 80, 109, 197, 31, 102, 40, 91, 8, 75, 222})
+			})
+
+			It("should return true for every transaction", func() {
+				for i, tx := range block.Transactions {
+					proof, err := NewTxProof(block, i)
+					Expect(err).To(BeNil())
+					Expect(VerifyTxProof(block.GetHeader(), tx, proof)).To(BeTrue())
+				}
+			})
+
+			It("should return false if proof belongs to another transaction", func() {
+				proof, _ := NewTxProof(block, 0)
+				Expect(VerifyTxProof(block.GetHeader(), block.Transactions[1], proof)).To(BeFalse())
+			})
+
+			It("should return false if index does not match the transaction", func() {
+				proof, _ := NewTxProof(block, 0)
+				proof.Index = 1
+				Expect(VerifyTxProof(block.GetHeader(), block.Transactions[0], proof)).To(BeFalse())
+			})
+
+			It("should return false if proof is nil", func() {
+				Expect(VerifyTxProof(block.GetHeader(), block.Transactions[0], nil)).To(BeFalse())
+			})
+		})
+	})
+})
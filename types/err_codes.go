@@ -6,6 +6,8 @@ const (
 	ErrCodeListAccountFailed = 30000
 	// ErrCodeAccountNotFound for missing account
 	ErrCodeAccountNotFound = 30001
+	// ErrCodeNameNotFound for missing or expired name
+	ErrCodeNameNotFound = 30002
 )
 
 // RPC package error codes
@@ -26,6 +28,8 @@ const (
 	ErrCodeTransactionNotFound = 50001
 	// ErrCodeBlockQuery for non-specific block query errors
 	ErrCodeBlockQuery = 50002
+	// ErrCodeBlockSealFailed for when a block could not be sealed
+	ErrCodeBlockSealFailed = 50003
 )
 
 // General error codes
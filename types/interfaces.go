@@ -5,6 +5,7 @@ import (
 
 	"github.com/ellcrys/elld/crypto"
 	"github.com/ellcrys/elld/elldb"
+	"github.com/ellcrys/elld/params"
 
 	"github.com/olebedev/emitter"
 
@@ -19,8 +20,9 @@ type Chainer interface {
 	// GetStore returns the store
 	GetStore() ChainStorer
 
-	// NewStateTree returns a new tree
-	NewStateTree(opts ...CallOp) (Tree, error)
+	// NewStateTree returns a state tree containing
+	// the accounts known to the chain
+	NewStateTree(opts ...CallOp) (StateTree, error)
 
 	// Current gets the header of the tip block
 	Current(opts ...CallOp) (Header, error)
@@ -43,9 +45,20 @@ type Chainer interface {
 	// GetAccount gets an account
 	GetAccount(address util.String, opts ...CallOp) (Account, error)
 
+	// GetName gets a name record
+	GetName(name util.String, opts ...CallOp) (*NameRecord, error)
+
 	// PutTransactions stores a collection of transactions
 	PutTransactions(txs []Transaction, blockNumber uint64, opts ...CallOp) error
 
+	// GetAddressTxPointers gets pointers to transactions sent
+	// or received by an address. Only pointers positioned after
+	// the cursor (if provided) in the direction of iteration are
+	// returned. Iteration begins from the oldest transaction
+	// if ascending is true, otherwise the newest.
+	GetAddressTxPointers(address util.String, cursor *TxPointer, limit int,
+		ascending bool, opts ...CallOp) ([]*TxPointer, error)
+
 	// GetTransaction gets a transaction by hash
 	GetTransaction(hash util.Hash, opts ...CallOp) (Transaction, error)
 
@@ -86,18 +99,53 @@ type ChainStorer interface {
 	// GetTransaction gets a transaction (by hash) belonging to the chain
 	GetTransaction(hash util.Hash, opts ...CallOp) (Transaction, error)
 
+	// GetTransactionBlock gets the block that includes a transaction (by hash)
+	GetTransactionBlock(hash util.Hash, opts ...CallOp) (Block, error)
+
 	// CreateAccount creates an account on a target block
 	CreateAccount(targetBlockNum uint64, account Account, opts ...CallOp) error
 
 	// GetAccount gets an account
 	GetAccount(address util.String, opts ...CallOp) (Account, error)
 
+	// GetAccountVersions gets the versions of an account
+	// in ascending order of the block they were stored at
+	GetAccountVersions(address util.String, opts ...CallOp) ([]*AccountVersion, error)
+
 	// GetAccounts gets an account
 	GetAccounts(opts ...CallOp) ([]Account, error)
 
+	// GetName gets the latest version of a name record
+	GetName(name util.String, opts ...CallOp) (*NameRecord, error)
+
+	// GetNames gets the latest version of all name records
+	GetNames(opts ...CallOp) ([]*NameRecord, error)
+
 	// PutTransactions stores a collection of transactions
 	PutTransactions(txs []Transaction, blockNumber uint64, opts ...CallOp) error
 
+	// GetAddressTxPointers gets pointers to transactions sent
+	// or received by an address. Only pointers positioned after
+	// the cursor (if provided) in the direction of iteration are
+	// returned. Iteration begins from the oldest transaction
+	// if ascending is true, otherwise the newest.
+	GetAddressTxPointers(address util.String, cursor *TxPointer, limit int,
+		ascending bool, opts ...CallOp) ([]*TxPointer, error)
+
+	// BuildAddressIndex indexes the transactions of all the
+	// stored blocks by address, unless already done
+	BuildAddressIndex(opts ...CallOp) error
+
+	// ResetAddressIndex deletes the address index
+	ResetAddressIndex(opts ...CallOp) error
+
+	// SetPruneHorizon stores the number of the block at or
+	// before which old versions of accounts may have been pruned
+	SetPruneHorizon(blockNumber uint64, opts ...CallOp) error
+
+	// GetPruneHorizon gets the prune horizon of the chain
+	GetPruneHorizon(opts ...CallOp) (uint64, error)
+
 	// PutMinedBlock stores a brief information about a
 	// block that was created by the blockchain's coinbase key
 	PutMinedBlock(block Block, opts ...CallOp) error
@@ -153,6 +201,12 @@ type Blockchain interface {
 	// SetDB sets the database
 	SetDB(elldb.DB)
 
+	// GetChainSpec gets the chain specification
+	GetChainSpec() *params.ChainSpec
+
+	// GetConsensusEngine gets the consensus engine
+	GetConsensusEngine() ConsensusEngine
+
 	// OrphanBlocks gets a reader for the orphan cache
 	OrphanBlocks() CacheReader
 
@@ -186,6 +240,9 @@ type Blockchain interface {
 	// GetAccountNonce gets the nonce of an account
 	GetAccountNonce(address util.String, opts ...CallOp) (uint64, error)
 
+	// GetName gets a name record
+	GetName(name util.String, opts ...CallOp) (*NameRecord, error)
+
 	// GetLocators fetches a list of blockhashes used to
 	// compare and sync the local chain with a remote chain.
 	GetLocators() ([]util.Hash, error)
@@ -194,6 +251,10 @@ type Blockchain interface {
 	// the transaction pool. These transactions must
 	// be suitable for inclusion in blocks.
 	SelectTransactions(maxSize int64) ([]Transaction, error)
+
+	// CheckCheckpoint checks whether a block with the
+	// given number and hash conflicts with a checkpoint
+	CheckCheckpoint(number uint64, hash util.Hash) error
 }
 
 // BlockMaker defines an interface providing the
@@ -212,6 +273,42 @@ type BlockMaker interface {
 
 	// IsMainChain checks whether a chain is the main chain
 	IsMainChain(ChainReaderFactory) bool
+
+	// GetChainSpec gets the chain specification
+	GetChainSpec() *params.ChainSpec
+
+	// GetConsensusEngine gets the consensus engine
+	GetConsensusEngine() ConsensusEngine
+
+	// GetTxPool gets the transaction pool
+	GetTxPool() TxPool
+}
+
+// ConsensusEngine defines an interface for
+// the consensus algorithm of a network
+type ConsensusEngine interface {
+
+	// VerifyHeader checks whether a header
+	// conforms to the consensus rules
+	VerifyHeader(header, parent Header, seal bool) error
+
+	// VerifySeal checks whether the seal
+	// of a header is valid
+	VerifySeal(header Header) error
+
+	// CalcDifficulty returns the difficulty a
+	// block should have given its parent
+	CalcDifficulty(header, parent Header) *big.Int
+
+	// Prepare initializes the consensus
+	// fields of a header
+	Prepare(chain ChainReaderFactory, header Header) error
+
+	// Seal seals a block and returns the sealed block.
+	// The engine decides how the block is sealed. It
+	// returns a nil block if stop is closed before the
+	// block is sealed.
+	Seal(block Block, stop <-chan struct{}) (Block, error)
 }
 
 // ChainReaderFactory defines an interface for reading a chain
@@ -321,6 +418,9 @@ type Account interface {
 	SetBalance(util.String)
 	GetNonce() uint64
 	IncrNonce()
+	GetType() int32
+	GetMultiSig() *MultiSig
+	SetMultiSig(*MultiSig)
 }
 
 // Transaction represents a transaction
@@ -346,6 +446,10 @@ type Transaction interface {
 	SetSenderPubKey(util.String)
 	GetSignature() []byte
 	SetSignature(sig []byte)
+	GetMultiSig() *MultiSig
+	GetMultiSigSignatures() []*MultiSigSignature
+	GetLockTime() uint64
+	GetName() util.String
 }
 
 // CallOp describes an interface to be used to define store method options
@@ -361,6 +465,14 @@ type Tree interface {
 	Root() util.Hash
 }
 
+// StateTree defines an authenticated tree
+// that commits to the world state
+type StateTree interface {
+	Set(key, value []byte)
+	Get(key []byte) (util.Hash, bool)
+	Root() util.Hash
+}
+
 // TxContainer represents a container
 // a container responsible for holding
 // and sorting transactions
@@ -379,12 +491,14 @@ type TxContainer interface {
 // TxPool represents a transactions pool
 type TxPool interface {
 	Put(tx Transaction) error
+	PutLocal(tx Transaction) error
 	Has(tx Transaction) bool
 	HasByHash(hash string) bool
 	Remove(txs ...Transaction)
 	ByteSize() int64
 	Size() int64
 	Container() TxContainer
+	QueueContainer() TxContainer
 	GetByHash(hash string) Transaction
 	GetByFrom(address util.String) []Transaction
 }
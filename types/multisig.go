@@ -0,0 +1,57 @@
+package types
+
+import (
+	"sort"
+
+	"github.com/ellcrys/elld/crypto"
+	"github.com/ellcrys/elld/util"
+)
+
+// MultiSig describes an M-of-N multi-signature
+// account. Funds of the account can only be spent
+// by a transaction signed by at least Threshold
+// of the public keys.
+type MultiSig struct {
+	Threshold uint32        `json:"threshold" msgpack:"threshold"`
+	PubKeys   []util.String `json:"pubKeys" msgpack:"pubKeys"`
+}
+
+// MultiSigSignature is a signature of
+// one of the signers of a multi-signature
+// account
+type MultiSigSignature struct {
+	PubKey util.String `json:"pubKey" msgpack:"pubKey"`
+	Sig    []byte      `json:"sig" msgpack:"sig"`
+}
+
+// Bytes returns the byte equivalent.
+// The public keys are sorted so that the
+// order they were provided in does not matter.
+func (m *MultiSig) Bytes() []byte {
+	pubKeys := make([]string, len(m.PubKeys))
+	for i, pk := range m.PubKeys {
+		pubKeys[i] = pk.String()
+	}
+	sort.Strings(pubKeys)
+	return util.ObjectToBytes([]interface{}{
+		m.Threshold,
+		pubKeys,
+	})
+}
+
+// Address returns the address of
+// the multi-signature account
+func (m *MultiSig) Address() util.String {
+	return crypto.MakeAddr(m.Bytes())
+}
+
+// HasPubKey checks whether a public
+// key is one of the signers
+func (m *MultiSig) HasPubKey(pubKey util.String) bool {
+	for _, pk := range m.PubKeys {
+		if pk.Equal(pubKey) {
+			return true
+		}
+	}
+	return false
+}
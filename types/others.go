@@ -153,3 +153,46 @@ type ConnectError string
 func (c ConnectError) Error() string {
 	return string(c)
 }
+
+// AccountVersion is the state of an account
+// as stored at a given block
+type AccountVersion struct {
+
+	// BlockNumber is the number of the block
+	// where this version of the account was stored
+	BlockNumber uint64
+
+	// Account is the account
+	Account Account
+}
+
+// TxPointer points to a transaction
+// included in a block
+type TxPointer struct {
+
+	// BlockNumber is the number of the block
+	// that includes the transaction
+	BlockNumber uint64
+
+	// Index is the position of the
+	// transaction in the block
+	Index uint64
+
+	// Hash is the hash of the transaction
+	Hash util.Hash
+}
+
+// NameRecord describes a registered name
+// and the address it resolves to
+type NameRecord struct {
+	Name      util.String `json:"name" msgpack:"name"`
+	Owner     util.String `json:"owner" msgpack:"owner"`
+	Address   util.String `json:"address" msgpack:"address"`
+	ExpiresAt uint64      `json:"expiresAt" msgpack:"expiresAt"`
+}
+
+// IsExpired checks whether the registration
+// has expired as at the given block number
+func (r *NameRecord) IsExpired(blockNumber uint64) bool {
+	return blockNumber > r.ExpiresAt
+}